@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/goamz/ec2"
+)
+
+// availabilityZoneCache remembers the availability zones of the region
+// so that validating several resources in one run only describes them
+// once.
+type availabilityZoneCache struct {
+	sync.Mutex
+	zones []string
+}
+
+// availabilityZones returns the names of the availability zones in the
+// client's region.
+func (c *AWSClient) availabilityZones() ([]string, error) {
+	c.azCache.Lock()
+	defer c.azCache.Unlock()
+
+	if c.azCache.zones != nil {
+		return c.azCache.zones, nil
+	}
+
+	log.Printf("[DEBUG] Describing availability zones")
+	resp, err := c.ec2conn().DescribeAvailabilityZones(ec2.NewFilter())
+	if err != nil {
+		return nil, fmt.Errorf("Error describing availability zones: %s", err)
+	}
+
+	zones := make([]string, 0, len(resp.Zones))
+	for _, z := range resp.Zones {
+		zones = append(zones, z.Name)
+	}
+	sort.Strings(zones)
+
+	c.azCache.zones = zones
+	return zones, nil
+}
+
+// validateAvailabilityZones returns an error if any of the given zones
+// isn't in the client's region. This catches a zone from another region
+// before the API call, which would otherwise fail confusingly.
+func validateAvailabilityZones(c *AWSClient, zones []string) error {
+	if len(zones) == 0 {
+		return nil
+	}
+
+	valid, err := c.availabilityZones()
+	if err != nil {
+		return err
+	}
+
+	return checkAvailabilityZones(valid, zones)
+}
+
+func checkAvailabilityZones(valid, zones []string) error {
+	known := make(map[string]struct{}, len(valid))
+	for _, z := range valid {
+		known[z] = struct{}{}
+	}
+
+	var bad []string
+	for _, z := range zones {
+		if _, ok := known[z]; !ok {
+			bad = append(bad, z)
+		}
+	}
+
+	if len(bad) > 0 {
+		return fmt.Errorf(
+			"Availability zone(s) %s not found in this region. Valid zones are: %s",
+			strings.Join(bad, ", "), strings.Join(valid, ", "))
+	}
+
+	return nil
+}
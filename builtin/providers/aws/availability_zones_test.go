@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"testing"
+)
+
+func TestCheckAvailabilityZones(t *testing.T) {
+	valid := []string{"us-east-1a", "us-east-1b", "us-east-1d"}
+
+	cases := []struct {
+		Zones []string
+		Err   bool
+	}{
+		{
+			Zones: []string{"us-east-1a"},
+			Err:   false,
+		},
+		{
+			Zones: []string{"us-east-1a", "us-east-1d"},
+			Err:   false,
+		},
+		{
+			Zones: []string{"us-west-2a"},
+			Err:   true,
+		},
+		{
+			Zones: []string{"us-east-1a", "us-east-1c"},
+			Err:   true,
+		},
+	}
+
+	for i, tc := range cases {
+		err := checkAvailabilityZones(valid, tc.Zones)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: bad: %s", i, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mitchellh/goamz/autoscaling"
+	"github.com/mitchellh/goamz/ec2"
+	"github.com/mitchellh/goamz/elb"
+	"github.com/mitchellh/goamz/rds"
+	"github.com/mitchellh/goamz/s3"
+)
+
+// awsErrorCode returns the code of an error returned by one of the AWS
+// APIs, whichever client it came from, or "" if the error didn't come from
+// AWS at all, such as a network error.
+func awsErrorCode(err error) string {
+	switch e := err.(type) {
+	case *ec2.Error:
+		return e.Code
+	case *elb.Error:
+		return e.Code
+	case *autoscaling.Error:
+		return e.Code
+	case *rds.Error:
+		return e.Code
+	case *s3.Error:
+		return e.Code
+	case *queryError:
+		return e.Code
+	}
+
+	return ""
+}
+
+// isAWSErr returns true if err is an AWS error with the code.
+func isAWSErr(err error, code string) bool {
+	return err != nil && awsErrorCode(err) == code
+}
+
+// retryOnAwsCodes wraps f for resource.Retry so that only the AWS errors
+// with one of the codes are retried, along with errors that don't come
+// from AWS, such as network errors. Any other AWS error stops the retries
+// and is returned as it is.
+func retryOnAwsCodes(codes []string, f resource.RetryFunc) resource.RetryFunc {
+	return func() error {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		code := awsErrorCode(err)
+		if code == "" {
+			return err
+		}
+		for _, c := range codes {
+			if code == c {
+				log.Printf("[DEBUG] Retrying on %s: %s", code, err)
+				return err
+			}
+		}
+
+		return resource.RetryError{err}
+	}
+}
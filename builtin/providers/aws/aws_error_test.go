@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mitchellh/goamz/autoscaling"
+	"github.com/mitchellh/goamz/ec2"
+	"github.com/mitchellh/goamz/elb"
+	"github.com/mitchellh/goamz/rds"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func TestIsAWSErr(t *testing.T) {
+	cases := []struct {
+		Err  error
+		Code string
+		Is   bool
+	}{
+		{&ec2.Error{Code: "DependencyViolation"}, "DependencyViolation", true},
+		{&ec2.Error{Code: "DependencyViolation"}, "InvalidSubnetID.NotFound", false},
+		{&elb.Error{Code: "LoadBalancerNotFound"}, "LoadBalancerNotFound", true},
+		{&autoscaling.Error{Code: "ResourceInUse"}, "ResourceInUse", true},
+		{&rds.Error{Code: "DBSubnetGroupNotFoundFault"}, "DBSubnetGroupNotFoundFault", true},
+		{&s3.Error{Code: "NoSuchBucket"}, "NoSuchBucket", true},
+		{&queryError{Code: "Throttling"}, "Throttling", true},
+		{fmt.Errorf("DependencyViolation"), "DependencyViolation", false},
+		{nil, "", false},
+	}
+
+	for i, tc := range cases {
+		if isAWSErr(tc.Err, tc.Code) != tc.Is {
+			t.Fatalf("%d: expected %t for %#v", i, tc.Is, tc.Err)
+		}
+	}
+}
+
+func TestRetryOnAwsCodes(t *testing.T) {
+	cases := []struct {
+		Errors []error
+		Calls  int
+		Err    bool
+	}{
+		// Retried until it succeeds
+		{
+			Errors: []error{
+				&autoscaling.Error{Code: "ResourceInUse"},
+				&autoscaling.Error{Code: "ResourceInUse"},
+				nil,
+			},
+			Calls: 3,
+			Err:   false,
+		},
+
+		// Other AWS errors stop the retries
+		{
+			Errors: []error{
+				&autoscaling.Error{Code: "ResourceInUse"},
+				&autoscaling.Error{Code: "ValidationError"},
+			},
+			Calls: 2,
+			Err:   true,
+		},
+
+		// Network errors are retried
+		{
+			Errors: []error{
+				fmt.Errorf("connection reset"),
+				nil,
+			},
+			Calls: 2,
+			Err:   false,
+		},
+	}
+
+	for i, tc := range cases {
+		calls := 0
+		err := resource.Retry(time.Minute, retryOnAwsCodes([]string{"ResourceInUse"}, func() error {
+			calls++
+			return tc.Errors[calls-1]
+		}))
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if calls != tc.Calls {
+			t.Fatalf("%d: bad calls: %d", i, calls)
+		}
+	}
+
+	// The error that stopped the retries is returned as it is
+	err := resource.Retry(time.Minute, retryOnAwsCodes(nil, func() error {
+		return &ec2.Error{Code: "UnauthorizedOperation"}
+	}))
+	if !isAWSErr(err, "UnauthorizedOperation") {
+		t.Fatalf("bad: %#v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+// blockDeviceSchema returns the schema to use for block devices. It is
+// shared by instances and launch configurations so that root, EBS and
+// ephemeral devices are described the same way in both. It expects the
+// field to be named "block_device".
+func blockDeviceSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"device_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+
+				"virtual_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: true,
+				},
+
+				"snapshot_id": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+
+				"volume_type": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ForceNew:     true,
+					ValidateFunc: validateBlockDeviceVolumeType,
+				},
+
+				"volume_size": &schema.Schema{
+					Type:     schema.TypeInt,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+
+				"iops": &schema.Schema{
+					Type:     schema.TypeInt,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+
+				"delete_on_termination": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  true,
+					ForceNew: true,
+				},
+
+				"encrypted": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+
+				// Only instances can use a KMS key of their own: AutoScaling
+				// has no KMS key setting for launch configuration volumes,
+				// so launch configurations reject it
+				"kms_key_id": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: true,
+				},
+			},
+		},
+		Set: blockDeviceHash,
+	}
+}
+
+// blockDeviceVolumeTypes are the EBS volume types that can be given as a
+// block device's volume_type.
+var blockDeviceVolumeTypes = []string{"standard", "gp2", "gp3", "io1", "st1", "sc1"}
+
+// Takes the result of flatmap.Expand for an array of block devices and
+// returns EC2 API compatible objects
+func expandBlockDevices(configured []interface{}) []ec2.BlockDeviceMapping {
+	bds := make([]ec2.BlockDeviceMapping, 0, len(configured))
+	for _, raw := range configured {
+		bd := raw.(map[string]interface{})
+
+		mapping := ec2.BlockDeviceMapping{
+			DeviceName:          bd["device_name"].(string),
+			VirtualName:         bd["virtual_name"].(string),
+			SnapshotId:          bd["snapshot_id"].(string),
+			VolumeType:          bd["volume_type"].(string),
+			VolumeSize:          int64(bd["volume_size"].(int)),
+			IOPS:                int64(bd["iops"].(int)),
+			DeleteOnTermination: bd["delete_on_termination"].(bool),
+			Encrypted:           bd["encrypted"].(bool),
+		}
+
+		// The key is only meaningful for an encrypted volume
+		if mapping.Encrypted {
+			mapping.KmsKeyId = bd["kms_key_id"].(string)
+		}
+
+		bds = append(bds, mapping)
+	}
+
+	return bds
+}
+
+// Flattens an array of BlockDeviceMappings into a []map[string]interface{}
+func flattenBlockDevices(list []ec2.BlockDeviceMapping) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, bd := range list {
+		result = append(result, map[string]interface{}{
+			"device_name":           bd.DeviceName,
+			"virtual_name":          bd.VirtualName,
+			"snapshot_id":           bd.SnapshotId,
+			"volume_type":           bd.VolumeType,
+			"volume_size":           int(bd.VolumeSize),
+			"iops":                  int(bd.IOPS),
+			"delete_on_termination": bd.DeleteOnTermination,
+			"encrypted":             bd.Encrypted,
+			"kms_key_id":            bd.KmsKeyId,
+		})
+	}
+
+	return result
+}
+
+// replaceRootBlockDevice swaps the root device of the image, as read back
+// from the API, for the one we know from the configuration, or drops it if
+// it wasn't configured. AWS merges a block device for the root device with
+// the image's own mapping, so it doesn't read back the way it was given,
+// and would otherwise show a diff that can never be applied.
+func replaceRootBlockDevice(
+	bds []map[string]interface{}, rootDevice string, known []interface{}) []map[string]interface{} {
+	if rootDevice == "" {
+		return bds
+	}
+
+	result := make([]map[string]interface{}, 0, len(bds))
+	for _, bd := range bds {
+		if bd["device_name"].(string) != rootDevice {
+			result = append(result, bd)
+		}
+	}
+
+	for _, raw := range known {
+		if bd := raw.(map[string]interface{}); bd["device_name"].(string) == rootDevice {
+			result = append(result, bd)
+		}
+	}
+
+	return result
+}
+
+func validateBlockDeviceVolumeType(v interface{}, k string) (ws []string, es []error) {
+	for _, t := range blockDeviceVolumeTypes {
+		if v.(string) == t {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of %s, got %q",
+		k, strings.Join(blockDeviceVolumeTypes, ", "), v))
+	return
+}
+
+// validateBlockDeviceConfig checks the block devices in a resource's raw
+// configuration for settings that depend on each other, so that mistakes
+// are reported when planning instead of by the API.
+func validateBlockDeviceConfig(c *terraform.ResourceConfig) (ws []string, es []error) {
+	n, ok := c.Get("block_device.#")
+	if !ok {
+		return
+	}
+
+	for i := 0; i < n.(int); i++ {
+		prefix := fmt.Sprintf("block_device.%d.", i)
+		name, _ := c.Get(prefix + "device_name")
+
+		// Only provisioned IOPS volumes take an IOPS setting
+		if !c.IsComputed(prefix+"iops") && !c.IsComputed(prefix+"volume_type") {
+			var iops int
+			raw, _ := c.Get(prefix + "iops")
+			switch v := raw.(type) {
+			case int:
+				iops = v
+			case string:
+				iops, _ = strconv.Atoi(v)
+			}
+
+			volumeType, _ := c.Get(prefix + "volume_type")
+			if iops > 0 && volumeType != "io1" {
+				if volumeType == nil {
+					volumeType = ""
+				}
+				es = append(es, fmt.Errorf(
+					"block_device %v: iops can only be set for io1 volumes, "+
+						"not volume_type %q", name, volumeType))
+			}
+		}
+
+		if c.IsComputed(prefix+"kms_key_id") || c.IsComputed(prefix+"encrypted") {
+			continue
+		}
+
+		key, _ := c.Get(prefix + "kms_key_id")
+		if s, _ := key.(string); s == "" {
+			continue
+		}
+
+		var encrypted bool
+		raw, _ := c.Get(prefix + "encrypted")
+		switch v := raw.(type) {
+		case bool:
+			encrypted = v
+		case string:
+			encrypted, _ = strconv.ParseBool(v)
+		}
+
+		if !encrypted {
+			es = append(es, fmt.Errorf(
+				"block_device %v: kms_key_id requires encrypted to be true", name))
+		}
+	}
+
+	return
+}
+
+func blockDeviceHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["device_name"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", m["virtual_name"].(string)))
+	buf.WriteString(fmt.Sprintf("%t-", m["delete_on_termination"].(bool)))
+
+	// Only hashed when set, so devices without a key keep their hash
+	if v, ok := m["kms_key_id"]; ok && v.(string) != "" {
+		buf.WriteString(fmt.Sprintf("%s-", v.(string)))
+	}
+
+	return hashcode.String(buf.String())
+}
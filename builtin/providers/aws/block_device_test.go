@@ -0,0 +1,317 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestExpandBlockDevices(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"device_name":           "/dev/sda1",
+			"virtual_name":          "",
+			"snapshot_id":           "",
+			"volume_type":           "io1",
+			"volume_size":           100,
+			"iops":                  1000,
+			"delete_on_termination": true,
+			"encrypted":             true,
+			"kms_key_id":            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		},
+		map[string]interface{}{
+			"device_name":           "/dev/sdb",
+			"virtual_name":          "ephemeral0",
+			"snapshot_id":           "",
+			"volume_type":           "",
+			"volume_size":           0,
+			"iops":                  0,
+			"delete_on_termination": false,
+			"encrypted":             false,
+			"kms_key_id":            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		},
+	}
+
+	expected := []ec2.BlockDeviceMapping{
+		ec2.BlockDeviceMapping{
+			DeviceName:          "/dev/sda1",
+			VolumeType:          "io1",
+			VolumeSize:          100,
+			IOPS:                1000,
+			DeleteOnTermination: true,
+			Encrypted:           true,
+			KmsKeyId:            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		},
+		// The key of an unencrypted volume isn't sent
+		ec2.BlockDeviceMapping{
+			DeviceName:  "/dev/sdb",
+			VirtualName: "ephemeral0",
+		},
+	}
+
+	actual := expandBlockDevices(expanded)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual,
+			expected)
+	}
+}
+
+func TestFlattenBlockDevices(t *testing.T) {
+	input := []ec2.BlockDeviceMapping{
+		ec2.BlockDeviceMapping{
+			DeviceName:          "/dev/sda1",
+			SnapshotId:          "snap-1234abcd",
+			VolumeType:          "io1",
+			VolumeSize:          100,
+			IOPS:                1000,
+			DeleteOnTermination: true,
+			Encrypted:           true,
+			KmsKeyId:            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		},
+	}
+
+	expected := []map[string]interface{}{
+		map[string]interface{}{
+			"device_name":           "/dev/sda1",
+			"virtual_name":          "",
+			"snapshot_id":           "snap-1234abcd",
+			"volume_type":           "io1",
+			"volume_size":           100,
+			"iops":                  1000,
+			"delete_on_termination": true,
+			"encrypted":             true,
+			"kms_key_id":            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		},
+	}
+
+	actual := flattenBlockDevices(input)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual,
+			expected)
+	}
+
+	// Expanding what we flattened must round trip
+	raw := make([]interface{}, len(actual))
+	for i, m := range actual {
+		raw[i] = m
+	}
+	bds := expandBlockDevices(raw)
+	if !reflect.DeepEqual(bds, input) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			bds,
+			input)
+	}
+}
+
+func TestValidateBlockDeviceVolumeType(t *testing.T) {
+	for _, v := range []string{"standard", "gp2", "gp3", "io1", "st1", "sc1"} {
+		if _, es := validateBlockDeviceVolumeType(v, "volume_type"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"gp1", "io2", "GP2", "ssd"} {
+		if _, es := validateBlockDeviceVolumeType(v, "volume_type"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
+
+func TestValidateBlockDeviceConfig(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{},
+			Err:    false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"encrypted":   true,
+						"kms_key_id":  "arn:aws:kms:us-east-1:123456789012:key/abcd",
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"encrypted":   "1",
+						"kms_key_id":  "arn:aws:kms:us-east-1:123456789012:key/abcd",
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+					},
+					map[string]interface{}{
+						"device_name": "/dev/sdc",
+						"kms_key_id":  "arn:aws:kms:us-east-1:123456789012:key/abcd",
+					},
+				},
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"encrypted":   false,
+						"kms_key_id":  "arn:aws:kms:us-east-1:123456789012:key/abcd",
+					},
+				},
+			},
+			Err: true,
+		},
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"volume_type": "io1",
+						"iops":        1000,
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"volume_type": "gp2",
+						"iops":        1000,
+					},
+				},
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"iops":        "1000",
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := validateBlockDeviceConfig(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestBlockDeviceHash_kmsKeyId(t *testing.T) {
+	bd := map[string]interface{}{
+		"device_name":           "/dev/sdb",
+		"virtual_name":          "",
+		"delete_on_termination": true,
+	}
+	withEmptyKey := map[string]interface{}{
+		"device_name":           "/dev/sdb",
+		"virtual_name":          "",
+		"delete_on_termination": true,
+		"kms_key_id":            "",
+	}
+	withKey := map[string]interface{}{
+		"device_name":           "/dev/sdb",
+		"virtual_name":          "",
+		"delete_on_termination": true,
+		"kms_key_id":            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+	}
+
+	if blockDeviceHash(bd) != blockDeviceHash(withEmptyKey) {
+		t.Fatal("an empty kms_key_id should not change the hash")
+	}
+	if blockDeviceHash(bd) == blockDeviceHash(withKey) {
+		t.Fatal("kms_key_id should be part of the hash")
+	}
+}
+
+func TestReplaceRootBlockDevice(t *testing.T) {
+	configured := map[string]interface{}{
+		"device_name":           "/dev/sda1",
+		"virtual_name":          "",
+		"delete_on_termination": false,
+		"volume_size":           20,
+	}
+	data := map[string]interface{}{
+		"device_name":           "/dev/sdb",
+		"virtual_name":          "",
+		"delete_on_termination": true,
+	}
+
+	// AWS merged the configured root device with the image's mapping,
+	// which deletes the volume on termination
+	read := []map[string]interface{}{
+		map[string]interface{}{
+			"device_name":           "/dev/sda1",
+			"virtual_name":          "",
+			"delete_on_termination": true,
+			"snapshot_id":           "snap-1234",
+			"volume_size":           20,
+		},
+		data,
+	}
+	if blockDeviceHash(read[0]) == blockDeviceHash(configured) {
+		t.Fatal("the merged root device should differ from the configured one")
+	}
+
+	known := []interface{}{configured, data}
+	actual := replaceRootBlockDevice(read, "/dev/sda1", known)
+	expected := []map[string]interface{}{data, configured}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// A root device that wasn't configured isn't read at all
+	actual = replaceRootBlockDevice(read, "/dev/sda1", []interface{}{data})
+	if !reflect.DeepEqual(actual, []map[string]interface{}{data}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// Without the image, everything is read as it is
+	actual = replaceRootBlockDevice(read, "", known)
+	if !reflect.DeepEqual(actual, read) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
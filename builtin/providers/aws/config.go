@@ -3,7 +3,9 @@ package aws
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/hashicorp/terraform/helper/multierror"
@@ -17,20 +19,136 @@ import (
 )
 
 type Config struct {
-	AccessKey string
-	SecretKey string
-	Region    string
+	AccessKey  string
+	SecretKey  string
+	Region     string
+	MaxRetries int
+
+	// UserAgentSuffix is appended to the User-Agent of every request, so
+	// that requests can be told apart in CloudTrail and by AWS support.
+	UserAgentSuffix string
+
+	// Endpoints overrides the region's endpoint for a service, keyed by
+	// the service name: "ec2", "elb", "s3", "autoscaling" or "sts".
+	Endpoints map[string]string
+
+	// AssumeRoleARN is the role to assume with the credentials above. The
+	// connections then use the role's temporary credentials instead.
+	AssumeRoleARN         string
+	AssumeRoleSessionName string
+	AssumeRoleExternalID  string
 }
 
 type AWSClient struct {
+	// role renews the credentials when a role is assumed, and is nil
+	// otherwise
+	role *assumedRole
+
+	// The goamz connections keep their credentials in fields they read
+	// while signing, so they are only ever replaced as a whole, under
+	// goamzLock, when the credentials of the assumed role are renewed.
+	// Use the accessors, such as ec2conn(), rather than these fields.
+	goamzLock  sync.Mutex
+	goamz      *goamzConns
+	goamzCreds *stsCredentials
+	region     aws.Region
+	httpClient *http.Client
+
+	// Query API connections for the actions goamz doesn't have
+	ec2query         *queryConn
+	autoscalingquery *queryConn
+	elbquery         *queryConn
+	cloudwatchconn   *queryConn
+	iamconn          *queryConn
+	snsconn          *queryConn
+	rdsquery         *queryConn
+	route53query     *route53Conn
+
+	// JSON API connections for the services goamz doesn't have at all
+	logsconn *jsonConn
+
+	azCache    availabilityZoneCache
+	imageCache imageCache
+}
+
+// goamzConns are the connections made with goamz.
+type goamzConns struct {
 	ec2conn         *ec2.EC2
 	elbconn         *elb.ELB
 	autoscalingconn *autoscaling.AutoScaling
-	s3conn          *s3.S3
+	s3conn          *s3Conn
 	rdsconn         *rds.Rds
 	route53         *route53.Route53
 }
 
+func newGoamzConns(auth aws.Auth, region aws.Region, httpClient *http.Client, role *assumedRole) *goamzConns {
+	return &goamzConns{
+		ec2conn:         ec2.NewWithClient(auth, region, httpClient),
+		elbconn:         elb.NewWithClient(auth, region, httpClient),
+		autoscalingconn: autoscaling.NewWithClient(auth, region, httpClient),
+		s3conn: &s3Conn{
+			S3:         s3.NewWithClient(auth, region, httpClient),
+			httpClient: httpClient,
+			role:       role,
+		},
+		// goamz's RDS and Route53 can't be given an HTTP client, so only
+		// their query connections send the Terraform User-Agent
+		rdsconn: rds.New(auth, region),
+		route53: route53.New(auth, region),
+	}
+}
+
+// connections returns the goamz connections, first making them again if
+// the credentials of the assumed role have been renewed since. If they
+// can't be renewed the current connections are kept, and their requests
+// fail once the credentials expire.
+func (c *AWSClient) connections() *goamzConns {
+	c.goamzLock.Lock()
+	defer c.goamzLock.Unlock()
+
+	if c.role == nil {
+		return c.goamz
+	}
+
+	creds, err := c.role.Credentials()
+	if err != nil {
+		log.Printf("[WARN] Error renewing credentials: %s", err)
+		return c.goamz
+	}
+
+	if creds != c.goamzCreds {
+		log.Println("[INFO] Reconnecting with the renewed credentials")
+		c.goamz = newGoamzConns(creds.Auth(), c.region, c.httpClient, c.role)
+		c.goamzCreds = creds
+	}
+
+	return c.goamz
+}
+
+func (c *AWSClient) ec2conn() *ec2.EC2 {
+	return c.connections().ec2conn
+}
+
+func (c *AWSClient) elbconn() *elb.ELB {
+	return c.connections().elbconn
+}
+
+func (c *AWSClient) autoscalingconn() *autoscaling.AutoScaling {
+	return c.connections().autoscalingconn
+}
+
+func (c *AWSClient) s3conn() *s3Conn {
+	return c.connections().s3conn
+}
+
+func (c *AWSClient) rdsconn() *rds.Rds {
+	return c.connections().rdsconn
+}
+
+func (c *AWSClient) route53() *route53.Route53 {
+	return c.connections().route53
+}
+
 // Client configures and returns a fully initailized AWSClient
 func (c *Config) Client() (interface{}, error) {
 	var client AWSClient
@@ -49,20 +167,111 @@ func (c *Config) Client() (interface{}, error) {
 	if err != nil {
 		errs = append(errs, err)
 	}
+	region = c.regionWithEndpoints(region)
 
 	if len(errs) == 0 {
+		// Throttled requests are retried by the HTTP client shared by
+		// the connections below, which also identifies them as coming
+		// from Terraform
+		httpClient := newRetryingHTTPClient(c.MaxRetries, userAgent(c.UserAgentSuffix))
+
+		if c.AssumeRoleARN != "" {
+			// Every connection signs with the role's credentials, which
+			// are renewed before they expire
+			client.role = &assumedRole{
+				conn:        newSTSConn(auth, c.Endpoints["sts"], httpClient),
+				roleARN:     c.AssumeRoleARN,
+				sessionName: c.AssumeRoleSessionName,
+				externalID:  c.AssumeRoleExternalID,
+			}
+			creds, err := client.role.Credentials()
+			if err != nil {
+				return nil, err
+			}
+
+			auth = creds.Auth()
+			client.goamzCreds = creds
+		}
+
+		log.Println("[INFO] Initializing goamz connections")
+		client.goamz = newGoamzConns(auth, region, httpClient, client.role)
+		client.region = region
+		client.httpClient = httpClient
+
 		log.Println("[INFO] Initializing EC2 connection")
-		client.ec2conn = ec2.New(auth, region)
+		// 2016-11-15 is the first version with IPv6 in VPCs
+		client.ec2query = &queryConn{
+			auth:       auth,
+			endpoint:   region.EC2Endpoint,
+			version:    "2016-11-15",
+			httpClient: httpClient,
+			role:       client.role,
+		}
 		log.Println("[INFO] Initializing ELB connection")
-		client.elbconn = elb.New(auth, region)
+		client.elbquery = &queryConn{
+			auth:       auth,
+			endpoint:   region.ELBEndpoint,
+			version:    "2012-06-01",
+			httpClient: httpClient,
+			role:       client.role,
+		}
 		log.Println("[INFO] Initializing AutoScaling connection")
-		client.autoscalingconn = autoscaling.New(auth, region)
-		log.Println("[INFO] Initializing S3 connection")
-		client.s3conn = s3.New(auth, region)
+		client.autoscalingquery = &queryConn{
+			auth:       auth,
+			endpoint:   region.AutoScalingEndpoint,
+			version:    "2011-01-01",
+			httpClient: httpClient,
+			role:       client.role,
+		}
+		log.Println("[INFO] Initializing CloudWatch connection")
+		client.cloudwatchconn = &queryConn{
+			auth:       auth,
+			endpoint:   regionalEndpoint("monitoring", region),
+			version:    "2010-08-01",
+			httpClient: httpClient,
+			role:       client.role,
+		}
+		log.Println("[INFO] Initializing IAM connection")
+		client.iamconn = &queryConn{
+			auth:       auth,
+			endpoint:   iamEndpoint(region),
+			version:    "2010-05-08",
+			httpClient: httpClient,
+			role:       client.role,
+		}
+		log.Println("[INFO] Initializing SNS connection")
+		client.snsconn = &queryConn{
+			auth:       auth,
+			endpoint:   regionalEndpoint("sns", region),
+			version:    "2010-03-31",
+			httpClient: httpClient,
+			role:       client.role,
+		}
+		log.Println("[INFO] Initializing CloudWatch Logs connection")
+		client.logsconn = &jsonConn{
+			auth:         auth,
+			region:       region.Name,
+			service:      "logs",
+			endpoint:     regionalEndpoint("logs", region),
+			targetPrefix: "Logs_20140328",
+			httpClient:   httpClient,
+			role:         client.role,
+		}
 		log.Println("[INFO] Initializing RDS connection")
-		client.rdsconn = rds.New(auth, region)
+		client.rdsquery = &queryConn{
+			auth:       auth,
+			endpoint:   regionalEndpoint("rds", region),
+			version:    "2013-09-09",
+			httpClient: httpClient,
+			role:       client.role,
+		}
 		log.Println("[INFO] Initializing Route53 connection")
-		client.route53 = route53.New(auth, region)
+		client.route53query = &route53Conn{
+			auth:       auth,
+			endpoint:   route53Endpoint,
+			httpClient: httpClient,
+			role:       client.role,
+		}
 	}
 
 	if len(errs) > 0 {
@@ -87,14 +296,17 @@ func (c *Config) AWSAuth() (aws.Auth, error) {
 	return auth, err
 }
 
+// validRegions are the regions the provider can be configured with.
+// Anything keyed by region, such as elbHostedZoneIds, has to cover all of
+// them.
+var validRegions = []string{"us-east-1", "us-west-2", "us-west-1", "eu-west-1",
+	"eu-central-1", "ap-southeast-1", "ap-southeast-2", "ap-northeast-1",
+	"sa-east-1", "cn-north-1", "us-gov-west-1"}
+
 // IsValidRegion returns true if the configured region is a valid AWS
 // region and false if it's not
 func (c *Config) IsValidRegion() bool {
-	var regions = [11]string{"us-east-1", "us-west-2", "us-west-1", "eu-west-1",
-		"eu-central-1", "ap-southeast-1", "ap-southeast-2", "ap-northeast-1",
-		"sa-east-1", "cn-north-1", "us-gov-west-1"}
-
-	for _, valid := range regions {
+	for _, valid := range validRegions {
 		if c.Region == valid {
 			return true
 		}
@@ -122,3 +334,30 @@ func (c *Config) AWSRegion() (aws.Region, error) {
 	region := strings.TrimRightFunc(string(md), unicode.IsLetter)
 	return aws.Regions[region], nil
 }
+
+// regionWithEndpoints returns a copy of the region with any configured
+// endpoint overrides applied.
+func (c *Config) regionWithEndpoints(region aws.Region) aws.Region {
+	if v := c.Endpoints["ec2"]; v != "" {
+		log.Printf("[INFO] Using EC2 endpoint: %s", v)
+		region.EC2Endpoint = v
+	}
+	if v := c.Endpoints["elb"]; v != "" {
+		log.Printf("[INFO] Using ELB endpoint: %s", v)
+		region.ELBEndpoint = v
+	}
+	if v := c.Endpoints["autoscaling"]; v != "" {
+		log.Printf("[INFO] Using AutoScaling endpoint: %s", v)
+		region.AutoScalingEndpoint = v
+	}
+	if v := c.Endpoints["s3"]; v != "" {
+		log.Printf("[INFO] Using S3 endpoint: %s", v)
+		region.S3Endpoint = v
+
+		// Use path style requests against the endpoint rather than
+		// virtual hosted buckets, which custom endpoints rarely support
+		region.S3BucketEndpoint = ""
+	}
+
+	return region
+}
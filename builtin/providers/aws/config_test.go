@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestConfigRegionWithEndpoints(t *testing.T) {
+	c := &Config{
+		Endpoints: map[string]string{
+			"ec2": "http://localhost:4597",
+			"s3":  "http://localhost:4572",
+		},
+	}
+
+	region := c.regionWithEndpoints(aws.USEast)
+	if region.EC2Endpoint != "http://localhost:4597" {
+		t.Fatalf("bad EC2 endpoint: %s", region.EC2Endpoint)
+	}
+	if region.S3Endpoint != "http://localhost:4572" {
+		t.Fatalf("bad S3 endpoint: %s", region.S3Endpoint)
+	}
+	if region.S3BucketEndpoint != "" {
+		t.Fatalf("bad S3 bucket endpoint: %s", region.S3BucketEndpoint)
+	}
+
+	// Services without an override keep the region's endpoint
+	if region.ELBEndpoint != aws.USEast.ELBEndpoint {
+		t.Fatalf("bad ELB endpoint: %s", region.ELBEndpoint)
+	}
+	if region.AutoScalingEndpoint != aws.USEast.AutoScalingEndpoint {
+		t.Fatalf("bad AutoScaling endpoint: %s", region.AutoScalingEndpoint)
+	}
+
+	// The original region is left alone
+	if aws.USEast.EC2Endpoint == "http://localhost:4597" {
+		t.Fatal("region was modified")
+	}
+}
+
+func TestAWSClientAssumedRoleRenewal(t *testing.T) {
+	var stsCalls int32
+	sts := testSTSServer(t, &stsCalls)
+	defer sts.Close()
+
+	var lock sync.Mutex
+	var keys []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		keys = append(keys, r.URL.Query().Get("AWSAccessKeyId"))
+		lock.Unlock()
+
+		fmt.Fprint(w, `<DescribeAccountAttributesResponse/>`)
+	}))
+	defer api.Close()
+
+	role := &assumedRole{
+		conn:        newSTSConn(aws.Auth{AccessKey: "foo", SecretKey: "bar"}, sts.URL, http.DefaultClient),
+		roleARN:     "arn:aws:iam::123456789012:role/terraform",
+		sessionName: "terraform",
+	}
+	creds, err := role.Credentials()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	region := aws.Region{Name: "us-east-1", EC2Endpoint: api.URL}
+	client := &AWSClient{
+		role:       role,
+		goamz:      newGoamzConns(creds.Auth(), region, http.DefaultClient, role),
+		goamzCreds: creds,
+		region:     region,
+		httpClient: http.DefaultClient,
+		ec2query: &queryConn{
+			auth:       creds.Auth(),
+			endpoint:   api.URL,
+			version:    "2016-11-15",
+			httpClient: http.DefaultClient,
+			role:       role,
+		},
+	}
+
+	conn := client.ec2conn()
+	if conn.Auth.AccessKey != "ASIA1" || client.ec2conn() != conn {
+		t.Fatalf("bad: %#v", conn.Auth)
+	}
+
+	// The credentials run out in the middle of the run, while resources
+	// are being applied in parallel
+	testExpireAssumedRole(role)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := client.ec2query.Request("DescribeAccountAttributes", nil, nil); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			if c := client.ec2conn(); c.Auth.AccessKey != "ASIA2" {
+				t.Errorf("bad: %#v", c.Auth)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&stsCalls); n != 2 {
+		t.Fatalf("expected the role to be assumed again once, got %d calls", n-1)
+	}
+	for _, k := range keys {
+		if k != "ASIA2" {
+			t.Fatalf("request signed with the expired credentials: %s", k)
+		}
+	}
+
+	// A connection that was already handed out keeps its credentials
+	if conn.Auth.AccessKey != "ASIA1" {
+		t.Fatalf("connection was modified: %#v", conn.Auth)
+	}
+}
@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceAwsAmi looks up an AMI, so that configurations don't have to
+// hardcode image IDs, which differ between regions and go stale as images
+// are replaced. Like the other lookups it is a resource that only reads.
+func dataSourceAwsAmi() *schema.Resource {
+	return &schema.Resource{
+		Create: dataSourceAwsAmiRead,
+		Read:   dataSourceAwsAmiRead,
+		Delete: dataSourceAwsLookupDelete,
+
+		Schema: map[string]*schema.Schema{
+			// Account IDs, or aliases such as "amazon" or "self"
+			"owners": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			// May contain the wildcards * and ?
+			"name_pattern": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"virtualization_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"root_device_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"architecture": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"most_recent": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"owner_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"creation_date": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ec2Image is an image as described by DescribeImages.
+type ec2Image struct {
+	ImageId      string `xml:"imageId"`
+	Name         string `xml:"name"`
+	Description  string `xml:"description"`
+	OwnerId      string `xml:"imageOwnerId"`
+	CreationDate string `xml:"creationDate"`
+}
+
+// ec2ImagesByCreationDate sorts images from the newest to the oldest.
+// Creation dates are all in the same ISO 8601 form, so compare as strings.
+type ec2ImagesByCreationDate []ec2Image
+
+func (s ec2ImagesByCreationDate) Len() int      { return len(s) }
+func (s ec2ImagesByCreationDate) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ec2ImagesByCreationDate) Less(i, j int) bool {
+	return s[i].CreationDate > s[j].CreationDate
+}
+
+func dataSourceAwsAmiRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	filters := map[string]string{}
+	for k, name := range map[string]string{
+		"name_pattern":        "name",
+		"virtualization_type": "virtualization-type",
+		"root_device_type":    "root-device-type",
+		"architecture":        "architecture",
+	} {
+		if v, ok := d.GetOk(k); ok {
+			filters[name] = v.(string)
+		}
+	}
+
+	owners := expandStringList(d.Get("owners").([]interface{}))
+
+	images, err := describeEc2Images(conn, owners, filters)
+	if err != nil {
+		return err
+	}
+
+	image, err := selectEc2Image(images, d.Get("most_recent").(bool))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(image.ImageId)
+	d.Set("name", image.Name)
+	d.Set("description", image.Description)
+	d.Set("owner_id", image.OwnerId)
+	d.Set("creation_date", image.CreationDate)
+
+	return nil
+}
+
+// describeEc2Images returns the available images owned by any of the
+// owners, if given, that match every filter.
+func describeEc2Images(
+	conn *queryConn, owners []string, filters map[string]string) ([]ec2Image, error) {
+	params := make(map[string]string)
+	for i, owner := range owners {
+		params[fmt.Sprintf("Owner.%d", i+1)] = owner
+	}
+
+	// Images that are still being created or have failed can't be used.
+	// The filters are sorted so the request is the same every time.
+	names := []string{"state"}
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	for i, name := range names {
+		value := filters[name]
+		if name == "state" {
+			value = "available"
+		}
+
+		params[fmt.Sprintf("Filter.%d.Name", i+1)] = name
+		params[fmt.Sprintf("Filter.%d.Value.1", i+1)] = value
+	}
+
+	var resp struct {
+		Images []ec2Image `xml:"imagesSet>item"`
+	}
+
+	log.Printf("[DEBUG] EC2 describe images: %#v", params)
+	if err := conn.Request("DescribeImages", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error describing images: %s", err)
+	}
+
+	return resp.Images, nil
+}
+
+// selectEc2Image picks the image a search found. More than one is an error
+// unless mostRecent is set, in which case the newest is picked.
+func selectEc2Image(images []ec2Image, mostRecent bool) (*ec2Image, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf(
+			"No AMI matches the search, change the filters and try again")
+	}
+
+	if len(images) > 1 {
+		if !mostRecent {
+			return nil, fmt.Errorf(
+				"%d AMIs match the search. Narrow the filters or set "+
+					"most_recent to use the newest", len(images))
+		}
+
+		sorted := make([]ec2Image, len(images))
+		copy(sorted, images)
+		sort.Sort(ec2ImagesByCreationDate(sorted))
+		images = sorted
+	}
+
+	return &images[0], nil
+}
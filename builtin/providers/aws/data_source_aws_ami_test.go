@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSAmiLookup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSAmiLookupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"aws_ami_lookup.nat", "owner_id", "137112412989"),
+					resource.TestCheckResourceAttr(
+						"aws_ami_lookup.nat", "name", "amzn-ami-vpc-nat-pv-2014.09.1.x86_64-ebs"),
+				),
+			},
+		},
+	})
+}
+
+func TestDescribeEc2Images(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "DescribeImages" || q.Get("Owner.1") != "amazon" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		// State first, then the others in order
+		expected := map[string]string{
+			"Filter.1.Name":    "state",
+			"Filter.1.Value.1": "available",
+			"Filter.2.Name":    "name",
+			"Filter.2.Value.1": "amzn-ami-*",
+			"Filter.3.Name":    "virtualization-type",
+			"Filter.3.Value.1": "hvm",
+		}
+		for k, v := range expected {
+			if q.Get(k) != v {
+				t.Errorf("bad %s: %s", k, q.Get(k))
+			}
+		}
+
+		fmt.Fprint(w, `<DescribeImagesResponse>
+  <imagesSet>
+    <item>
+      <imageId>ami-1234</imageId>
+      <name>amzn-ami-2015.03</name>
+      <imageOwnerId>137112412989</imageOwnerId>
+      <creationDate>2015-03-01T00:00:00.000Z</creationDate>
+    </item>
+  </imagesSet>
+</DescribeImagesResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2014-10-01",
+		httpClient: http.DefaultClient,
+	}
+
+	images, err := describeEc2Images(conn, []string{"amazon"}, map[string]string{
+		"virtualization-type": "hvm",
+		"name":                "amzn-ami-*",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(images) != 1 || images[0].ImageId != "ami-1234" || images[0].OwnerId != "137112412989" {
+		t.Fatalf("bad: %#v", images)
+	}
+}
+
+func TestSelectEc2Image(t *testing.T) {
+	images := []ec2Image{
+		ec2Image{ImageId: "ami-1", CreationDate: "2015-01-01T00:00:00.000Z"},
+		ec2Image{ImageId: "ami-3", CreationDate: "2015-03-01T00:00:00.000Z"},
+		ec2Image{ImageId: "ami-2", CreationDate: "2015-02-01T00:00:00.000Z"},
+	}
+
+	cases := []struct {
+		Images     []ec2Image
+		MostRecent bool
+		Expected   string
+		Err        bool
+	}{
+		{Images: nil, MostRecent: true, Err: true},
+		{Images: images[:1], MostRecent: false, Expected: "ami-1"},
+		{Images: images, MostRecent: false, Err: true},
+		{Images: images, MostRecent: true, Expected: "ami-3"},
+	}
+
+	for i, tc := range cases {
+		image, err := selectEc2Image(tc.Images, tc.MostRecent)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: bad: %s", i, err)
+		}
+		if err == nil && image.ImageId != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, image.ImageId)
+		}
+	}
+
+	// The images given aren't reordered
+	if images[1].ImageId != "ami-3" {
+		t.Fatalf("images were sorted in place: %#v", images)
+	}
+}
+
+const testAccAWSAmiLookupConfig = `
+resource "aws_ami_lookup" "nat" {
+  owners = ["amazon"]
+  name_pattern = "amzn-ami-vpc-nat-pv-2014.09.1.x86_64-ebs"
+}
+`
@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceAwsLaunchConfiguration looks up an existing launch
+// configuration by name. It is a resource that only ever reads: creating
+// it finds the launch configuration and destroying it just forgets it.
+func dataSourceAwsLaunchConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Create: dataSourceAwsLaunchConfigurationRead,
+		Read:   dataSourceAwsLaunchConfigurationRead,
+		Delete: dataSourceAwsLookupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"image_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"instance_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"iam_instance_profile": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"key_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"security_groups": &schema.Schema{
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(v.(string))
+				},
+			},
+
+			"spot_price": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"block_device": blockDeviceSchema(),
+		},
+	}
+}
+
+func dataSourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}) error {
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
+
+	name := d.Get("name").(string)
+	lc, err := describeLaunchConfiguration(autoscalingconn, name)
+	if err != nil {
+		return err
+	}
+	if lc == nil {
+		return fmt.Errorf("Launch configuration %s not found", name)
+	}
+
+	d.SetId(lc.Name)
+	log.Printf("[DEBUG] Found launch configuration: %s", d.Id())
+
+	setLaunchConfiguration(d, lc)
+
+	return nil
+}
+
+// dataSourceAwsLookupDelete is the Delete of the lookup resources. There
+// is nothing to destroy, so the lookup is only removed from the state.
+func dataSourceAwsLookupDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
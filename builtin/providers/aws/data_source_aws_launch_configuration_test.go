@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mitchellh/goamz/autoscaling"
+)
+
+func TestAccAWSLaunchConfigurationLookup(t *testing.T) {
+	var conf autoscaling.LaunchConfiguration
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLaunchConfigurationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationLookupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration_lookup.bar", "name", "foobar-terraform-test-lookup"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration_lookup.bar", "image_id", "ami-21f78e11"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration_lookup.bar", "instance_type", "t1.micro"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration_lookup.bar", "block_device.#", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration_lookup.bar", "block_device.172787947.device_name", "/dev/sdb"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAWSLaunchConfigurationLookupConfig = `
+resource "aws_launch_configuration" "bar" {
+  name = "foobar-terraform-test-lookup"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+
+  block_device {
+    device_name = "/dev/sdb"
+    volume_size = 10
+  }
+}
+
+resource "aws_launch_configuration_lookup" "bar" {
+  name = "${aws_launch_configuration.bar.name}"
+}
+`
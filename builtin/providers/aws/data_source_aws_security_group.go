@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+// dataSourceAwsSecurityGroup looks up an existing security group, such as
+// one shared between configurations, by its ID, name, VPC or filters. Like
+// the other lookups it is a resource that only reads.
+func dataSourceAwsSecurityGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: dataSourceAwsSecurityGroupRead,
+		Read:   dataSourceAwsSecurityGroupRead,
+		Delete: dataSourceAwsLookupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"filter": ec2FilterSchema(),
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAwsSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	sg, err := findSecurityGroup(
+		ec2conn, d.Get("id").(string), d.Get("name").(string),
+		d.Get("vpc_id").(string), buildEC2Filters(d))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(sg.Id)
+	log.Printf("[DEBUG] Found security group: %s", d.Id())
+
+	d.Set("id", sg.Id)
+	d.Set("name", sg.Name)
+	d.Set("vpc_id", sg.VpcId)
+	d.Set("description", sg.Description)
+
+	return nil
+}
+
+// findSecurityGroup returns the one security group that has the ID, name
+// and VPC, where they're given, and matches the filter. It's an error for
+// no group or more than one to match.
+func findSecurityGroup(
+	conn *ec2.EC2, id, name, vpcId string, filter *ec2.Filter) (*ec2.SecurityGroupInfo, error) {
+	var groups []ec2.SecurityGroup
+	if id != "" {
+		groups = append(groups, ec2.SecurityGroup{Id: id})
+	}
+	if name != "" {
+		filter.Add("group-name", name)
+	}
+	if vpcId != "" {
+		filter.Add("vpc-id", vpcId)
+	}
+
+	resp, err := conn.SecurityGroups(groups, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Error describing security groups: %s", err)
+	}
+
+	switch len(resp.Groups) {
+	case 0:
+		return nil, fmt.Errorf(
+			"No security group matches the search, change it and try again")
+	case 1:
+		return &resp.Groups[0], nil
+	}
+
+	ids := make([]string, 0, len(resp.Groups))
+	for _, g := range resp.Groups {
+		ids = append(ids, g.Id)
+	}
+	return nil, fmt.Errorf(
+		"%d security groups match the search (%v), it has to match only one",
+		len(resp.Groups), ids)
+}
@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestAccAWSSecurityGroupLookup(t *testing.T) {
+	var group ec2.SecurityGroupInfo
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSSecurityGroupLookupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSSecurityGroupExists("aws_security_group.web", &group),
+					resource.TestCheckResourceAttr(
+						"aws_security_group_lookup.web", "name", "terraform_acceptance_test_lookup"),
+					resource.TestCheckResourceAttr(
+						"aws_security_group_lookup.web", "description", "Used in the terraform acceptance tests"),
+				),
+			},
+		},
+	})
+}
+
+func TestFindSecurityGroup(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") != "DescribeSecurityGroups" {
+			t.Errorf("bad action: %s", r.Form.Get("Action"))
+		}
+
+		// Without a name, both groups in the VPC match
+		fmt.Fprint(w, `<DescribeSecurityGroupsResponse><securityGroupInfo>`)
+		for _, name := range []string{"web", "db"} {
+			if r.Form.Get("Filter.1.Name") == "group-name" && r.Form.Get("Filter.1.Value.1") != name {
+				continue
+			}
+
+			fmt.Fprintf(w, `<item>
+  <groupId>sg-%s</groupId>
+  <groupName>%s</groupName>
+  <groupDescription>%s servers</groupDescription>
+  <vpcId>vpc-1234</vpcId>
+</item>`, name, name, name)
+		}
+		fmt.Fprint(w, `</securityGroupInfo></DescribeSecurityGroupsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := ec2.New(
+		aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		aws.Region{Name: "us-east-1", EC2Endpoint: ts.URL})
+
+	sg, err := findSecurityGroup(conn, "", "web", "", ec2.NewFilter())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if sg.Id != "sg-web" || sg.Description != "web servers" || sg.VpcId != "vpc-1234" {
+		t.Fatalf("bad: %#v", sg)
+	}
+
+	_, err = findSecurityGroup(conn, "", "", "vpc-1234", ec2.NewFilter())
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "2 security groups match") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	_, err = findSecurityGroup(conn, "", "app", "", ec2.NewFilter())
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "No security group matches") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+const testAccAWSSecurityGroupLookupConfig = `
+resource "aws_security_group" "web" {
+  name = "terraform_acceptance_test_lookup"
+  description = "Used in the terraform acceptance tests"
+
+  ingress {
+    protocol = "tcp"
+    from_port = 80
+    to_port = 8000
+    cidr_blocks = ["10.0.0.0/8"]
+  }
+}
+
+resource "aws_security_group_lookup" "web" {
+  name = "${aws_security_group.web.name}"
+}
+`
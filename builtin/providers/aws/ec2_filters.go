@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+// ec2FilterSchema returns the schema to use for the "filter" blocks of
+// the lookup resources. Each block names an EC2 API filter, such as
+// "tag:Name" or "vpc-id", and the values it matches.
+func ec2FilterSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+
+				"values": &schema.Schema{
+					Type:     schema.TypeList,
+					Required: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+		Set: ec2FilterHash,
+	}
+}
+
+// buildEC2Filters is a helper to build the filter for a DescribeX call.
+// It expects the filter field to be named "filter".
+func buildEC2Filters(d *schema.ResourceData) *ec2.Filter {
+	return expandEC2Filters(d.Get("filter").(*schema.Set).List())
+}
+
+// Takes the result of flatmap.Expand for an array of filters and returns
+// the EC2 filter to describe with. Filters with the same name match any
+// of their values.
+func expandEC2Filters(configured []interface{}) *ec2.Filter {
+	filter := ec2.NewFilter()
+	for _, raw := range configured {
+		data := raw.(map[string]interface{})
+		filter.Add(
+			data["name"].(string),
+			expandStringList(data["values"].([]interface{}))...)
+	}
+
+	return filter
+}
+
+func ec2FilterHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
+
+	values := expandStringList(m["values"].([]interface{}))
+	sort.Strings(values)
+	for _, v := range values {
+		buf.WriteString(fmt.Sprintf("%s-", v))
+	}
+
+	return hashcode.String(buf.String())
+}
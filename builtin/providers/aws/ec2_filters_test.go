@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestExpandEC2Filters(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"name":   "tag:Name",
+			"values": []interface{}{"web", "app"},
+		},
+		map[string]interface{}{
+			"name":   "vpc-id",
+			"values": []interface{}{"vpc-12345678"},
+		},
+	}
+
+	expected := ec2.NewFilter()
+	expected.Add("tag:Name", "web", "app")
+	expected.Add("vpc-id", "vpc-12345678")
+
+	filter := expandEC2Filters(expanded)
+	if !reflect.DeepEqual(filter, expected) {
+		t.Fatalf("bad: %#v", filter)
+	}
+
+	if filter := expandEC2Filters(nil); !reflect.DeepEqual(filter, ec2.NewFilter()) {
+		t.Fatalf("bad empty filter: %#v", filter)
+	}
+}
+
+func TestEC2FilterHash(t *testing.T) {
+	a := map[string]interface{}{
+		"name":   "tag:Name",
+		"values": []interface{}{"web", "app"},
+	}
+	b := map[string]interface{}{
+		"name":   "tag:Name",
+		"values": []interface{}{"app", "web"},
+	}
+
+	if ec2FilterHash(a) != ec2FilterHash(b) {
+		t.Fatal("value order should not change the hash")
+	}
+}
@@ -0,0 +1,23 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// retryDependencyViolation retries the deletion f for as long as EC2
+// reports that something still depends on the resource, such as a
+// network interface that lingers after its instance is gone. notFound is
+// the error code EC2 uses for a resource that no longer exists, which
+// counts as deleted. Errors that don't come from EC2, such as network
+// errors, are retried too. Any other EC2 error is returned immediately.
+func retryDependencyViolation(timeout time.Duration, notFound string, f func() error) error {
+	return resource.Retry(timeout, retryOnAwsCodes([]string{"DependencyViolation"}, func() error {
+		if err := f(); !isAWSErr(err, notFound) {
+			return err
+		}
+
+		return nil
+	}))
+}
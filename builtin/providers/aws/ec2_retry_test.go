@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestRetryDependencyViolation(t *testing.T) {
+	cases := []struct {
+		Errors []error
+		Calls  int
+		Err    bool
+	}{
+		// The dependency goes away after a couple of attempts
+		{
+			Errors: []error{
+				&ec2.Error{Code: "DependencyViolation"},
+				&ec2.Error{Code: "DependencyViolation"},
+				nil,
+			},
+			Calls: 3,
+			Err:   false,
+		},
+
+		// Already deleted
+		{
+			Errors: []error{
+				&ec2.Error{Code: "DependencyViolation"},
+				&ec2.Error{Code: "InvalidSubnetID.NotFound"},
+			},
+			Calls: 2,
+			Err:   false,
+		},
+
+		// Other EC2 errors aren't retried
+		{
+			Errors: []error{
+				&ec2.Error{Code: "UnauthorizedOperation"},
+			},
+			Calls: 1,
+			Err:   true,
+		},
+
+		// Network errors are
+		{
+			Errors: []error{
+				fmt.Errorf("connection reset"),
+				nil,
+			},
+			Calls: 2,
+			Err:   false,
+		},
+	}
+
+	for i, tc := range cases {
+		calls := 0
+		err := retryDependencyViolation(time.Minute, "InvalidSubnetID.NotFound", func() error {
+			calls++
+			return tc.Errors[calls-1]
+		})
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if calls != tc.Calls {
+			t.Fatalf("%d: bad calls: %d", i, calls)
+		}
+	}
+}
+
+func TestRetryDependencyViolation_timeout(t *testing.T) {
+	err := retryDependencyViolation(time.Second, "InvalidSubnetID.NotFound", func() error {
+		return &ec2.Error{Code: "DependencyViolation"}
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+}
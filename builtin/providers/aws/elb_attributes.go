@@ -0,0 +1,301 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// elbAttributesVerifyTimeout bounds how long verify_attributes waits for
+// the attributes of an ELB to read back as they were set.
+const elbAttributesVerifyTimeout = time.Minute
+
+// elbAdditionalAttributeDefaults are the additional attributes that can be
+// given in additional_attributes, with the values AWS gives them when they
+// aren't set.
+var elbAdditionalAttributeDefaults = map[string]string{
+	"elb.http.desyncmitigationmode": "defensive",
+}
+
+// elbAttributes are the attributes of an ELB as returned by
+// DescribeLoadBalancerAttributes, which goamz only partly supports.
+type elbAttributes struct {
+	CrossZoneLoadBalancing struct {
+		Enabled bool `xml:"Enabled"`
+	} `xml:"CrossZoneLoadBalancing"`
+
+	AccessLog elbAccessLog `xml:"AccessLog"`
+
+	ConnectionDraining struct {
+		Enabled bool `xml:"Enabled"`
+		Timeout int  `xml:"Timeout"`
+	} `xml:"ConnectionDraining"`
+
+	ConnectionSettings struct {
+		IdleTimeout int `xml:"IdleTimeout"`
+	} `xml:"ConnectionSettings"`
+
+	AdditionalAttributes []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"AdditionalAttributes>member"`
+}
+
+type elbAccessLog struct {
+	Enabled        bool   `xml:"Enabled"`
+	S3BucketName   string `xml:"S3BucketName"`
+	S3BucketPrefix string `xml:"S3BucketPrefix"`
+	EmitInterval   int    `xml:"EmitInterval"`
+}
+
+// describeElbAttributes fetches every attribute of the ELB in a single
+// request.
+func describeElbAttributes(conn *queryConn, name string) (*elbAttributes, error) {
+	params := map[string]string{
+		"LoadBalancerName": name,
+	}
+
+	var resp struct {
+		Attributes elbAttributes `xml:"DescribeLoadBalancerAttributesResult>LoadBalancerAttributes"`
+	}
+
+	log.Printf("[DEBUG] ELB describe attributes: %s", name)
+	if err := conn.Request("DescribeLoadBalancerAttributes", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error retrieving ELB attributes: %s", err)
+	}
+
+	return &resp.Attributes, nil
+}
+
+// flattenElbAccessLog returns the access_logs of an ELB, which are empty
+// unless logging is enabled.
+func flattenElbAccessLog(l elbAccessLog) []map[string]interface{} {
+	if !l.Enabled {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		map[string]interface{}{
+			"bucket":        l.S3BucketName,
+			"bucket_prefix": l.S3BucketPrefix,
+			"interval":      l.EmitInterval,
+		},
+	}
+}
+
+// expandElbAttributes returns the attributes of the ELB that have fields
+// of their own. Values that haven't changed come from the state, so that
+// they can all be sent together without resetting any of them.
+func expandElbAttributes(d *schema.ResourceData) *elbAttributes {
+	attrs := new(elbAttributes)
+	attrs.CrossZoneLoadBalancing.Enabled = d.Get("cross_zone_load_balancing").(bool)
+	attrs.ConnectionDraining.Enabled = d.Get("connection_draining").(bool)
+	attrs.ConnectionDraining.Timeout = d.Get("connection_draining_timeout").(int)
+	attrs.ConnectionSettings.IdleTimeout = d.Get("idle_timeout").(int)
+
+	if logs := d.Get("access_logs").([]interface{}); len(logs) > 0 {
+		m := logs[0].(map[string]interface{})
+		attrs.AccessLog = elbAccessLog{
+			Enabled:        true,
+			S3BucketName:   m["bucket"].(string),
+			S3BucketPrefix: m["bucket_prefix"].(string),
+			EmitInterval:   m["interval"].(int),
+		}
+	}
+
+	return attrs
+}
+
+// modifyElbAttributes sets all the attributes that have fields of their
+// own in a single request, so that setting one can't clobber another.
+// Timeouts of zero haven't been read yet and are left alone, as AWS
+// doesn't accept them.
+func modifyElbAttributes(conn *queryConn, name string, attrs *elbAttributes) error {
+	params := map[string]string{
+		"LoadBalancerName": name,
+		"LoadBalancerAttributes.CrossZoneLoadBalancing.Enabled": strconv.FormatBool(
+			attrs.CrossZoneLoadBalancing.Enabled),
+		"LoadBalancerAttributes.ConnectionDraining.Enabled": strconv.FormatBool(
+			attrs.ConnectionDraining.Enabled),
+		"LoadBalancerAttributes.AccessLog.Enabled": strconv.FormatBool(
+			attrs.AccessLog.Enabled),
+	}
+	if attrs.ConnectionDraining.Timeout > 0 {
+		params["LoadBalancerAttributes.ConnectionDraining.Timeout"] = strconv.Itoa(
+			attrs.ConnectionDraining.Timeout)
+	}
+	if attrs.ConnectionSettings.IdleTimeout > 0 {
+		params["LoadBalancerAttributes.ConnectionSettings.IdleTimeout"] = strconv.Itoa(
+			attrs.ConnectionSettings.IdleTimeout)
+	}
+	if attrs.AccessLog.Enabled {
+		params["LoadBalancerAttributes.AccessLog.S3BucketName"] = attrs.AccessLog.S3BucketName
+		params["LoadBalancerAttributes.AccessLog.S3BucketPrefix"] = attrs.AccessLog.S3BucketPrefix
+		params["LoadBalancerAttributes.AccessLog.EmitInterval"] = strconv.Itoa(
+			attrs.AccessLog.EmitInterval)
+	}
+
+	log.Printf("[DEBUG] ELB modify attributes: %#v", params)
+	if err := conn.Request("ModifyLoadBalancerAttributes", params, nil); err != nil {
+		return fmt.Errorf("Error modifying ELB attributes: %s", err)
+	}
+
+	return nil
+}
+
+// waitForElbAttributes re-reads the attributes of the ELB until they match
+// the ones that were just set, as reads can lag behind a modify for a
+// little while. Either of expected or additional may be empty.
+func waitForElbAttributes(
+	conn *queryConn, name string, expected *elbAttributes,
+	additional map[string]string, timeout time.Duration) error {
+	err := resource.Retry(timeout, func() error {
+		actual, err := describeElbAttributes(conn, name)
+		if err != nil {
+			return resource.RetryError{err}
+		}
+
+		if mismatched := elbAttributesMismatch(expected, additional, actual); len(mismatched) > 0 {
+			log.Printf("[DEBUG] ELB %s attributes haven't converged yet: %v", name, mismatched)
+			return fmt.Errorf("%s", strings.Join(mismatched, "; "))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error verifying attributes of ELB %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// elbAttributesMismatch describes every attribute of actual that differs
+// from what was set. Zero timeouts aren't sent by modifyElbAttributes, so
+// they aren't compared either.
+func elbAttributesMismatch(
+	expected *elbAttributes, additional map[string]string, actual *elbAttributes) []string {
+	var result []string
+	mismatch := func(field string, actual, expected interface{}) {
+		if actual != expected {
+			result = append(result, fmt.Sprintf("%s is %+v, expected %+v", field, actual, expected))
+		}
+	}
+
+	if expected != nil {
+		mismatch("cross_zone_load_balancing",
+			actual.CrossZoneLoadBalancing.Enabled, expected.CrossZoneLoadBalancing.Enabled)
+		mismatch("connection_draining",
+			actual.ConnectionDraining.Enabled, expected.ConnectionDraining.Enabled)
+		if expected.ConnectionDraining.Timeout > 0 {
+			mismatch("connection_draining_timeout",
+				actual.ConnectionDraining.Timeout, expected.ConnectionDraining.Timeout)
+		}
+		if expected.ConnectionSettings.IdleTimeout > 0 {
+			mismatch("idle_timeout",
+				actual.ConnectionSettings.IdleTimeout, expected.ConnectionSettings.IdleTimeout)
+		}
+		if expected.AccessLog.Enabled {
+			mismatch("access_logs", actual.AccessLog, expected.AccessLog)
+		} else {
+			mismatch("access_logs enabled", actual.AccessLog.Enabled, false)
+		}
+	}
+
+	values := make(map[string]string)
+	for _, a := range actual.AdditionalAttributes {
+		values[a.Key] = a.Value
+	}
+	keys := make([]string, 0, len(additional))
+	for k := range additional {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		mismatch(k, values[k], additional[k])
+	}
+
+	return result
+}
+
+// modifyElbAdditionalAttributes sets additional attributes of the ELB,
+// leaving the others alone.
+func modifyElbAdditionalAttributes(conn *queryConn, name string, attrs map[string]string) error {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	params := map[string]string{
+		"LoadBalancerName": name,
+	}
+	for i, k := range keys {
+		prefix := fmt.Sprintf("LoadBalancerAttributes.AdditionalAttributes.member.%d", i+1)
+		params[prefix+".Key"] = k
+		params[prefix+".Value"] = attrs[k]
+	}
+
+	log.Printf("[DEBUG] ELB modify additional attributes: %#v", params)
+	if err := conn.Request("ModifyLoadBalancerAttributes", params, nil); err != nil {
+		return fmt.Errorf("Error modifying ELB additional attributes: %s", err)
+	}
+
+	return nil
+}
+
+// expandElbAdditionalAttributes returns the additional attributes to set
+// to go from o to n. Attributes that were removed go back to their
+// defaults, since AWS has no way to unset them.
+func expandElbAdditionalAttributes(o, n map[string]interface{}) map[string]string {
+	attrs := make(map[string]string)
+	for k := range o {
+		if _, ok := n[k]; !ok {
+			attrs[k] = elbAdditionalAttributeDefaults[k]
+		}
+	}
+	for k, v := range n {
+		attrs[k] = v.(string)
+	}
+
+	return attrs
+}
+
+// flattenElbAdditionalAttributes returns the additional attributes of an
+// ELB that were configured. AWS reports every additional attribute, and
+// the others would otherwise show up as a diff.
+func flattenElbAdditionalAttributes(attrs *elbAttributes, configured map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	for _, a := range attrs.AdditionalAttributes {
+		if _, ok := configured[a.Key]; ok {
+			result[a.Key] = a.Value
+		}
+	}
+
+	return result
+}
+
+// validateElbAdditionalAttributes checks that the keys of
+// additional_attributes are ones we know, to catch typos at plan time.
+func validateElbAdditionalAttributes(m map[string]interface{}) (es []error) {
+	known := make([]string, 0, len(elbAdditionalAttributeDefaults))
+	for k := range elbAdditionalAttributeDefaults {
+		known = append(known, k)
+	}
+	sort.Strings(known)
+
+	for k := range m {
+		if _, ok := elbAdditionalAttributeDefaults[k]; !ok {
+			es = append(es, fmt.Errorf(
+				"additional_attributes: unknown attribute %q, must be one of %s",
+				k, strings.Join(known, ", ")))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,309 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestDescribeElbAttributes(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		q := r.URL.Query()
+		if q.Get("Action") != "DescribeLoadBalancerAttributes" || q.Get("LoadBalancerName") != "foo" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `<DescribeLoadBalancerAttributesResponse>
+  <DescribeLoadBalancerAttributesResult>
+    <LoadBalancerAttributes>
+      <CrossZoneLoadBalancing><Enabled>true</Enabled></CrossZoneLoadBalancing>
+      <AccessLog>
+        <Enabled>true</Enabled>
+        <S3BucketName>logs</S3BucketName>
+        <S3BucketPrefix>elb</S3BucketPrefix>
+        <EmitInterval>5</EmitInterval>
+      </AccessLog>
+      <ConnectionDraining><Enabled>true</Enabled><Timeout>120</Timeout></ConnectionDraining>
+      <ConnectionSettings><IdleTimeout>90</IdleTimeout></ConnectionSettings>
+      <AdditionalAttributes>
+        <member><Key>elb.http.desyncmitigationmode</Key><Value>strictest</Value></member>
+      </AdditionalAttributes>
+    </LoadBalancerAttributes>
+  </DescribeLoadBalancerAttributesResult>
+</DescribeLoadBalancerAttributesResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	attrs, err := describeElbAttributes(conn, "foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+
+	if !attrs.CrossZoneLoadBalancing.Enabled {
+		t.Fatalf("bad cross zone: %#v", attrs)
+	}
+	if !attrs.ConnectionDraining.Enabled || attrs.ConnectionDraining.Timeout != 120 {
+		t.Fatalf("bad connection draining: %#v", attrs)
+	}
+	if attrs.ConnectionSettings.IdleTimeout != 90 {
+		t.Fatalf("bad idle timeout: %#v", attrs)
+	}
+
+	expected := []map[string]interface{}{
+		map[string]interface{}{
+			"bucket":        "logs",
+			"bucket_prefix": "elb",
+			"interval":      5,
+		},
+	}
+	if logs := flattenElbAccessLog(attrs.AccessLog); !reflect.DeepEqual(logs, expected) {
+		t.Fatalf("bad access logs: %#v", logs)
+	}
+
+	additional := flattenElbAdditionalAttributes(attrs, map[string]interface{}{
+		"elb.http.desyncmitigationmode": "strictest",
+	})
+	if !reflect.DeepEqual(additional, map[string]string{"elb.http.desyncmitigationmode": "strictest"}) {
+		t.Fatalf("bad additional attributes: %#v", additional)
+	}
+	if additional := flattenElbAdditionalAttributes(attrs, nil); len(additional) > 0 {
+		t.Fatalf("unconfigured additional attributes: %#v", additional)
+	}
+
+	attrs.AccessLog.Enabled = false
+	if logs := flattenElbAccessLog(attrs.AccessLog); logs != nil {
+		t.Fatalf("expected no access logs: %#v", logs)
+	}
+}
+
+func TestModifyElbAttributes(t *testing.T) {
+	var requests []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		fmt.Fprint(w, `<ModifyLoadBalancerAttributesResponse></ModifyLoadBalancerAttributesResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	attrs := new(elbAttributes)
+	attrs.CrossZoneLoadBalancing.Enabled = true
+	attrs.ConnectionDraining.Enabled = true
+	attrs.ConnectionDraining.Timeout = 120
+	attrs.ConnectionSettings.IdleTimeout = 90
+
+	if err := modifyElbAttributes(conn, "foo", attrs); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected a single request, got %d", len(requests))
+	}
+
+	q := requests[0]
+	expected := map[string]string{
+		"Action":           "ModifyLoadBalancerAttributes",
+		"LoadBalancerName": "foo",
+		"LoadBalancerAttributes.CrossZoneLoadBalancing.Enabled": "true",
+		"LoadBalancerAttributes.ConnectionDraining.Enabled":     "true",
+		"LoadBalancerAttributes.ConnectionDraining.Timeout":     "120",
+		"LoadBalancerAttributes.ConnectionSettings.IdleTimeout": "90",
+		"LoadBalancerAttributes.AccessLog.Enabled":              "false",
+		"LoadBalancerAttributes.AccessLog.S3BucketName":         "",
+	}
+	for k, v := range expected {
+		if q.Get(k) != v {
+			t.Fatalf("bad %s: %q", k, q.Get(k))
+		}
+	}
+}
+
+func TestWaitForElbAttributes(t *testing.T) {
+	// Cross zone load balancing only reads back as enabled on the third
+	// request, and the desync mitigation mode never changes
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		fmt.Fprintf(w, `<DescribeLoadBalancerAttributesResponse>
+  <DescribeLoadBalancerAttributesResult>
+    <LoadBalancerAttributes>
+      <CrossZoneLoadBalancing><Enabled>%t</Enabled></CrossZoneLoadBalancing>
+      <ConnectionDraining><Enabled>false</Enabled><Timeout>300</Timeout></ConnectionDraining>
+      <ConnectionSettings><IdleTimeout>60</IdleTimeout></ConnectionSettings>
+      <AdditionalAttributes>
+        <member><Key>elb.http.desyncmitigationmode</Key><Value>defensive</Value></member>
+      </AdditionalAttributes>
+    </LoadBalancerAttributes>
+  </DescribeLoadBalancerAttributesResult>
+</DescribeLoadBalancerAttributesResponse>`, requests >= 3)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	expected := new(elbAttributes)
+	expected.CrossZoneLoadBalancing.Enabled = true
+	expected.ConnectionSettings.IdleTimeout = 60
+
+	if err := waitForElbAttributes(conn, "foo", expected, nil, time.Minute); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+
+	// Attributes that never converge fail once the timeout is up
+	additional := map[string]string{"elb.http.desyncmitigationmode": "strictest"}
+	err := waitForElbAttributes(conn, "foo", nil, additional, time.Second)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "elb.http.desyncmitigationmode is defensive, expected strictest") {
+		t.Fatalf("bad error: %s", err)
+	}
+}
+
+func TestElbAttributesMismatch(t *testing.T) {
+	actual := new(elbAttributes)
+	actual.ConnectionDraining.Timeout = 300
+	actual.AccessLog = elbAccessLog{
+		Enabled:      true,
+		S3BucketName: "logs",
+		EmitInterval: 60,
+	}
+
+	// Timeouts that weren't set aren't compared
+	expected := new(elbAttributes)
+	expected.AccessLog = actual.AccessLog
+	if mismatched := elbAttributesMismatch(expected, nil, actual); len(mismatched) > 0 {
+		t.Fatalf("bad: %#v", mismatched)
+	}
+
+	expected.AccessLog.EmitInterval = 5
+	expected.ConnectionDraining.Timeout = 120
+	mismatched := elbAttributesMismatch(expected, nil, actual)
+	if len(mismatched) != 2 {
+		t.Fatalf("bad: %#v", mismatched)
+	}
+
+	// Disabling logs only needs them to read back as disabled
+	expected = new(elbAttributes)
+	mismatched = elbAttributesMismatch(expected, nil, actual)
+	if !reflect.DeepEqual(mismatched, []string{"access_logs enabled is true, expected false"}) {
+		t.Fatalf("bad: %#v", mismatched)
+	}
+}
+
+func TestExpandElbAdditionalAttributes(t *testing.T) {
+	cases := []struct {
+		Old, New map[string]interface{}
+		Expected map[string]string
+	}{
+		{
+			Old: nil,
+			New: map[string]interface{}{
+				"elb.http.desyncmitigationmode": "monitor",
+			},
+			Expected: map[string]string{
+				"elb.http.desyncmitigationmode": "monitor",
+			},
+		},
+
+		// Removed attributes go back to their defaults
+		{
+			Old: map[string]interface{}{
+				"elb.http.desyncmitigationmode": "monitor",
+			},
+			New: nil,
+			Expected: map[string]string{
+				"elb.http.desyncmitigationmode": "defensive",
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := expandElbAdditionalAttributes(tc.Old, tc.New)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestResourceAwsElbValidate_additionalAttributes(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{},
+			Err:    false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"additional_attributes": []map[string]interface{}{
+					map[string]interface{}{
+						"elb.http.desyncmitigationmode": "strictest",
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"additional_attributes": []map[string]interface{}{
+					map[string]interface{}{
+						"elb.http.desyncmitigation": "strictest",
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		tc.Config["availability_zones"] = []interface{}{"us-west-2a"}
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsElbValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"fmt"
+)
+
+// elbHostedZoneIds are the IDs of the Route 53 hosted zones of the ELB
+// DNS names in each region, which alias records pointing at an ELB need.
+var elbHostedZoneIds = map[string]string{
+	"ap-northeast-1": "Z14GRHDCWA56QT",
+	"ap-southeast-1": "Z1LMS91P8CMLE5",
+	"ap-southeast-2": "Z1GM3OXH4ZPM65",
+	"cn-north-1":     "Z3BX2TMKNYI13Y",
+	"eu-central-1":   "Z215JYRZR1TBD5",
+	"eu-west-1":      "Z32O12XQLNTSW2",
+	"sa-east-1":      "Z2P70J7HTTTPLU",
+	"us-east-1":      "Z35SXDOTRQ7X7K",
+	"us-gov-west-1":  "Z33AYJ8TM3BH4J",
+	"us-west-1":      "Z368ELLRRE2KJ0",
+	"us-west-2":      "Z1H1FL5HABSF5",
+}
+
+// elbHostedZoneId returns the hosted zone ID of the ELBs in the region.
+// A region missing from the map is an error, since an empty zone ID
+// would only turn up later as a broken alias record.
+func elbHostedZoneId(region string) (string, error) {
+	id, ok := elbHostedZoneIds[region]
+	if !ok {
+		return "", fmt.Errorf("No known ELB hosted zone ID for region %q", region)
+	}
+
+	return id, nil
+}
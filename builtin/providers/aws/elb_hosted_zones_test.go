@@ -0,0 +1,26 @@
+package aws
+
+import (
+	"testing"
+)
+
+func TestElbHostedZoneId(t *testing.T) {
+	// Every region the provider accepts must have a zone
+	for _, region := range validRegions {
+		id, err := elbHostedZoneId(region)
+		if err != nil {
+			t.Fatalf("%s: err: %s", region, err)
+		}
+		if id == "" {
+			t.Fatalf("%s: empty hosted zone ID", region)
+		}
+	}
+
+	if id, _ := elbHostedZoneId("us-west-2"); id != "Z1H1FL5HABSF5" {
+		t.Fatalf("bad: %s", id)
+	}
+
+	if _, err := elbHostedZoneId("mars-north-1"); err == nil {
+		t.Fatal("expected an error for an unknown region")
+	}
+}
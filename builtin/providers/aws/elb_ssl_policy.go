@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// elbSslPolicyType is the type of the policies managed by ssl_policy.
+const elbSslPolicyType = "SSLNegotiationPolicyType"
+
+// elbPolicyDescription is a policy of an ELB as returned by
+// DescribeLoadBalancerPolicies.
+type elbPolicyDescription struct {
+	PolicyName     string               `xml:"PolicyName"`
+	PolicyTypeName string               `xml:"PolicyTypeName"`
+	Attributes     []elbPolicyAttribute `xml:"PolicyAttributeDescriptions>member"`
+}
+
+type elbPolicyAttribute struct {
+	Name  string `xml:"AttributeName"`
+	Value string `xml:"AttributeValue"`
+}
+
+// readElbSslPolicies returns the ssl_policy of the ELB from the SSL
+// negotiation policies set on its listeners, so that a policy detached or
+// changed outside of Terraform shows up as a diff. known is the current
+// ssl_policy, which decides which attributes are read back.
+func readElbSslPolicies(conn *queryConn, name string, known []interface{}) ([]map[string]interface{}, error) {
+	listeners, err := describeElbListenerPolicies(conn, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ns := range listeners {
+		names = append(names, ns...)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	policies, err := describeElbPolicies(conn, name, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenElbSslPolicies(listeners, policies, known), nil
+}
+
+// describeElbListenerPolicies returns the names of the policies set on
+// each listener of the ELB, keyed by its lb_port.
+func describeElbListenerPolicies(conn *queryConn, name string) (map[int][]string, error) {
+	params := map[string]string{
+		"LoadBalancerNames.member.1": name,
+	}
+
+	var resp struct {
+		Listeners []struct {
+			LoadBalancerPort int      `xml:"Listener>LoadBalancerPort"`
+			PolicyNames      []string `xml:"PolicyNames>member"`
+		} `xml:"DescribeLoadBalancersResult>LoadBalancerDescriptions>member>ListenerDescriptions>member"`
+	}
+
+	log.Printf("[DEBUG] ELB describe listener policies: %s", name)
+	if err := conn.Request("DescribeLoadBalancers", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error retrieving ELB listener policies: %s", err)
+	}
+
+	result := make(map[int][]string, len(resp.Listeners))
+	for _, l := range resp.Listeners {
+		if len(l.PolicyNames) > 0 {
+			result[l.LoadBalancerPort] = l.PolicyNames
+		}
+	}
+
+	return result, nil
+}
+
+// describeElbPolicies fetches the named policies of the ELB.
+func describeElbPolicies(conn *queryConn, name string, policyNames []string) ([]elbPolicyDescription, error) {
+	params := map[string]string{
+		"LoadBalancerName": name,
+	}
+	for i, n := range policyNames {
+		params[fmt.Sprintf("PolicyNames.member.%d", i+1)] = n
+	}
+
+	var resp struct {
+		Policies []elbPolicyDescription `xml:"DescribeLoadBalancerPoliciesResult>PolicyDescriptions>member"`
+	}
+
+	log.Printf("[DEBUG] ELB describe policies: %#v", params)
+	if err := conn.Request("DescribeLoadBalancerPolicies", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error retrieving ELB policies: %s", err)
+	}
+
+	return resp.Policies, nil
+}
+
+// flattenElbSslPolicies returns an ssl_policy for each SSL negotiation
+// policy set on a listener. AWS reports every attribute of a policy,
+// including all of those a reference policy expands to, so like
+// additional_attributes only the attributes known for the policy are kept.
+func flattenElbSslPolicies(
+	listeners map[int][]string,
+	policies []elbPolicyDescription,
+	known []interface{}) []map[string]interface{} {
+	byName := make(map[string]elbPolicyDescription, len(policies))
+	for _, p := range policies {
+		byName[p.PolicyName] = p
+	}
+
+	knownAttrs := make(map[string]map[string]interface{}, len(known))
+	for _, raw := range known {
+		m := raw.(map[string]interface{})
+		key := fmt.Sprintf("%d-%s", m["lb_port"].(int), m["name"].(string))
+		if attrs, ok := m["attributes"].(map[string]interface{}); ok {
+			knownAttrs[key] = attrs
+		}
+	}
+
+	ports := make([]int, 0, len(listeners))
+	for port := range listeners {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	var result []map[string]interface{}
+	for _, port := range ports {
+		for _, name := range listeners[port] {
+			p, ok := byName[name]
+			if !ok || p.PolicyTypeName != elbSslPolicyType {
+				continue
+			}
+
+			configured := knownAttrs[fmt.Sprintf("%d-%s", port, name)]
+			reference := ""
+			attrs := make(map[string]interface{})
+			for _, a := range p.Attributes {
+				if a.Name == "Reference-Security-Policy" {
+					reference = a.Value
+					continue
+				}
+				if _, ok := configured[a.Name]; ok {
+					attrs[a.Name] = a.Value
+				}
+			}
+
+			result = append(result, map[string]interface{}{
+				"lb_port":                   port,
+				"name":                      name,
+				"reference_security_policy": reference,
+				"attributes":                attrs,
+			})
+		}
+	}
+
+	return result
+}
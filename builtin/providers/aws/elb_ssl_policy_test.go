@@ -0,0 +1,183 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestReadElbSslPolicies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("Action") {
+		case "DescribeLoadBalancers":
+			if q.Get("LoadBalancerNames.member.1") != "foo" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+
+			fmt.Fprint(w, `<DescribeLoadBalancersResponse>
+  <DescribeLoadBalancersResult>
+    <LoadBalancerDescriptions>
+      <member>
+        <ListenerDescriptions>
+          <member>
+            <Listener><LoadBalancerPort>80</LoadBalancerPort></Listener>
+            <PolicyNames/>
+          </member>
+          <member>
+            <Listener><LoadBalancerPort>443</LoadBalancerPort></Listener>
+            <PolicyNames><member>tls</member></PolicyNames>
+          </member>
+          <member>
+            <Listener><LoadBalancerPort>8443</LoadBalancerPort></Listener>
+            <PolicyNames><member>custom</member><member>cookies</member></PolicyNames>
+          </member>
+        </ListenerDescriptions>
+      </member>
+    </LoadBalancerDescriptions>
+  </DescribeLoadBalancersResult>
+</DescribeLoadBalancersResponse>`)
+		case "DescribeLoadBalancerPolicies":
+			if q.Get("LoadBalancerName") != "foo" || q.Get("PolicyNames.member.3") == "" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+
+			fmt.Fprint(w, `<DescribeLoadBalancerPoliciesResponse>
+  <DescribeLoadBalancerPoliciesResult>
+    <PolicyDescriptions>
+      <member>
+        <PolicyName>tls</PolicyName>
+        <PolicyTypeName>SSLNegotiationPolicyType</PolicyTypeName>
+        <PolicyAttributeDescriptions>
+          <member><AttributeName>Reference-Security-Policy</AttributeName><AttributeValue>ELBSecurityPolicy-2016-08</AttributeValue></member>
+          <member><AttributeName>Protocol-TLSv1.2</AttributeName><AttributeValue>true</AttributeValue></member>
+          <member><AttributeName>Protocol-SSLv3</AttributeName><AttributeValue>false</AttributeValue></member>
+        </PolicyAttributeDescriptions>
+      </member>
+      <member>
+        <PolicyName>custom</PolicyName>
+        <PolicyTypeName>SSLNegotiationPolicyType</PolicyTypeName>
+        <PolicyAttributeDescriptions>
+          <member><AttributeName>Protocol-TLSv1.2</AttributeName><AttributeValue>true</AttributeValue></member>
+          <member><AttributeName>Protocol-TLSv1</AttributeName><AttributeValue>true</AttributeValue></member>
+        </PolicyAttributeDescriptions>
+      </member>
+      <member>
+        <PolicyName>cookies</PolicyName>
+        <PolicyTypeName>LBCookieStickinessPolicyType</PolicyTypeName>
+      </member>
+    </PolicyDescriptions>
+  </DescribeLoadBalancerPoliciesResult>
+</DescribeLoadBalancerPoliciesResponse>`)
+		default:
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	// Protocol-TLSv1 was changed outside of Terraform, and the attributes
+	// the reference policy expands to were never configured
+	known := []interface{}{
+		map[string]interface{}{
+			"lb_port":                   443,
+			"name":                      "tls",
+			"reference_security_policy": "ELBSecurityPolicy-2016-08",
+			"attributes":                map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"lb_port":                   8443,
+			"name":                      "custom",
+			"reference_security_policy": "",
+			"attributes": map[string]interface{}{
+				"Protocol-TLSv1.2": "true",
+				"Protocol-TLSv1":   "false",
+			},
+		},
+	}
+
+	policies, err := readElbSslPolicies(conn, "foo", known)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []map[string]interface{}{
+		map[string]interface{}{
+			"lb_port":                   443,
+			"name":                      "tls",
+			"reference_security_policy": "ELBSecurityPolicy-2016-08",
+			"attributes":                map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"lb_port":                   8443,
+			"name":                      "custom",
+			"reference_security_policy": "",
+			"attributes": map[string]interface{}{
+				"Protocol-TLSv1.2": "true",
+				"Protocol-TLSv1":   "true",
+			},
+		},
+	}
+	if !reflect.DeepEqual(policies, expected) {
+		t.Fatalf("bad: %#v", policies)
+	}
+}
+
+func TestReadElbSslPolicies_none(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") != "DescribeLoadBalancers" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `<DescribeLoadBalancersResponse>
+  <DescribeLoadBalancersResult>
+    <LoadBalancerDescriptions>
+      <member>
+        <ListenerDescriptions>
+          <member>
+            <Listener><LoadBalancerPort>443</LoadBalancerPort></Listener>
+            <PolicyNames/>
+          </member>
+        </ListenerDescriptions>
+      </member>
+    </LoadBalancerDescriptions>
+  </DescribeLoadBalancersResult>
+</DescribeLoadBalancersResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	// The policy was detached outside of Terraform
+	known := []interface{}{
+		map[string]interface{}{
+			"lb_port":                   443,
+			"name":                      "tls",
+			"reference_security_policy": "ELBSecurityPolicy-2016-08",
+			"attributes":                map[string]interface{}{},
+		},
+	}
+
+	policies, err := readElbSslPolicies(conn, "foo", known)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(policies) != 0 {
+		t.Fatalf("bad: %#v", policies)
+	}
+}
@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/mitchellh/goamz/ec2"
+)
+
+// imageCache remembers the AMIs described in the region, so that several
+// resources using the same image in one run only describe it once. Images
+// that don't exist are cached as nil.
+type imageCache struct {
+	sync.Mutex
+	images map[string]*ec2.Image
+}
+
+// describeImage returns the AMI, or nil if it doesn't exist, or isn't
+// visible to us, in the client's region.
+func (c *AWSClient) describeImage(id string) (*ec2.Image, error) {
+	c.imageCache.Lock()
+	defer c.imageCache.Unlock()
+
+	if image, ok := c.imageCache.images[id]; ok {
+		return image, nil
+	}
+
+	log.Printf("[DEBUG] Describing image %s", id)
+	var image *ec2.Image
+	resp, err := c.ec2conn().Images([]string{id}, nil)
+	if err != nil {
+		ec2err, ok := err.(*ec2.Error)
+		if !ok || (ec2err.Code != "InvalidAMIID.NotFound" &&
+			ec2err.Code != "InvalidAMIID.Unavailable") {
+			return nil, fmt.Errorf("Error describing image %s: %s", id, err)
+		}
+	} else if len(resp.Images) > 0 {
+		// Deregistered images can be described without an error, but
+		// don't come back
+		image = &resp.Images[0]
+	}
+
+	if c.imageCache.images == nil {
+		c.imageCache.images = make(map[string]*ec2.Image)
+	}
+	c.imageCache.images[id] = image
+	return image, nil
+}
+
+// imageExists returns whether the AMI exists, and is visible to us, in the
+// client's region.
+func (c *AWSClient) imageExists(id string) (bool, error) {
+	image, err := c.describeImage(id)
+	return image != nil, err
+}
+
+// imageRootDeviceName returns the name of the root device of the AMI, or
+// an empty string if the AMI doesn't exist anymore.
+func (c *AWSClient) imageRootDeviceName(id string) (string, error) {
+	image, err := c.describeImage(id)
+	if err != nil || image == nil {
+		return "", err
+	}
+
+	return image.RootDeviceName, nil
+}
+
+// validateImage returns an error if the AMI doesn't exist in the client's
+// region. This catches an image from another region, or one that has been
+// deregistered, before it's used in a request that would otherwise fail
+// with a less helpful error, or not fail until instances are launched.
+func validateImage(c *AWSClient, id string) error {
+	exists, err := c.imageExists(id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf(
+			"AMI %s not found in region %s. AMIs are regional, so an image "+
+				"from another region has to be copied to this one to be used here",
+			id, c.ec2conn().Region.Name)
+	}
+
+	return nil
+}
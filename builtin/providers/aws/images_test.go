@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestValidateImage(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		r.ParseForm()
+		if r.Form.Get("Action") != "DescribeImages" {
+			t.Errorf("bad action: %s", r.Form.Get("Action"))
+		}
+
+		if r.Form.Get("ImageId.1") != "ami-1234" {
+			w.WriteHeader(400)
+			fmt.Fprintf(w, `<Response>
+  <Errors><Error><Code>InvalidAMIID.NotFound</Code><Message>The image id '[%s]' does not exist</Message></Error></Errors>
+  <RequestID>abc</RequestID>
+</Response>`, r.Form.Get("ImageId.1"))
+			return
+		}
+
+		fmt.Fprint(w, `<DescribeImagesResponse>
+  <imagesSet><item><imageId>ami-1234</imageId></item></imagesSet>
+</DescribeImagesResponse>`)
+	}))
+	defer ts.Close()
+
+	client := &AWSClient{
+		goamz: &goamzConns{
+			ec2conn: ec2.New(
+				aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+				aws.Region{Name: "us-east-1", EC2Endpoint: ts.URL}),
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := validateImage(client, "ami-1234"); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected the image to be described once, got %d", requests)
+	}
+
+	err := validateImage(client, "ami-5678")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "ami-5678 not found in region us-east-1") {
+		t.Fatalf("bad: %s", err)
+	}
+}
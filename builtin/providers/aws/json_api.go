@@ -0,0 +1,197 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+// jsonConn makes requests against an AWS JSON API, such as CloudWatch
+// Logs, which goamz doesn't have at all. These only take signature
+// version 4.
+type jsonConn struct {
+	auth         aws.Auth
+	region       string
+	service      string
+	endpoint     string
+	targetPrefix string
+	httpClient   *http.Client
+
+	// role, if set, signs requests with the credentials of the assumed
+	// role instead of auth
+	role *assumedRole
+}
+
+// Request calls the action with in as its JSON request. If out is non-nil
+// the JSON response is decoded into it. Error responses are returned as a
+// *queryError, so they can be told apart by their code like the others.
+func (c *jsonConn) Request(action string, in, out interface{}) error {
+	if in == nil {
+		in = struct{}{}
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", c.targetPrefix+"."+action)
+
+	auth, err := signingAuth(c.auth, c.role)
+	if err != nil {
+		return err
+	}
+	signV4(req, body, auth, c.region, c.service, time.Now())
+
+	log.Printf("[DEBUG] JSON API request %s to %s", action, u.Host)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return decodeJSONError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// decodeJSONError reads the error response of a JSON API. The type of
+// the error is its code, possibly prefixed with a namespace.
+func decodeJSONError(resp *http.Response) *queryError {
+	qerr := &queryError{
+		StatusCode: resp.StatusCode,
+		RequestId:  resp.Header.Get("X-Amzn-Requestid"),
+	}
+
+	var jerr struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &jerr)
+	}
+	if err != nil {
+		qerr.Code = http.StatusText(resp.StatusCode)
+		qerr.Message = err.Error()
+		return qerr
+	}
+
+	qerr.Code = jerr.Type[strings.LastIndex(jerr.Type, "#")+1:]
+	qerr.Message = jerr.Message
+	return qerr
+}
+
+// signV4 signs the request, with every header it has, using signature
+// version 4.
+func signV4(req *http.Request, body []byte, auth aws.Auth, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if auth.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", auth.Token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders bytes.Buffer
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Query parameters are signed sorted by name, then by value
+	query := req.URL.Query()
+	var params []string
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, v4Escape(k)+"="+v4Escape(v))
+		}
+	}
+	sort.Strings(params)
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.Join(params, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + auth.SecretKey)
+	for _, v := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		auth.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// v4Escape escapes a query parameter the way signature version 4 expects,
+// which leaves only the unreserved characters alone.
+func v4Escape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestSignV4(t *testing.T) {
+	// The example from the AWS signature version 4 documentation
+	req, err := http.NewRequest(
+		"GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	auth := aws.Auth{
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signV4(req, nil, auth, "us-east-1", "iam", now)
+
+	expected := "AWS4-HMAC-SHA256 " +
+		"Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if v := req.Header.Get("Authorization"); v != expected {
+		t.Fatalf("bad: %s", v)
+	}
+}
+
+func TestJSONConnRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "Logs_20140328.DescribeLogGroups" {
+			t.Errorf("bad target: %s", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=foo/") {
+			t.Errorf("bad authorization: %s", r.Header.Get("Authorization"))
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"logGroupNamePrefix":"foo"}` {
+			w.Header().Set("X-Amzn-RequestId", "1234")
+			w.WriteHeader(400)
+			fmt.Fprint(w, `{"__type":"com.amazonaws.logs#ResourceNotFoundException",`+
+				`"message":"The specified log group does not exist."}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"logGroups":[{"logGroupName":"foo"}]}`)
+	}))
+	defer ts.Close()
+
+	conn := &jsonConn{
+		auth:         aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		region:       "us-west-2",
+		service:      "logs",
+		endpoint:     ts.URL,
+		targetPrefix: "Logs_20140328",
+		httpClient:   http.DefaultClient,
+	}
+
+	var resp struct {
+		LogGroups []struct {
+			LogGroupName string `json:"logGroupName"`
+		} `json:"logGroups"`
+	}
+	in := map[string]string{"logGroupNamePrefix": "foo"}
+	if err := conn.Request("DescribeLogGroups", in, &resp); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(resp.LogGroups) != 1 || resp.LogGroups[0].LogGroupName != "foo" {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	err := conn.Request("DescribeLogGroups", nil, nil)
+	if !isAWSErr(err, "ResourceNotFoundException") {
+		t.Fatalf("bad: %#v", err)
+	}
+	if qerr := err.(*queryError); qerr.RequestId != "1234" || qerr.StatusCode != 400 {
+		t.Fatalf("bad: %#v", qerr)
+	}
+}
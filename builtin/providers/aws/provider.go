@@ -33,29 +33,127 @@ func Provider() terraform.ResourceProvider {
 				Description:  descriptions["region"],
 				InputDefault: "us-east-1",
 			},
+
+			"max_retries": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     11,
+				Description: descriptions["max_retries"],
+			},
+
+			"user_agent_suffix": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["user_agent_suffix"],
+			},
+
+			"endpoints": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ec2": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["ec2_endpoint"],
+						},
+
+						"elb": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["elb_endpoint"],
+						},
+
+						"s3": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["s3_endpoint"],
+						},
+
+						"autoscaling": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["autoscaling_endpoint"],
+						},
+
+						"sts": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["sts_endpoint"],
+						},
+					},
+				},
+			},
+
+			"assume_role": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_arn": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["assume_role_role_arn"],
+						},
+
+						"session_name": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "terraform",
+							Description: descriptions["assume_role_session_name"],
+						},
+
+						"external_id": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["assume_role_external_id"],
+						},
+					},
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"aws_autoscaling_group":       resourceAwsAutoscalingGroup(),
-			"aws_db_instance":             resourceAwsDbInstance(),
-			"aws_db_parameter_group":      resourceAwsDbParameterGroup(),
-			"aws_db_security_group":       resourceAwsDbSecurityGroup(),
-			"aws_db_subnet_group":         resourceAwsDbSubnetGroup(),
-			"aws_eip":                     resourceAwsEip(),
-			"aws_elb":                     resourceAwsElb(),
-			"aws_instance":                resourceAwsInstance(),
-			"aws_internet_gateway":        resourceAwsInternetGateway(),
-			"aws_key_pair":                resourceAwsKeyPair(),
-			"aws_launch_configuration":    resourceAwsLaunchConfiguration(),
-			"aws_network_acl":             resourceAwsNetworkAcl(),
-			"aws_route53_record":          resourceAwsRoute53Record(),
-			"aws_route53_zone":            resourceAwsRoute53Zone(),
-			"aws_route_table":             resourceAwsRouteTable(),
-			"aws_route_table_association": resourceAwsRouteTableAssociation(),
-			"aws_s3_bucket":               resourceAwsS3Bucket(),
-			"aws_security_group":          resourceAwsSecurityGroup(),
-			"aws_subnet":                  resourceAwsSubnet(),
-			"aws_vpc":                     resourceAwsVpc(),
+			"aws_ami_lookup":                   dataSourceAwsAmi(),
+			"aws_autoscaling_group":            resourceAwsAutoscalingGroup(),
+			"aws_autoscaling_lifecycle_hook":   resourceAwsAutoscalingLifecycleHook(),
+			"aws_autoscaling_policy":           resourceAwsAutoscalingPolicy(),
+			"aws_cloudwatch_log_group":         resourceAwsCloudwatchLogGroup(),
+			"aws_cloudwatch_metric_alarm":      resourceAwsCloudwatchMetricAlarm(),
+			"aws_db_instance":                  resourceAwsDbInstance(),
+			"aws_db_parameter_group":           resourceAwsDbParameterGroup(),
+			"aws_db_security_group":            resourceAwsDbSecurityGroup(),
+			"aws_db_subnet_group":              resourceAwsDbSubnetGroup(),
+			"aws_ebs_snapshot":                 resourceAwsEbsSnapshot(),
+			"aws_ebs_volume":                   resourceAwsEbsVolume(),
+			"aws_eip":                          resourceAwsEip(),
+			"aws_eip_association":              resourceAwsEipAssociation(),
+			"aws_elb":                          resourceAwsElb(),
+			"aws_iam_instance_profile":         resourceAwsIamInstanceProfile(),
+			"aws_iam_role":                     resourceAwsIamRole(),
+			"aws_iam_role_policy":              resourceAwsIamRolePolicy(),
+			"aws_instance":                     resourceAwsInstance(),
+			"aws_internet_gateway":             resourceAwsInternetGateway(),
+			"aws_key_pair":                     resourceAwsKeyPair(),
+			"aws_launch_configuration":         resourceAwsLaunchConfiguration(),
+			"aws_launch_configuration_lookup":  dataSourceAwsLaunchConfiguration(),
+			"aws_network_acl":                  resourceAwsNetworkAcl(),
+			"aws_route":                        resourceAwsRoute(),
+			"aws_route53_record":               resourceAwsRoute53Record(),
+			"aws_route53_zone":                 resourceAwsRoute53Zone(),
+			"aws_route_table":                  resourceAwsRouteTable(),
+			"aws_route_table_association":      resourceAwsRouteTableAssociation(),
+			"aws_s3_bucket":                    resourceAwsS3Bucket(),
+			"aws_s3_bucket_object":             resourceAwsS3BucketObject(),
+			"aws_s3_bucket_policy":             resourceAwsS3BucketPolicy(),
+			"aws_security_group":               resourceAwsSecurityGroup(),
+			"aws_security_group_lookup":        dataSourceAwsSecurityGroup(),
+			"aws_sns_topic":                    resourceAwsSnsTopic(),
+			"aws_subnet":                       resourceAwsSubnet(),
+			"aws_volume_attachment":            resourceAwsVolumeAttachment(),
+			"aws_vpc":                          resourceAwsVpc(),
+			"aws_vpc_dhcp_options":             resourceAwsVpcDhcpOptions(),
+			"aws_vpc_dhcp_options_association": resourceAwsVpcDhcpOptionsAssociation(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -74,14 +172,61 @@ func init() {
 
 		"secret_key": "The secret key for API operations. You can retrieve this\n" +
 			"from the 'Security & Credentials' section of the AWS console.",
+
+		"max_retries": "The maximum number of times an AWS API request is\n" +
+			"retried when it is throttled.",
+
+		"user_agent_suffix": "Text appended to the User-Agent of every AWS API\n" +
+			"request, to tell requests from this configuration apart.",
+
+		"ec2_endpoint": "Use this to override the default EC2 endpoint URL\n" +
+			"for the region, for example to test against a mock.",
+
+		"elb_endpoint": "Use this to override the default ELB endpoint URL\n" +
+			"for the region, for example to test against a mock.",
+
+		"s3_endpoint": "Use this to override the default S3 endpoint URL\n" +
+			"for the region, for example to test against a mock.",
+
+		"autoscaling_endpoint": "Use this to override the default AutoScaling\n" +
+			"endpoint URL for the region, for example to test against a mock.",
+
+		"sts_endpoint": "Use this to override the default STS endpoint URL,\n" +
+			"for example to test against a mock.",
+
+		"assume_role_role_arn": "The ARN of a role to assume. Resources are\n" +
+			"managed with the role's temporary credentials.",
+
+		"assume_role_session_name": "The session name to use when assuming\n" +
+			"the role. It shows up in CloudTrail.",
+
+		"assume_role_external_id": "The external ID the role requires to be\n" +
+			"assumed, if any.",
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
-		AccessKey: d.Get("access_key").(string),
-		SecretKey: d.Get("secret_key").(string),
-		Region:    d.Get("region").(string),
+		AccessKey:  d.Get("access_key").(string),
+		SecretKey:  d.Get("secret_key").(string),
+		Region:     d.Get("region").(string),
+		MaxRetries: d.Get("max_retries").(int),
+		Endpoints:  make(map[string]string),
+
+		UserAgentSuffix: d.Get("user_agent_suffix").(string),
+	}
+
+	if v := d.Get("endpoints").([]interface{}); len(v) > 0 && v[0] != nil {
+		for k, endpoint := range v[0].(map[string]interface{}) {
+			config.Endpoints[k] = endpoint.(string)
+		}
+	}
+
+	if v := d.Get("assume_role").([]interface{}); len(v) > 0 && v[0] != nil {
+		m := v[0].(map[string]interface{})
+		config.AssumeRoleARN = m["role_arn"].(string)
+		config.AssumeRoleSessionName = m["session_name"].(string)
+		config.AssumeRoleExternalID = m["external_id"].(string)
 	}
 
 	return config.Client()
@@ -0,0 +1,148 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+// queryConn makes requests against an AWS query API, such as the
+// AutoScaling or CloudWatch APIs, for the actions goamz doesn't have.
+// Requests are signed with signature version 2, like goamz does for the
+// same services.
+type queryConn struct {
+	auth       aws.Auth
+	endpoint   string
+	version    string
+	httpClient *http.Client
+
+	// role, if set, signs requests with the credentials of the assumed
+	// role instead of auth
+	role *assumedRole
+}
+
+// regionalEndpoint returns the endpoint of a service in the region, for
+// the services goamz doesn't know the endpoints of.
+func regionalEndpoint(service string, region aws.Region) string {
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region.Name, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+
+	return fmt.Sprintf("https://%s.%s.%s", service, region.Name, domain)
+}
+
+// iamEndpoint returns the endpoint of IAM for the region. IAM is global,
+// except that China and GovCloud have IAMs of their own.
+func iamEndpoint(region aws.Region) string {
+	switch {
+	case strings.HasPrefix(region.Name, "cn-"):
+		return regionalEndpoint("iam", region)
+	case strings.HasPrefix(region.Name, "us-gov-"):
+		return "https://iam.us-gov.amazonaws.com"
+	}
+
+	return "https://iam.amazonaws.com"
+}
+
+// queryError is an error response from a query API.
+type queryError struct {
+	StatusCode int
+	Type       string `xml:"Error>Type"`
+	Code       string `xml:"Error>Code"`
+	Message    string `xml:"Error>Message"`
+	RequestId  string `xml:"RequestId"`
+}
+
+// decodeQueryError reads the error response of a query API. EC2 wraps
+// its errors in an Errors element, where the others have just the one.
+func decodeQueryError(resp *http.Response) *queryError {
+	qerr := &queryError{StatusCode: resp.StatusCode}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		err = xml.Unmarshal(body, qerr)
+	}
+	if err != nil {
+		qerr.Code = http.StatusText(resp.StatusCode)
+		qerr.Message = err.Error()
+		return qerr
+	}
+
+	if qerr.Code == "" {
+		var ec2err struct {
+			Code      string `xml:"Errors>Error>Code"`
+			Message   string `xml:"Errors>Error>Message"`
+			RequestId string `xml:"RequestID"`
+		}
+		if err := xml.Unmarshal(body, &ec2err); err == nil {
+			qerr.Code = ec2err.Code
+			qerr.Message = ec2err.Message
+			qerr.RequestId = ec2err.RequestId
+		}
+	}
+
+	return qerr
+}
+
+func (e *queryError) Error() string {
+	return fmt.Sprintf("%s: %s (status code: %d, request id: %s)",
+		e.Code, e.Message, e.StatusCode, e.RequestId)
+}
+
+// Request calls the action with the given parameters. If out is non-nil
+// the XML response is decoded into it. Error responses are returned as a
+// *queryError.
+func (c *queryConn) Request(action string, params map[string]string, out interface{}) error {
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	signed := make(map[string]string, len(params)+3)
+	for k, v := range params {
+		signed[k] = v
+	}
+	signed["Action"] = action
+	signed["Version"] = c.version
+	signed["Timestamp"] = time.Now().UTC().Format(time.RFC3339)
+
+	auth, err := signingAuth(c.auth, c.role)
+	if err != nil {
+		return err
+	}
+	aws.Sign(auth, "GET", u.Path, signed, u.Host)
+
+	query := make(url.Values, len(signed))
+	for k, v := range signed {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	log.Printf("[DEBUG] Query API request %s to %s", action, u.Host)
+	resp, err := c.httpClient.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return decodeQueryError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return xml.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestQueryConnRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Version") != "2011-01-01" || q.Get("Signature") == "" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		switch q.Get("Action") {
+		case "PutScalingPolicy":
+			if q.Get("PolicyName") != "foo" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `<PutScalingPolicyResponse>
+  <PutScalingPolicyResult><PolicyARN>arn:foo</PolicyARN></PutScalingPolicyResult>
+</PutScalingPolicyResponse>`)
+		default:
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<ErrorResponse>
+  <Error><Type>Sender</Type><Code>ValidationError</Code><Message>Policy not found</Message></Error>
+  <RequestId>abc</RequestId>
+</ErrorResponse>`)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2011-01-01",
+		httpClient: http.DefaultClient,
+	}
+
+	var resp struct {
+		PolicyARN string `xml:"PutScalingPolicyResult>PolicyARN"`
+	}
+	err := conn.Request("PutScalingPolicy", map[string]string{"PolicyName": "foo"}, &resp)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if resp.PolicyARN != "arn:foo" {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	err = conn.Request("DeletePolicy", nil, nil)
+	qerr, ok := err.(*queryError)
+	if !ok {
+		t.Fatalf("expected a *queryError, got %#v", err)
+	}
+	if qerr.StatusCode != 400 || qerr.Code != "ValidationError" || qerr.RequestId != "abc" {
+		t.Fatalf("bad: %#v", qerr)
+	}
+	if !isAutoscalingNotFound(err) {
+		t.Fatalf("expected a not found error: %s", err)
+	}
+}
+
+func TestQueryConnRequest_ec2Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprint(w, `<Response>
+  <Errors><Error><Code>InvalidAMIID.NotFound</Code><Message>The image id does not exist</Message></Error></Errors>
+  <RequestID>abc</RequestID>
+</Response>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2014-10-01",
+		httpClient: http.DefaultClient,
+	}
+
+	err := conn.Request("DescribeImages", nil, nil)
+	qerr, ok := err.(*queryError)
+	if !ok {
+		t.Fatalf("expected a *queryError, got %#v", err)
+	}
+	if qerr.Code != "InvalidAMIID.NotFound" || qerr.RequestId != "abc" {
+		t.Fatalf("bad: %#v", qerr)
+	}
+}
@@ -89,7 +89,6 @@ func resourceAwsAutoscalingGroup() *schema.Resource {
 			"load_balancers": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
-				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set: func(v interface{}) int {
 					return hashcode.String(v.(string))
@@ -117,12 +116,16 @@ func resourceAwsAutoscalingGroup() *schema.Resource {
 					return hashcode.String(v.(string))
 				},
 			},
+
+			// An update waits up to this long for the group's instances
+			// to register with the load balancers it's attached to
+			"timeouts": timeoutsSchema("update"),
 		},
 	}
 }
 
 func resourceAwsAutoscalingGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	var autoScalingGroupOpts autoscaling.CreateAutoScalingGroup
 	autoScalingGroupOpts.Name = d.Get("name").(string)
@@ -134,6 +137,9 @@ func resourceAwsAutoscalingGroupCreate(d *schema.ResourceData, meta interface{})
 	autoScalingGroupOpts.SetMaxSize = true
 	autoScalingGroupOpts.AvailZone = expandStringList(
 		d.Get("availability_zones").(*schema.Set).List())
+	if err := validateAvailabilityZones(meta.(*AWSClient), autoScalingGroupOpts.AvailZone); err != nil {
+		return err
+	}
 
 	if v, ok := d.GetOk("default_cooldown"); ok {
 		autoScalingGroupOpts.DefaultCooldown = v.(int)
@@ -202,7 +208,7 @@ func resourceAwsAutoscalingGroupRead(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceAwsAutoscalingGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	opts := autoscaling.UpdateAutoScalingGroup{
 		Name: d.Id(),
@@ -230,11 +236,106 @@ func resourceAwsAutoscalingGroupUpdate(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error updating Autoscaling group: %s", err)
 	}
 
+	if d.HasChange("load_balancers") {
+		// Without a configured timeout we don't wait for the instances
+		// to register with the new load balancers
+		timeout, err := resourceTimeout(d, "update", 0)
+		if err != nil {
+			return err
+		}
+
+		o, n := d.GetChange("load_balancers")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+		attach := expandStringList(ns.Difference(os).List())
+		detach := expandStringList(os.Difference(ns).List())
+
+		err = updateAutoscalingGroupLoadBalancers(
+			meta.(*AWSClient).autoscalingquery, d.Id(), attach, detach)
+		if err != nil {
+			d.Partial(true)
+			return err
+		}
+
+		if timeout > 0 && len(attach) > 0 {
+			if err := resourceAwsAutoscalingGroupWaitForElbs(d, meta, attach, timeout); err != nil {
+				return err
+			}
+		}
+	}
+
 	return resourceAwsAutoscalingGroupRead(d, meta)
 }
 
+// updateAutoscalingGroupLoadBalancers attaches the group to the load
+// balancers in attach and detaches it from those in detach, in place.
+func updateAutoscalingGroupLoadBalancers(conn *queryConn, name string, attach, detach []string) error {
+	if len(attach) > 0 {
+		params := autoscalingLoadBalancerParams(name, attach)
+		log.Printf("[DEBUG] AutoScaling Group attach load balancers: %#v", params)
+		if err := conn.Request("AttachLoadBalancers", params, nil); err != nil {
+			return fmt.Errorf("Error attaching load balancers: %s", err)
+		}
+	}
+
+	if len(detach) > 0 {
+		params := autoscalingLoadBalancerParams(name, detach)
+		log.Printf("[DEBUG] AutoScaling Group detach load balancers: %#v", params)
+		if err := conn.Request("DetachLoadBalancers", params, nil); err != nil {
+			return fmt.Errorf("Error detaching load balancers: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func autoscalingLoadBalancerParams(name string, elbs []string) map[string]string {
+	params := map[string]string{
+		"AutoScalingGroupName": name,
+	}
+	for i, elb := range elbs {
+		params[fmt.Sprintf("LoadBalancerNames.member.%d", i+1)] = elb
+	}
+
+	return params
+}
+
+// resourceAwsAutoscalingGroupWaitForElbs waits for the group's instances
+// that are in service to register with each of the load balancers.
+func resourceAwsAutoscalingGroupWaitForElbs(
+	d *schema.ResourceData, meta interface{}, elbs []string, timeout time.Duration) error {
+	g, err := getAwsAutoscalingGroup(d, meta)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		return nil
+	}
+
+	var instances []string
+	for _, i := range g.Instances {
+		if i.LifecycleState == "InService" {
+			instances = append(instances, i.InstanceId)
+		}
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, name := range elbs {
+		err := resourceAwsElbWaitForInstances(
+			meta.(*AWSClient).elbconn(), name, instances, nil, deadline.Sub(time.Now()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func resourceAwsAutoscalingGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	// Read the autoscaling group first. If it doesn't exist, we're done.
 	// We need the group in order to check if there are instances attached.
@@ -279,7 +380,7 @@ func resourceAwsAutoscalingGroupDelete(d *schema.ResourceData, meta interface{})
 func getAwsAutoscalingGroup(
 	d *schema.ResourceData,
 	meta interface{}) (*autoscaling.AutoScalingGroup, error) {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	describeOpts := autoscaling.DescribeAutoScalingGroups{
 		Names: []string{d.Id()},
@@ -310,7 +411,7 @@ func getAwsAutoscalingGroup(
 }
 
 func resourceAwsAutoscalingGroupDrain(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	// First, set the capacity to zero so the group will drain
 	log.Printf("[DEBUG] Reducing autoscaling group capacity to zero")
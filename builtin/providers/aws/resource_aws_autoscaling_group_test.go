@@ -2,11 +2,16 @@ package aws
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/autoscaling"
+	"github.com/mitchellh/goamz/aws"
 )
 
 func TestAccAWSAutoScalingGroup_basic(t *testing.T) {
@@ -73,8 +78,85 @@ func TestAccAWSAutoScalingGroupWithLoadBalancer(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAWSAutoScalingGroup_addLoadBalancer(t *testing.T) {
+	var group autoscaling.AutoScalingGroup
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSAutoScalingGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSAutoScalingGroupConfigWithLoadBalancer,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoScalingGroupExists("aws_autoscaling_group.bar", &group),
+					testAccCheckAWSAutoScalingGroupLoadBalancers(
+						&group, "foobar-terraform-test"),
+				),
+			},
+
+			// The second ELB is attached to the group in place
+			resource.TestStep{
+				Config: testAccAWSAutoScalingGroupConfigWithLoadBalancers,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoScalingGroupExists("aws_autoscaling_group.bar", &group),
+					testAccCheckAWSAutoScalingGroupLoadBalancers(
+						&group, "foobar-terraform-test", "foobar-terraform-test-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestUpdateAutoscalingGroupLoadBalancers(t *testing.T) {
+	var requests []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		fmt.Fprint(w, `<Response></Response>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2011-01-01",
+		httpClient: http.DefaultClient,
+	}
+
+	err := updateAutoscalingGroupLoadBalancers(
+		conn, "foo", []string{"new-1", "new-2"}, []string{"old"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	expected := []map[string]string{
+		{
+			"Action":                     "AttachLoadBalancers",
+			"AutoScalingGroupName":       "foo",
+			"LoadBalancerNames.member.1": "new-1",
+			"LoadBalancerNames.member.2": "new-2",
+		},
+		{
+			"Action":                     "DetachLoadBalancers",
+			"AutoScalingGroupName":       "foo",
+			"LoadBalancerNames.member.1": "old",
+		},
+	}
+	for i, e := range expected {
+		for k, v := range e {
+			if requests[i].Get(k) != v {
+				t.Fatalf("%d: bad %s: %q, expected %q", i, k, requests[i].Get(k), v)
+			}
+		}
+	}
+}
+
 func testAccCheckAWSAutoScalingGroupDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).autoscalingconn
+	conn := testAccProvider.Meta().(*AWSClient).autoscalingconn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_autoscaling_group" {
@@ -155,6 +237,19 @@ func testAccCheckAWSAutoScalingGroupAttributesLoadBalancer(group *autoscaling.Au
 	}
 }
 
+func testAccCheckAWSAutoScalingGroupLoadBalancers(
+	group *autoscaling.AutoScalingGroup, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		names := append([]string(nil), group.LoadBalancerNames...)
+		sort.Strings(names)
+		if fmt.Sprintf("%v", names) != fmt.Sprintf("%v", expected) {
+			return fmt.Errorf("Bad load_balancers: %v, expected %v", names, expected)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckAWSAutoScalingGroupExists(n string, group *autoscaling.AutoScalingGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -166,7 +261,7 @@ func testAccCheckAWSAutoScalingGroupExists(n string, group *autoscaling.AutoScal
 			return fmt.Errorf("No AutoScaling Group ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).autoscalingconn
+		conn := testAccProvider.Meta().(*AWSClient).autoscalingconn()
 
 		describeOpts := autoscaling.DescribeAutoScalingGroups{
 			Names: []string{rs.Primary.ID},
@@ -264,3 +359,49 @@ resource "aws_autoscaling_group" "bar" {
   load_balancers = ["${aws_elb.bar.name}"]
 }
 `
+
+const testAccAWSAutoScalingGroupConfigWithLoadBalancers = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+}
+
+resource "aws_elb" "baz" {
+  name = "foobar-terraform-test-2"
+  availability_zones = ["us-west-2a"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+}
+
+resource "aws_launch_configuration" "foobar" {
+  name = "foobarautoscaling-terraform-test"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+}
+
+resource "aws_autoscaling_group" "bar" {
+  availability_zones = ["us-west-2a"]
+  name = "foobar3-terraform-test"
+  max_size = 5
+  min_size = 2
+  health_check_grace_period = 300
+  health_check_type = "ELB"
+  desired_capacity = 4
+  force_delete = true
+
+  launch_configuration = "${aws_launch_configuration.foobar.name}"
+  load_balancers = ["${aws_elb.bar.name}", "${aws_elb.baz.name}"]
+}
+`
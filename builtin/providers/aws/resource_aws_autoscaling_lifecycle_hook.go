@@ -0,0 +1,216 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsAutoscalingLifecycleHook() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsAutoscalingLifecycleHookPut,
+		Read:   resourceAwsAutoscalingLifecycleHookRead,
+		Update: resourceAwsAutoscalingLifecycleHookPut,
+		Delete: resourceAwsAutoscalingLifecycleHookDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"autoscaling_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"lifecycle_transition": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateAutoscalingLifecycleTransition,
+			},
+
+			"default_result": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateAutoscalingLifecycleHookDefaultResult,
+			},
+
+			"heartbeat_timeout": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"notification_target_arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"role_arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// autoscalingLifecycleTransitions are the instance state changes a
+// lifecycle hook can pause.
+var autoscalingLifecycleTransitions = []string{
+	"autoscaling:EC2_INSTANCE_LAUNCHING",
+	"autoscaling:EC2_INSTANCE_TERMINATING",
+}
+
+// autoscalingLifecycleHook is a lifecycle hook as returned by
+// DescribeLifecycleHooks.
+type autoscalingLifecycleHook struct {
+	LifecycleHookName     string `xml:"LifecycleHookName"`
+	AutoScalingGroupName  string `xml:"AutoScalingGroupName"`
+	LifecycleTransition   string `xml:"LifecycleTransition"`
+	DefaultResult         string `xml:"DefaultResult"`
+	HeartbeatTimeout      int    `xml:"HeartbeatTimeout"`
+	NotificationTargetARN string `xml:"NotificationTargetARN"`
+	RoleARN               string `xml:"RoleARN"`
+}
+
+// PutLifecycleHook creates the hook, or replaces the settings of an
+// existing one, so it serves as both create and update.
+func resourceAwsAutoscalingLifecycleHookPut(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	params := map[string]string{
+		"AutoScalingGroupName": d.Get("autoscaling_group_name").(string),
+		"LifecycleHookName":    d.Get("name").(string),
+		"LifecycleTransition":  d.Get("lifecycle_transition").(string),
+	}
+	if v, ok := d.GetOk("default_result"); ok {
+		params["DefaultResult"] = v.(string)
+	}
+	if v, ok := d.GetOk("heartbeat_timeout"); ok {
+		params["HeartbeatTimeout"] = strconv.Itoa(v.(int))
+	}
+
+	// An empty ARN removes the notification target, so it's sent whenever
+	// it changes, but left out on create.
+	for k, param := range map[string]string{
+		"notification_target_arn": "NotificationTargetARN",
+		"role_arn":                "RoleARN",
+	} {
+		if v := d.Get(k).(string); v != "" || d.HasChange(k) {
+			params[param] = v
+		}
+	}
+
+	log.Printf("[DEBUG] AutoScaling put lifecycle hook: %#v", params)
+	if err := conn.Request("PutLifecycleHook", params, nil); err != nil {
+		return fmt.Errorf("Error putting lifecycle hook: %s", err)
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	return resourceAwsAutoscalingLifecycleHookRead(d, meta)
+}
+
+func resourceAwsAutoscalingLifecycleHookRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	hook, err := describeAutoscalingLifecycleHook(
+		conn, d.Get("autoscaling_group_name").(string), d.Id())
+	if err != nil {
+		return err
+	}
+	if hook == nil {
+		log.Printf("[WARN] AutoScaling lifecycle hook %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("lifecycle_transition", hook.LifecycleTransition)
+	d.Set("default_result", hook.DefaultResult)
+	d.Set("heartbeat_timeout", hook.HeartbeatTimeout)
+	d.Set("notification_target_arn", hook.NotificationTargetARN)
+	d.Set("role_arn", hook.RoleARN)
+
+	return nil
+}
+
+func resourceAwsAutoscalingLifecycleHookDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	params := map[string]string{
+		"AutoScalingGroupName": d.Get("autoscaling_group_name").(string),
+		"LifecycleHookName":    d.Id(),
+	}
+
+	log.Printf("[DEBUG] AutoScaling delete lifecycle hook: %#v", params)
+	if err := conn.Request("DeleteLifecycleHook", params, nil); err != nil {
+		// The hook, or its whole group, is already gone
+		if isAutoscalingNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting lifecycle hook: %s", err)
+	}
+
+	return nil
+}
+
+// describeAutoscalingLifecycleHook returns the named lifecycle hook of the
+// group, or nil if it doesn't exist.
+func describeAutoscalingLifecycleHook(conn *queryConn, group, name string) (*autoscalingLifecycleHook, error) {
+	params := map[string]string{
+		"AutoScalingGroupName":        group,
+		"LifecycleHookNames.member.1": name,
+	}
+
+	var resp struct {
+		LifecycleHooks []autoscalingLifecycleHook `xml:"DescribeLifecycleHooksResult>LifecycleHooks>member"`
+	}
+
+	log.Printf("[DEBUG] AutoScaling describe lifecycle hooks: %#v", params)
+	if err := conn.Request("DescribeLifecycleHooks", params, &resp); err != nil {
+		if isAutoscalingNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving lifecycle hook: %s", err)
+	}
+
+	for _, h := range resp.LifecycleHooks {
+		if h.LifecycleHookName == name {
+			return &h, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func validateAutoscalingLifecycleTransition(v interface{}, k string) (ws []string, es []error) {
+	for _, t := range autoscalingLifecycleTransitions {
+		if v.(string) == t {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of %s, got %q",
+		k, strings.Join(autoscalingLifecycleTransitions, ", "), v))
+	return
+}
+
+func validateAutoscalingLifecycleHookDefaultResult(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "CONTINUE", "ABANDON":
+	default:
+		es = append(es, fmt.Errorf("%s must be CONTINUE or ABANDON, got %q", k, v))
+	}
+
+	return
+}
@@ -0,0 +1,141 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSAutoscalingLifecycleHook_basic(t *testing.T) {
+	var hook autoscalingLifecycleHook
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSAutoscalingLifecycleHookDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSAutoscalingLifecycleHookConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoscalingLifecycleHookExists("aws_autoscaling_lifecycle_hook.foobar", &hook),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "lifecycle_transition", "autoscaling:EC2_INSTANCE_LAUNCHING"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "default_result", "CONTINUE"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "heartbeat_timeout", "2000"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSAutoscalingLifecycleHookConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoscalingLifecycleHookExists("aws_autoscaling_lifecycle_hook.foobar", &hook),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "lifecycle_transition", "autoscaling:EC2_INSTANCE_TERMINATING"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "default_result", "ABANDON"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_lifecycle_hook.foobar", "heartbeat_timeout", "600"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateAutoscalingLifecycleTransition(t *testing.T) {
+	for _, v := range []string{"autoscaling:EC2_INSTANCE_LAUNCHING", "autoscaling:EC2_INSTANCE_TERMINATING"} {
+		if _, es := validateAutoscalingLifecycleTransition(v, "lifecycle_transition"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "EC2_INSTANCE_LAUNCHING", "autoscaling:ec2_instance_launching", "autoscaling:TEST_NOTIFICATION"} {
+		if _, es := validateAutoscalingLifecycleTransition(v, "lifecycle_transition"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
+
+func TestValidateAutoscalingLifecycleHookDefaultResult(t *testing.T) {
+	for _, v := range []string{"CONTINUE", "ABANDON"} {
+		if _, es := validateAutoscalingLifecycleHookDefaultResult(v, "default_result"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "continue", "FAIL"} {
+		if _, es := validateAutoscalingLifecycleHookDefaultResult(v, "default_result"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
+
+func testAccCheckAWSAutoscalingLifecycleHookDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).autoscalingquery
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_autoscaling_lifecycle_hook" {
+			continue
+		}
+
+		hook, err := describeAutoscalingLifecycleHook(
+			conn, rs.Primary.Attributes["autoscaling_group_name"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if hook != nil {
+			return fmt.Errorf("Lifecycle hook still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSAutoscalingLifecycleHookExists(n string, hook *autoscalingLifecycleHook) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No lifecycle hook ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).autoscalingquery
+		h, err := describeAutoscalingLifecycleHook(
+			conn, rs.Primary.Attributes["autoscaling_group_name"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if h == nil {
+			return fmt.Errorf("Lifecycle hook not found")
+		}
+
+		*hook = *h
+		return nil
+	}
+}
+
+const testAccAWSAutoscalingLifecycleHookConfig = testAccAWSAutoscalingPolicyConfigGroup + `
+resource "aws_autoscaling_lifecycle_hook" "foobar" {
+  name = "foobar-terraform-test"
+  autoscaling_group_name = "${aws_autoscaling_group.bar.name}"
+  lifecycle_transition = "autoscaling:EC2_INSTANCE_LAUNCHING"
+  default_result = "CONTINUE"
+  heartbeat_timeout = 2000
+}
+`
+
+const testAccAWSAutoscalingLifecycleHookConfigUpdate = testAccAWSAutoscalingPolicyConfigGroup + `
+resource "aws_autoscaling_lifecycle_hook" "foobar" {
+  name = "foobar-terraform-test"
+  autoscaling_group_name = "${aws_autoscaling_group.bar.name}"
+  lifecycle_transition = "autoscaling:EC2_INSTANCE_TERMINATING"
+  default_result = "ABANDON"
+  heartbeat_timeout = 600
+}
+`
@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsAutoscalingPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsAutoscalingPolicyPut,
+		Read:   resourceAwsAutoscalingPolicyRead,
+		Update: resourceAwsAutoscalingPolicyPut,
+		Delete: resourceAwsAutoscalingPolicyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"autoscaling_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"adjustment_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateAutoscalingAdjustmentType,
+			},
+
+			"scaling_adjustment": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"cooldown": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// autoscalingAdjustmentTypes are the ways a scaling policy can change the
+// capacity of its group.
+var autoscalingAdjustmentTypes = []string{
+	"ChangeInCapacity",
+	"ExactCapacity",
+	"PercentChangeInCapacity",
+}
+
+// autoscalingPolicy is a scaling policy as returned by DescribePolicies.
+type autoscalingPolicy struct {
+	PolicyARN            string `xml:"PolicyARN"`
+	PolicyName           string `xml:"PolicyName"`
+	AutoScalingGroupName string `xml:"AutoScalingGroupName"`
+	AdjustmentType       string `xml:"AdjustmentType"`
+	ScalingAdjustment    int    `xml:"ScalingAdjustment"`
+	Cooldown             int    `xml:"Cooldown"`
+}
+
+// PutScalingPolicy creates the policy, or replaces the settings of an
+// existing one, so it serves as both create and update.
+func resourceAwsAutoscalingPolicyPut(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	params := map[string]string{
+		"AutoScalingGroupName": d.Get("autoscaling_group_name").(string),
+		"PolicyName":           d.Get("name").(string),
+		"AdjustmentType":       d.Get("adjustment_type").(string),
+		"ScalingAdjustment":    strconv.Itoa(d.Get("scaling_adjustment").(int)),
+	}
+	if v, ok := d.GetOk("cooldown"); ok {
+		params["Cooldown"] = strconv.Itoa(v.(int))
+	}
+
+	var resp struct {
+		PolicyARN string `xml:"PutScalingPolicyResult>PolicyARN"`
+	}
+
+	log.Printf("[DEBUG] AutoScaling put scaling policy: %#v", params)
+	if err := conn.Request("PutScalingPolicy", params, &resp); err != nil {
+		return fmt.Errorf("Error putting scaling policy: %s", err)
+	}
+
+	d.SetId(d.Get("name").(string))
+	d.Set("arn", resp.PolicyARN)
+	log.Printf("[INFO] AutoScaling scaling policy ARN: %s", resp.PolicyARN)
+
+	return resourceAwsAutoscalingPolicyRead(d, meta)
+}
+
+func resourceAwsAutoscalingPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	policy, err := describeAutoscalingPolicy(
+		conn, d.Get("autoscaling_group_name").(string), d.Id())
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		log.Printf("[WARN] AutoScaling scaling policy %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("adjustment_type", policy.AdjustmentType)
+	d.Set("scaling_adjustment", policy.ScalingAdjustment)
+	d.Set("cooldown", policy.Cooldown)
+	d.Set("arn", policy.PolicyARN)
+
+	return nil
+}
+
+func resourceAwsAutoscalingPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).autoscalingquery
+
+	params := map[string]string{
+		"AutoScalingGroupName": d.Get("autoscaling_group_name").(string),
+		"PolicyName":           d.Id(),
+	}
+
+	log.Printf("[DEBUG] AutoScaling delete scaling policy: %#v", params)
+	if err := conn.Request("DeletePolicy", params, nil); err != nil {
+		// The policy, or its whole group, is already gone
+		if isAutoscalingNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting scaling policy: %s", err)
+	}
+
+	return nil
+}
+
+// describeAutoscalingPolicy returns the named scaling policy of the group,
+// or nil if it doesn't exist.
+func describeAutoscalingPolicy(conn *queryConn, group, name string) (*autoscalingPolicy, error) {
+	params := map[string]string{
+		"AutoScalingGroupName": group,
+		"PolicyNames.member.1": name,
+	}
+
+	var resp struct {
+		ScalingPolicies []autoscalingPolicy `xml:"DescribePoliciesResult>ScalingPolicies>member"`
+	}
+
+	log.Printf("[DEBUG] AutoScaling describe scaling policies: %#v", params)
+	if err := conn.Request("DescribePolicies", params, &resp); err != nil {
+		if isAutoscalingNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving scaling policy: %s", err)
+	}
+
+	for _, p := range resp.ScalingPolicies {
+		if p.PolicyName == name {
+			return &p, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// isAutoscalingNotFound returns true if the error is AutoScaling reporting
+// that something doesn't exist. It has no error code of its own for this.
+func isAutoscalingNotFound(err error) bool {
+	qerr, ok := err.(*queryError)
+	return ok && qerr.Code == "ValidationError" &&
+		strings.Contains(strings.ToLower(qerr.Message), "not found")
+}
+
+func validateAutoscalingAdjustmentType(v interface{}, k string) (ws []string, es []error) {
+	for _, t := range autoscalingAdjustmentTypes {
+		if v.(string) == t {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of %s, got %q",
+		k, strings.Join(autoscalingAdjustmentTypes, ", "), v))
+	return
+}
@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSAutoscalingPolicy_basic(t *testing.T) {
+	var policy autoscalingPolicy
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSAutoscalingPolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSAutoscalingPolicyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoscalingPolicyExists("aws_autoscaling_policy.foobar", &policy),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_policy.foobar", "adjustment_type", "ChangeInCapacity"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_policy.foobar", "scaling_adjustment", "2"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_policy.foobar", "cooldown", "300"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSAutoscalingPolicyConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSAutoscalingPolicyExists("aws_autoscaling_policy.foobar", &policy),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_policy.foobar", "adjustment_type", "PercentChangeInCapacity"),
+					resource.TestCheckResourceAttr(
+						"aws_autoscaling_policy.foobar", "scaling_adjustment", "-20"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateAutoscalingAdjustmentType(t *testing.T) {
+	for _, v := range []string{"ChangeInCapacity", "ExactCapacity", "PercentChangeInCapacity"} {
+		if _, es := validateAutoscalingAdjustmentType(v, "adjustment_type"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "changeincapacity", "PercentChange"} {
+		if _, es := validateAutoscalingAdjustmentType(v, "adjustment_type"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
+
+func testAccCheckAWSAutoscalingPolicyDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).autoscalingquery
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_autoscaling_policy" {
+			continue
+		}
+
+		policy, err := describeAutoscalingPolicy(
+			conn, rs.Primary.Attributes["autoscaling_group_name"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			return fmt.Errorf("Scaling policy still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSAutoscalingPolicyExists(n string, policy *autoscalingPolicy) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No scaling policy ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).autoscalingquery
+		p, err := describeAutoscalingPolicy(
+			conn, rs.Primary.Attributes["autoscaling_group_name"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("Scaling policy not found")
+		}
+
+		if p.PolicyARN != rs.Primary.Attributes["arn"] {
+			return fmt.Errorf("Bad ARN: %s", rs.Primary.Attributes["arn"])
+		}
+
+		*policy = *p
+		return nil
+	}
+}
+
+const testAccAWSAutoscalingPolicyConfigGroup = `
+resource "aws_launch_configuration" "foobar" {
+  name = "foobarautoscaling-terraform-test"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+}
+
+resource "aws_autoscaling_group" "bar" {
+  availability_zones = ["us-west-2a"]
+  name = "foobar3-terraform-test"
+  max_size = 5
+  min_size = 2
+  force_delete = true
+
+  launch_configuration = "${aws_launch_configuration.foobar.name}"
+}
+`
+
+const testAccAWSAutoscalingPolicyConfig = testAccAWSAutoscalingPolicyConfigGroup + `
+resource "aws_autoscaling_policy" "foobar" {
+  name = "foobar3-terraform-test"
+  autoscaling_group_name = "${aws_autoscaling_group.bar.name}"
+  adjustment_type = "ChangeInCapacity"
+  scaling_adjustment = 2
+  cooldown = 300
+}
+`
+
+const testAccAWSAutoscalingPolicyConfigUpdate = testAccAWSAutoscalingPolicyConfigGroup + `
+resource "aws_autoscaling_policy" "foobar" {
+  name = "foobar3-terraform-test"
+  autoscaling_group_name = "${aws_autoscaling_group.bar.name}"
+  adjustment_type = "PercentChangeInCapacity"
+  scaling_adjustment = -20
+  cooldown = 300
+}
+`
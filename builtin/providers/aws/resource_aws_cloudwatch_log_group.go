@@ -0,0 +1,332 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceAwsCloudwatchLogGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsCloudwatchLogGroupCreate,
+		Read:   resourceAwsCloudwatchLogGroupRead,
+		Update: resourceAwsCloudwatchLogGroupUpdate,
+		Delete: resourceAwsCloudwatchLogGroupDelete,
+
+		ValidateFunc: resourceAwsCloudwatchLogGroupValidate,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCloudwatchLogGroupName,
+			},
+
+			"name_prefix": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCloudwatchLogGroupNamePrefix,
+			},
+
+			// Zero keeps the events forever
+			"retention_in_days": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateCloudwatchLogGroupRetention,
+			},
+
+			"tags": tagsSchema(),
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// cloudwatchLogGroup is a log group as returned by DescribeLogGroups.
+type cloudwatchLogGroup struct {
+	Arn             string `json:"arn"`
+	LogGroupName    string `json:"logGroupName"`
+	RetentionInDays int    `json:"retentionInDays"`
+}
+
+// cloudwatchLogGroupRetentions are the only retention periods CloudWatch
+// Logs accepts, in days.
+var cloudwatchLogGroupRetentions = []int{
+	1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 3653,
+}
+
+func resourceAwsCloudwatchLogGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).logsconn
+
+	var name string
+	if v, ok := d.GetOk("name"); ok {
+		name = v.(string)
+	} else if v, ok := d.GetOk("name_prefix"); ok {
+		name = resource.PrefixedUniqueId(v.(string))
+	} else {
+		name = resource.UniqueId()
+	}
+
+	params := struct {
+		LogGroupName string            `json:"logGroupName"`
+		Tags         map[string]string `json:"tags,omitempty"`
+	}{
+		LogGroupName: name,
+	}
+	if v := d.Get("tags").(map[string]interface{}); len(v) > 0 {
+		params.Tags = tagsToMap(tagsFromMap(v))
+	}
+
+	log.Printf("[DEBUG] CloudWatch Logs create log group: %#v", params)
+	if err := conn.Request("CreateLogGroup", params, nil); err != nil {
+		return fmt.Errorf("Error creating CloudWatch log group: %s", err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO] CloudWatch log group ID: %s", d.Id())
+
+	// New log groups keep their events forever, so only a retention
+	// period has to be set
+	if v := d.Get("retention_in_days").(int); v > 0 {
+		if err := putCloudwatchLogGroupRetention(conn, name, v); err != nil {
+			return err
+		}
+	}
+
+	return resourceAwsCloudwatchLogGroupRead(d, meta)
+}
+
+func resourceAwsCloudwatchLogGroupRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).logsconn
+
+	group, err := describeCloudwatchLogGroup(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		log.Printf("[WARN] CloudWatch log group %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	params := map[string]string{
+		"logGroupName": d.Id(),
+	}
+
+	var resp struct {
+		Tags map[string]string `json:"tags"`
+	}
+
+	log.Printf("[DEBUG] CloudWatch Logs list log group tags: %s", d.Id())
+	if err := conn.Request("ListTagsLogGroup", params, &resp); err != nil {
+		return fmt.Errorf("Error listing tags of CloudWatch log group %s: %s", d.Id(), err)
+	}
+
+	d.Set("name", group.LogGroupName)
+	d.Set("retention_in_days", group.RetentionInDays)
+	d.Set("tags", resp.Tags)
+
+	// The ARN covers the log streams in the group, which isn't what
+	// policies granting access to the group itself want
+	d.Set("arn", strings.TrimSuffix(group.Arn, ":*"))
+
+	return nil
+}
+
+func resourceAwsCloudwatchLogGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).logsconn
+
+	if d.HasChange("retention_in_days") {
+		if v := d.Get("retention_in_days").(int); v > 0 {
+			if err := putCloudwatchLogGroupRetention(conn, d.Id(), v); err != nil {
+				return err
+			}
+		} else {
+			params := map[string]string{
+				"logGroupName": d.Id(),
+			}
+
+			log.Printf("[DEBUG] CloudWatch Logs delete retention policy: %s", d.Id())
+			if err := conn.Request("DeleteRetentionPolicy", params, nil); err != nil {
+				return fmt.Errorf(
+					"Error removing retention of CloudWatch log group %s: %s", d.Id(), err)
+			}
+		}
+	}
+
+	if d.HasChange("tags") {
+		o, n := d.GetChange("tags")
+		create, remove := diffTags(
+			tagsFromMap(o.(map[string]interface{})), tagsFromMap(n.(map[string]interface{})))
+
+		if len(remove) > 0 {
+			var keys []string
+			for _, t := range remove {
+				keys = append(keys, t.Key)
+			}
+			params := struct {
+				LogGroupName string   `json:"logGroupName"`
+				Tags         []string `json:"tags"`
+			}{d.Id(), keys}
+
+			log.Printf("[DEBUG] CloudWatch Logs untag log group: %#v", params)
+			if err := conn.Request("UntagLogGroup", params, nil); err != nil {
+				return fmt.Errorf(
+					"Error removing tags of CloudWatch log group %s: %s", d.Id(), err)
+			}
+		}
+
+		if len(create) > 0 {
+			params := struct {
+				LogGroupName string            `json:"logGroupName"`
+				Tags         map[string]string `json:"tags"`
+			}{d.Id(), tagsToMap(create)}
+
+			log.Printf("[DEBUG] CloudWatch Logs tag log group: %#v", params)
+			if err := conn.Request("TagLogGroup", params, nil); err != nil {
+				return fmt.Errorf(
+					"Error tagging CloudWatch log group %s: %s", d.Id(), err)
+			}
+		}
+	}
+
+	return resourceAwsCloudwatchLogGroupRead(d, meta)
+}
+
+func resourceAwsCloudwatchLogGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).logsconn
+
+	params := map[string]string{
+		"logGroupName": d.Id(),
+	}
+
+	log.Printf("[DEBUG] CloudWatch Logs delete log group: %s", d.Id())
+	if err := conn.Request("DeleteLogGroup", params, nil); err != nil {
+		if isAWSErr(err, "ResourceNotFoundException") {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting CloudWatch log group %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsCloudwatchLogGroupValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	if c.IsSet("name") && c.IsSet("name_prefix") {
+		es = append(es, fmt.Errorf("Only one of name or name_prefix can be set"))
+	}
+
+	return
+}
+
+// describeCloudwatchLogGroup returns the named log group, or nil if it
+// doesn't exist. Log groups can only be looked up by prefix, so this
+// follows the pages of the response until it finds the exact name.
+func describeCloudwatchLogGroup(conn *jsonConn, name string) (*cloudwatchLogGroup, error) {
+	params := map[string]string{
+		"logGroupNamePrefix": name,
+	}
+	for {
+		var resp struct {
+			LogGroups []cloudwatchLogGroup `json:"logGroups"`
+			NextToken string               `json:"nextToken"`
+		}
+
+		log.Printf("[DEBUG] CloudWatch Logs describe log groups: %#v", params)
+		if err := conn.Request("DescribeLogGroups", params, &resp); err != nil {
+			return nil, fmt.Errorf("Error retrieving CloudWatch log group %s: %s", name, err)
+		}
+
+		for _, group := range resp.LogGroups {
+			if group.LogGroupName == name {
+				return &group, nil
+			}
+		}
+
+		if resp.NextToken == "" {
+			return nil, nil
+		}
+		params["nextToken"] = resp.NextToken
+	}
+}
+
+func putCloudwatchLogGroupRetention(conn *jsonConn, name string, days int) error {
+	params := struct {
+		LogGroupName    string `json:"logGroupName"`
+		RetentionInDays int    `json:"retentionInDays"`
+	}{name, days}
+
+	log.Printf("[DEBUG] CloudWatch Logs put retention policy: %#v", params)
+	if err := conn.Request("PutRetentionPolicy", params, nil); err != nil {
+		return fmt.Errorf("Error setting retention of CloudWatch log group %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// Log group names can be up to 512 characters long, of letters, digits
+// and "._-/#".
+func validateCloudwatchLogGroupName(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if len(value) < 1 || len(value) > 512 {
+		es = append(es, fmt.Errorf(
+			"%s must be between 1 and 512 characters long, is %d", k, len(value)))
+	}
+	es = append(es, validateCloudwatchLogGroupNameChars(value, k)...)
+
+	return
+}
+
+func validateCloudwatchLogGroupNamePrefix(v interface{}, k string) (ws []string, es []error) {
+	max := 512 - resource.UniqueIdSuffixLength
+	if len(v.(string)) > max {
+		es = append(es, fmt.Errorf(
+			"%s can be at most %d characters long, is %d", k, max, len(v.(string))))
+	}
+	es = append(es, validateCloudwatchLogGroupNameChars(v.(string), k)...)
+
+	return
+}
+
+func validateCloudwatchLogGroupNameChars(value, k string) (es []error) {
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.' || r == '_' || r == '-' || r == '/' || r == '#':
+		default:
+			es = append(es, fmt.Errorf(
+				"%s can only contain letters, digits and \"._-/#\", got %q", k, value))
+			return
+		}
+	}
+
+	return
+}
+
+func validateCloudwatchLogGroupRetention(v interface{}, k string) (ws []string, es []error) {
+	days := v.(int)
+	if days == 0 {
+		return
+	}
+	for _, valid := range cloudwatchLogGroupRetentions {
+		if days == valid {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be 0 or one of %v, got %d", k, cloudwatchLogGroupRetentions, days))
+	return
+}
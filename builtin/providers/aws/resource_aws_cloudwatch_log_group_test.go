@@ -0,0 +1,228 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSCloudWatchLogGroup_basic(t *testing.T) {
+	var group cloudwatchLogGroup
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSCloudWatchLogGroupDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSCloudWatchLogGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSCloudWatchLogGroupExists("aws_cloudwatch_log_group.foo", &group),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_log_group.foo", "name", "terraform-test-foo"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_log_group.foo", "retention_in_days", "30"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_log_group.foo", "tags.Name", "foo"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSCloudWatchLogGroupConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSCloudWatchLogGroupExists("aws_cloudwatch_log_group.foo", &group),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_log_group.foo", "retention_in_days", "365"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_log_group.foo", "tags.Name", "bar"),
+				),
+			},
+
+			// Zero removes the retention policy altogether
+			resource.TestStep{
+				Config: testAccAWSCloudWatchLogGroupConfigNoRetention,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSCloudWatchLogGroupExists("aws_cloudwatch_log_group.foo", &group),
+					func(*terraform.State) error {
+						if group.RetentionInDays != 0 {
+							return fmt.Errorf("bad retention: %d", group.RetentionInDays)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDescribeCloudwatchLogGroup(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("err: %s", err)
+		}
+
+		// The first page only has groups the name is a prefix of
+		if req["nextToken"] == "" {
+			fmt.Fprint(w, `{"logGroups":[{"logGroupName":"foo-bar"}],"nextToken":"1"}`)
+			return
+		}
+		fmt.Fprint(w, `{"logGroups":[{"logGroupName":"foo",`+
+			`"arn":"arn:aws:logs:us-west-2:123456789012:log-group:foo:*",`+
+			`"retentionInDays":7}]}`)
+	}))
+	defer ts.Close()
+
+	conn := &jsonConn{
+		auth:         aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		region:       "us-west-2",
+		service:      "logs",
+		endpoint:     ts.URL,
+		targetPrefix: "Logs_20140328",
+		httpClient:   http.DefaultClient,
+	}
+
+	group, err := describeCloudwatchLogGroup(conn, "foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if group == nil || group.LogGroupName != "foo" || group.RetentionInDays != 7 {
+		t.Fatalf("bad: %#v", group)
+	}
+
+	// Log groups that aren't there are nothing rather than an error
+	group, err = describeCloudwatchLogGroup(conn, "foo-")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if group != nil {
+		t.Fatalf("bad: %#v", group)
+	}
+}
+
+func TestValidateCloudwatchLogGroupName(t *testing.T) {
+	valid := []string{
+		"foo",
+		"/aws/lambda/foo_bar-1.baz#2",
+		strings.Repeat("a", 512),
+	}
+	for _, v := range valid {
+		if _, es := validateCloudwatchLogGroupName(v, "name"); len(es) > 0 {
+			t.Fatalf("%q should be valid: %v", v, es)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"foo bar",
+		"foo:bar",
+		strings.Repeat("a", 513),
+	}
+	for _, v := range invalid {
+		if _, es := validateCloudwatchLogGroupName(v, "name"); len(es) == 0 {
+			t.Fatalf("%q should be invalid", v)
+		}
+	}
+
+	max := 512 - resource.UniqueIdSuffixLength
+	if _, es := validateCloudwatchLogGroupNamePrefix(strings.Repeat("a", max), "name_prefix"); len(es) > 0 {
+		t.Fatalf("bad: %v", es)
+	}
+	if _, es := validateCloudwatchLogGroupNamePrefix(strings.Repeat("a", max+1), "name_prefix"); len(es) == 0 {
+		t.Fatal("prefix should be too long")
+	}
+}
+
+func TestValidateCloudwatchLogGroupRetention(t *testing.T) {
+	for _, v := range []int{0, 1, 30, 3653} {
+		if _, es := validateCloudwatchLogGroupRetention(v, "retention_in_days"); len(es) > 0 {
+			t.Fatalf("%d: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []int{-1, 2, 31, 3654} {
+		if _, es := validateCloudwatchLogGroupRetention(v, "retention_in_days"); len(es) == 0 {
+			t.Fatalf("%d: expected an error", v)
+		}
+	}
+}
+
+func testAccCheckAWSCloudWatchLogGroupDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).logsconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_cloudwatch_log_group" {
+			continue
+		}
+
+		group, err := describeCloudwatchLogGroup(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if group != nil {
+			return fmt.Errorf("CloudWatch log group still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSCloudWatchLogGroupExists(n string, group *cloudwatchLogGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No CloudWatch log group ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).logsconn
+		found, err := describeCloudwatchLogGroup(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return fmt.Errorf("CloudWatch log group not found")
+		}
+
+		*group = *found
+		return nil
+	}
+}
+
+const testAccAWSCloudWatchLogGroupConfig = `
+resource "aws_cloudwatch_log_group" "foo" {
+	name = "terraform-test-foo"
+	retention_in_days = 30
+
+	tags {
+		Name = "foo"
+	}
+}
+`
+
+const testAccAWSCloudWatchLogGroupConfigUpdate = `
+resource "aws_cloudwatch_log_group" "foo" {
+	name = "terraform-test-foo"
+	retention_in_days = 365
+
+	tags {
+		Name = "bar"
+	}
+}
+`
+
+const testAccAWSCloudWatchLogGroupConfigNoRetention = `
+resource "aws_cloudwatch_log_group" "foo" {
+	name = "terraform-test-foo"
+}
+`
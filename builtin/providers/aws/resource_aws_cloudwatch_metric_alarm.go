@@ -0,0 +1,264 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsCloudwatchMetricAlarm() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsCloudwatchMetricAlarmPut,
+		Read:   resourceAwsCloudwatchMetricAlarmRead,
+		Update: resourceAwsCloudwatchMetricAlarmPut,
+		Delete: resourceAwsCloudwatchMetricAlarmDelete,
+
+		Schema: map[string]*schema.Schema{
+			"alarm_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"comparison_operator": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCloudwatchComparisonOperator,
+			},
+
+			"evaluation_periods": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"metric_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"namespace": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"period": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"statistic": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCloudwatchStatistic,
+			},
+
+			"threshold": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Required: true,
+			},
+
+			"dimensions": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+
+			"alarm_actions": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(v.(string))
+				},
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+var cloudwatchComparisonOperators = []string{
+	"GreaterThanOrEqualToThreshold",
+	"GreaterThanThreshold",
+	"LessThanThreshold",
+	"LessThanOrEqualToThreshold",
+}
+
+var cloudwatchStatistics = []string{
+	"SampleCount",
+	"Average",
+	"Sum",
+	"Minimum",
+	"Maximum",
+}
+
+// cloudwatchMetricAlarm is an alarm as returned by DescribeAlarms.
+type cloudwatchMetricAlarm struct {
+	AlarmArn           string  `xml:"AlarmArn"`
+	AlarmName          string  `xml:"AlarmName"`
+	ComparisonOperator string  `xml:"ComparisonOperator"`
+	EvaluationPeriods  int     `xml:"EvaluationPeriods"`
+	MetricName         string  `xml:"MetricName"`
+	Namespace          string  `xml:"Namespace"`
+	Period             int     `xml:"Period"`
+	Statistic          string  `xml:"Statistic"`
+	Threshold          float64 `xml:"Threshold"`
+	Dimensions         []struct {
+		Name  string `xml:"Name"`
+		Value string `xml:"Value"`
+	} `xml:"Dimensions>member"`
+	AlarmActions []string `xml:"AlarmActions>member"`
+}
+
+// PutMetricAlarm creates the alarm, or replaces all the settings of an
+// existing one, so it serves as both create and update.
+func resourceAwsCloudwatchMetricAlarmPut(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).cloudwatchconn
+
+	params := map[string]string{
+		"AlarmName":          d.Get("alarm_name").(string),
+		"ComparisonOperator": d.Get("comparison_operator").(string),
+		"EvaluationPeriods":  strconv.Itoa(d.Get("evaluation_periods").(int)),
+		"MetricName":         d.Get("metric_name").(string),
+		"Namespace":          d.Get("namespace").(string),
+		"Period":             strconv.Itoa(d.Get("period").(int)),
+		"Statistic":          d.Get("statistic").(string),
+		"Threshold":          strconv.FormatFloat(d.Get("threshold").(float64), 'f', -1, 64),
+	}
+	expandCloudwatchDimensions(params, d.Get("dimensions").(map[string]interface{}))
+	if v, ok := d.GetOk("alarm_actions"); ok {
+		for i, action := range expandStringList(v.(*schema.Set).List()) {
+			params[fmt.Sprintf("AlarmActions.member.%d", i+1)] = action
+		}
+	}
+
+	log.Printf("[DEBUG] CloudWatch put metric alarm: %#v", params)
+	if err := conn.Request("PutMetricAlarm", params, nil); err != nil {
+		return fmt.Errorf("Error putting metric alarm: %s", err)
+	}
+
+	d.SetId(d.Get("alarm_name").(string))
+	log.Printf("[INFO] CloudWatch metric alarm ID: %s", d.Id())
+
+	return resourceAwsCloudwatchMetricAlarmRead(d, meta)
+}
+
+func resourceAwsCloudwatchMetricAlarmRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).cloudwatchconn
+
+	alarm, err := describeCloudwatchMetricAlarm(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if alarm == nil {
+		log.Printf("[WARN] CloudWatch metric alarm %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	dimensions := make(map[string]string, len(alarm.Dimensions))
+	for _, dim := range alarm.Dimensions {
+		dimensions[dim.Name] = dim.Value
+	}
+
+	d.Set("comparison_operator", alarm.ComparisonOperator)
+	d.Set("evaluation_periods", alarm.EvaluationPeriods)
+	d.Set("metric_name", alarm.MetricName)
+	d.Set("namespace", alarm.Namespace)
+	d.Set("period", alarm.Period)
+	d.Set("statistic", alarm.Statistic)
+	d.Set("threshold", alarm.Threshold)
+	d.Set("dimensions", dimensions)
+	d.Set("alarm_actions", flattenStringSet(alarm.AlarmActions))
+	d.Set("arn", alarm.AlarmArn)
+
+	return nil
+}
+
+func resourceAwsCloudwatchMetricAlarmDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).cloudwatchconn
+
+	params := map[string]string{
+		"AlarmNames.member.1": d.Id(),
+	}
+
+	log.Printf("[DEBUG] CloudWatch delete metric alarm: %s", d.Id())
+	if err := conn.Request("DeleteAlarms", params, nil); err != nil {
+		qerr, ok := err.(*queryError)
+		if ok && qerr.Code == "ResourceNotFound" {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting metric alarm: %s", err)
+	}
+
+	return nil
+}
+
+// describeCloudwatchMetricAlarm returns the named alarm, or nil if it
+// doesn't exist.
+func describeCloudwatchMetricAlarm(conn *queryConn, name string) (*cloudwatchMetricAlarm, error) {
+	params := map[string]string{
+		"AlarmNames.member.1": name,
+	}
+
+	var resp struct {
+		MetricAlarms []cloudwatchMetricAlarm `xml:"DescribeAlarmsResult>MetricAlarms>member"`
+	}
+
+	log.Printf("[DEBUG] CloudWatch describe alarms: %#v", params)
+	if err := conn.Request("DescribeAlarms", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error retrieving metric alarm: %s", err)
+	}
+
+	for _, a := range resp.MetricAlarms {
+		if a.AlarmName == name {
+			return &a, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// expandCloudwatchDimensions adds the dimensions to the parameters of a
+// PutMetricAlarm request. They are sorted by name so that the same
+// dimensions always make the same request.
+func expandCloudwatchDimensions(params map[string]string, dimensions map[string]interface{}) {
+	names := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		params[fmt.Sprintf("Dimensions.member.%d.Name", i+1)] = name
+		params[fmt.Sprintf("Dimensions.member.%d.Value", i+1)] = dimensions[name].(string)
+	}
+}
+
+func validateCloudwatchComparisonOperator(v interface{}, k string) (ws []string, es []error) {
+	return validateCloudwatchEnum(v, k, cloudwatchComparisonOperators)
+}
+
+func validateCloudwatchStatistic(v interface{}, k string) (ws []string, es []error) {
+	return validateCloudwatchEnum(v, k, cloudwatchStatistics)
+}
+
+func validateCloudwatchEnum(v interface{}, k string, valid []string) (ws []string, es []error) {
+	for _, s := range valid {
+		if v.(string) == s {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of %s, got %q", k, strings.Join(valid, ", "), v))
+	return
+}
@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSCloudWatchMetricAlarm_basic(t *testing.T) {
+	var alarm cloudwatchMetricAlarm
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSCloudWatchMetricAlarmDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSCloudWatchMetricAlarmConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSCloudWatchMetricAlarmExists("aws_cloudwatch_metric_alarm.foobar", &alarm),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "metric_name", "CPUUtilization"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "statistic", "Average"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "threshold", "80"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "dimensions.InstanceId", "i-abc123"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSCloudWatchMetricAlarmConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSCloudWatchMetricAlarmExists("aws_cloudwatch_metric_alarm.foobar", &alarm),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "threshold", "90.5"),
+					resource.TestCheckResourceAttr(
+						"aws_cloudwatch_metric_alarm.foobar", "evaluation_periods", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandCloudwatchDimensions(t *testing.T) {
+	params := make(map[string]string)
+	expandCloudwatchDimensions(params, map[string]interface{}{
+		"InstanceId":           "i-abc123",
+		"AutoScalingGroupName": "foo",
+	})
+
+	expected := map[string]string{
+		"Dimensions.member.1.Name":  "AutoScalingGroupName",
+		"Dimensions.member.1.Value": "foo",
+		"Dimensions.member.2.Name":  "InstanceId",
+		"Dimensions.member.2.Value": "i-abc123",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("bad: %#v", params)
+	}
+}
+
+func TestValidateCloudwatchStatistic(t *testing.T) {
+	if _, es := validateCloudwatchStatistic("Average", "statistic"); len(es) > 0 {
+		t.Fatalf("unexpected errors: %#v", es)
+	}
+	if _, es := validateCloudwatchStatistic("average", "statistic"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+
+	if _, es := validateCloudwatchComparisonOperator("GreaterThanThreshold", "comparison_operator"); len(es) > 0 {
+		t.Fatalf("unexpected errors: %#v", es)
+	}
+	if _, es := validateCloudwatchComparisonOperator(">", "comparison_operator"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+}
+
+func testAccCheckAWSCloudWatchMetricAlarmDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).cloudwatchconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_cloudwatch_metric_alarm" {
+			continue
+		}
+
+		alarm, err := describeCloudwatchMetricAlarm(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if alarm != nil {
+			return fmt.Errorf("Metric alarm still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSCloudWatchMetricAlarmExists(n string, alarm *cloudwatchMetricAlarm) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No metric alarm ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).cloudwatchconn
+		a, err := describeCloudwatchMetricAlarm(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return fmt.Errorf("Metric alarm not found")
+		}
+
+		*alarm = *a
+		return nil
+	}
+}
+
+const testAccAWSCloudWatchMetricAlarmConfig = `
+resource "aws_cloudwatch_metric_alarm" "foobar" {
+  alarm_name = "terraform-test-foobar"
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  evaluation_periods = 2
+  metric_name = "CPUUtilization"
+  namespace = "AWS/EC2"
+  period = 120
+  statistic = "Average"
+  threshold = 80
+
+  dimensions {
+    InstanceId = "i-abc123"
+  }
+}
+`
+
+const testAccAWSCloudWatchMetricAlarmConfigUpdate = `
+resource "aws_cloudwatch_metric_alarm" "foobar" {
+  alarm_name = "terraform-test-foobar"
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  evaluation_periods = 3
+  metric_name = "CPUUtilization"
+  namespace = "AWS/EC2"
+  period = 120
+  statistic = "Average"
+  threshold = 90.5
+
+  dimensions {
+    InstanceId = "i-abc123"
+  }
+}
+`
@@ -106,6 +106,7 @@ func resourceAwsDbInstance() *schema.Resource {
 			"port": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 
@@ -176,7 +177,7 @@ func resourceAwsDbInstance() *schema.Resource {
 }
 
 func resourceAwsDbInstanceCreate(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).rdsconn
+	conn := meta.(*AWSClient).rdsconn()
 	opts := rds.CreateDBInstance{
 		AllocatedStorage:     d.Get("allocated_storage").(int),
 		SetAllocatedStorage:  true,
@@ -338,7 +339,7 @@ func resourceAwsDbInstanceRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsDbInstanceDelete(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).rdsconn
+	conn := meta.(*AWSClient).rdsconn()
 
 	log.Printf("[DEBUG] DB Instance destroy: %v", d.Id())
 
@@ -348,6 +349,11 @@ func resourceAwsDbInstanceDelete(d *schema.ResourceData, meta interface{}) error
 		opts.SkipFinalSnapshot = true
 	} else {
 		opts.FinalDBSnapshotIdentifier = d.Get("final_snapshot_identifier").(string)
+		if opts.FinalDBSnapshotIdentifier == "" {
+			return fmt.Errorf(
+				"DB Instance %s needs a final_snapshot_identifier to be "+
+					"destroyed, or skip_final_snapshot set to true", d.Id())
+		}
 	}
 
 	log.Printf("[DEBUG] DB Instance destroy configuration: %v", opts)
@@ -375,7 +381,7 @@ func resourceAwsDbInstanceDelete(d *schema.ResourceData, meta interface{}) error
 
 func resourceAwsBbInstanceRetrieve(
 	d *schema.ResourceData, meta interface{}) (*rds.DBInstance, error) {
-	conn := meta.(*AWSClient).rdsconn
+	conn := meta.(*AWSClient).rdsconn()
 
 	opts := rds.DescribeDBInstances{
 		DBInstanceIdentifier: d.Id(),
@@ -394,9 +400,7 @@ func resourceAwsBbInstanceRetrieve(
 
 	if len(resp.DBInstances) != 1 ||
 		resp.DBInstances[0].DBInstanceIdentifier != d.Id() {
-		if err != nil {
-			return nil, nil
-		}
+		return nil, nil
 	}
 
 	v := resp.DBInstances[0]
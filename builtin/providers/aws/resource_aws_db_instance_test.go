@@ -45,6 +45,9 @@ func TestAccAWSDBInstance(t *testing.T) {
 						"aws_db_instance.bar", "security_group_names.3322503515", "secfoobarbaz-test-terraform"),
 					resource.TestCheckResourceAttr(
 						"aws_db_instance.bar", "parameter_group_name", "default.mysql5.6"),
+					resource.TestCheckResourceAttr(
+						"aws_db_instance.bar", "port", "3306"),
+					testAccCheckAWSDBInstanceEndpoint("aws_db_instance.bar", &v),
 				),
 			},
 		},
@@ -52,7 +55,7 @@ func TestAccAWSDBInstance(t *testing.T) {
 }
 
 func testAccCheckAWSDBInstanceDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).rdsconn
+	conn := testAccProvider.Meta().(*AWSClient).rdsconn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_db_instance" {
@@ -115,7 +118,7 @@ func testAccCheckAWSDBInstanceExists(n string, v *rds.DBInstance) resource.TestC
 			return fmt.Errorf("No DB Instance ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).rdsconn
+		conn := testAccProvider.Meta().(*AWSClient).rdsconn()
 
 		opts := rds.DescribeDBInstances{
 			DBInstanceIdentifier: rs.Primary.ID,
@@ -138,6 +141,26 @@ func testAccCheckAWSDBInstanceExists(n string, v *rds.DBInstance) resource.TestC
 	}
 }
 
+func testAccCheckAWSDBInstanceEndpoint(n string, v *rds.DBInstance) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.Attributes["address"] != v.Address {
+			return fmt.Errorf("bad address: %s", rs.Primary.Attributes["address"])
+		}
+
+		expected := fmt.Sprintf("%s:%d", v.Address, v.Port)
+		if rs.Primary.Attributes["endpoint"] != expected {
+			return fmt.Errorf("bad endpoint: %s", rs.Primary.Attributes["endpoint"])
+		}
+
+		return nil
+	}
+}
+
 const testAccAWSDBInstanceConfig = `
 resource "aws_db_security_group" "bar" {
 	name = "secfoobarbaz-test-terraform"
@@ -15,6 +15,7 @@ func resourceAwsDbSubnetGroup() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAwsDbSubnetGroupCreate,
 		Read:   resourceAwsDbSubnetGroupRead,
+		Update: resourceAwsDbSubnetGroupUpdate,
 		Delete: resourceAwsDbSubnetGroupDelete,
 
 		Schema: map[string]*schema.Schema{
@@ -33,7 +34,6 @@ func resourceAwsDbSubnetGroup() *schema.Resource {
 			"subnet_ids": &schema.Schema{
 				Type:     schema.TypeSet,
 				Required: true,
-				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set: func(v interface{}) int {
 					return hashcode.String(v.(string))
@@ -44,7 +44,7 @@ func resourceAwsDbSubnetGroup() *schema.Resource {
 }
 
 func resourceAwsDbSubnetGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	rdsconn := meta.(*AWSClient).rdsconn
+	rdsconn := meta.(*AWSClient).rdsconn()
 
 	subnetIdsSet := d.Get("subnet_ids").(*schema.Set)
 	subnetIds := make([]string, subnetIdsSet.Len())
@@ -70,7 +70,7 @@ func resourceAwsDbSubnetGroupCreate(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceAwsDbSubnetGroupRead(d *schema.ResourceData, meta interface{}) error {
-	rdsconn := meta.(*AWSClient).rdsconn
+	rdsconn := meta.(*AWSClient).rdsconn()
 
 	describeOpts := rds.DescribeDBSubnetGroups{
 		DBSubnetGroupName: d.Id(),
@@ -78,11 +78,18 @@ func resourceAwsDbSubnetGroupRead(d *schema.ResourceData, meta interface{}) erro
 
 	describeResp, err := rdsconn.DescribeDBSubnetGroups(&describeOpts)
 	if err != nil {
+		if rdserr, ok := err.(*rds.Error); ok && rdserr.Code == "DBSubnetGroupNotFoundFault" {
+			d.SetId("")
+			return nil
+		}
+
 		return err
 	}
 
 	if len(describeResp.DBSubnetGroups) != 1 ||
 		describeResp.DBSubnetGroups[0].Name != d.Id() {
+		d.SetId("")
+		return nil
 	}
 
 	d.Set("name", describeResp.DBSubnetGroups[0].Name)
@@ -92,6 +99,36 @@ func resourceAwsDbSubnetGroupRead(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+func resourceAwsDbSubnetGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("subnet_ids") {
+		subnetIds := expandStringList(d.Get("subnet_ids").(*schema.Set).List())
+		err := modifyDbSubnetGroupSubnets(meta.(*AWSClient).rdsquery, d.Id(), subnetIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceAwsDbSubnetGroupRead(d, meta)
+}
+
+// modifyDbSubnetGroupSubnets replaces the subnets of the group, which goamz
+// can't do. RDS works out which subnets were added and removed itself.
+func modifyDbSubnetGroupSubnets(conn *queryConn, name string, subnetIds []string) error {
+	params := map[string]string{
+		"DBSubnetGroupName": name,
+	}
+	for i, id := range subnetIds {
+		params[fmt.Sprintf("SubnetIds.member.%d", i+1)] = id
+	}
+
+	log.Printf("[DEBUG] Modify DB Subnet Group: %#v", params)
+	if err := conn.Request("ModifyDBSubnetGroup", params, nil); err != nil {
+		return fmt.Errorf("Error modifying DB Subnet Group %s: %s", name, err)
+	}
+
+	return nil
+}
+
 func resourceAwsDbSubnetGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"pending"},
@@ -107,7 +144,7 @@ func resourceAwsDbSubnetGroupDelete(d *schema.ResourceData, meta interface{}) er
 func resourceAwsDbSubnetGroupDeleteRefreshFunc(
 	d *schema.ResourceData,
 	meta interface{}) resource.StateRefreshFunc {
-	rdsconn := meta.(*AWSClient).rdsconn
+	rdsconn := meta.(*AWSClient).rdsconn()
 
 	return func() (interface{}, string, error) {
 
@@ -2,18 +2,26 @@ package aws
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
 	"github.com/mitchellh/goamz/rds"
 )
 
 func TestAccAWSDBSubnetGroup(t *testing.T) {
 	var v rds.DBSubnetGroup
 
-	testCheck := func(*terraform.State) error {
-		return nil
+	testCheckSubnets := func(count int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if len(v.SubnetIds) != count {
+				return fmt.Errorf("bad subnets: %#v", v.SubnetIds)
+			}
+			return nil
+		}
 	}
 
 	resource.Test(t, resource.TestCase{
@@ -26,15 +34,51 @@ func TestAccAWSDBSubnetGroup(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckDBSubnetGroupExists(
 						"aws_db_subnet_group.foo", &v),
-					testCheck,
+					testCheckSubnets(2),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccDBSubnetGroupConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDBSubnetGroupExists(
+						"aws_db_subnet_group.foo", &v),
+					testCheckSubnets(3),
 				),
 			},
 		},
 	})
 }
 
+func TestModifyDbSubnetGroupSubnets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "ModifyDBSubnetGroup" || q.Get("DBSubnetGroupName") != "foo" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+		if q.Get("SubnetIds.member.1") != "subnet-1234" || q.Get("SubnetIds.member.2") != "subnet-5678" {
+			t.Errorf("bad subnets: %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `<ModifyDBSubnetGroupResponse></ModifyDBSubnetGroupResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2013-09-09",
+		httpClient: http.DefaultClient,
+	}
+
+	err := modifyDbSubnetGroupSubnets(conn, "foo", []string{"subnet-1234", "subnet-5678"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func testAccCheckDBSubnetGroupDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).rdsconn
+	conn := testAccProvider.Meta().(*AWSClient).rdsconn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_db_subnet_group" {
@@ -75,7 +119,7 @@ func testAccCheckDBSubnetGroupExists(n string, v *rds.DBSubnetGroup) resource.Te
 			return fmt.Errorf("No ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).rdsconn
+		conn := testAccProvider.Meta().(*AWSClient).rdsconn()
 		resp, err := conn.DescribeDBSubnetGroups(&rds.DescribeDBSubnetGroups{rs.Primary.ID})
 		if err != nil {
 			return err
@@ -113,3 +157,33 @@ resource "aws_db_subnet_group" "foo" {
 	subnet_ids = ["${aws_subnet.foo.id}", "${aws_subnet.bar.id}"]
 }
 `
+
+const testAccDBSubnetGroupConfigUpdate = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_subnet" "foo" {
+	cidr_block = "10.1.1.0/24"
+	availability_zone = "us-west-2a"
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_subnet" "bar" {
+	cidr_block = "10.1.2.0/24"
+	availability_zone = "us-west-2b"
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_subnet" "baz" {
+	cidr_block = "10.1.3.0/24"
+	availability_zone = "us-west-2c"
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_db_subnet_group" "foo" {
+	name = "foo"
+	description = "foo description"
+	subnet_ids = ["${aws_subnet.foo.id}", "${aws_subnet.bar.id}", "${aws_subnet.baz.id}"]
+}
+`
@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func resourceAwsEbsSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsEbsSnapshotCreate,
+		Read:   resourceAwsEbsSnapshotRead,
+		Update: resourceAwsEbsSnapshotUpdate,
+		Delete: resourceAwsEbsSnapshotDelete,
+
+		Schema: map[string]*schema.Schema{
+			"volume_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"owner_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"volume_size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"encrypted": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+
+			"timeouts": timeoutsSchema("create"),
+		},
+	}
+}
+
+// ec2Snapshot is a snapshot as returned by DescribeSnapshots. goamz's
+// doesn't have whether it's encrypted.
+type ec2Snapshot struct {
+	SnapshotId  string    `xml:"snapshotId"`
+	VolumeId    string    `xml:"volumeId"`
+	Status      string    `xml:"status"`
+	OwnerId     string    `xml:"ownerId"`
+	VolumeSize  int       `xml:"volumeSize"`
+	Encrypted   bool      `xml:"encrypted"`
+	Description string    `xml:"description"`
+	Tags        []ec2.Tag `xml:"tagSet>item"`
+}
+
+func resourceAwsEbsSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	timeout, err := resourceTimeout(d, "create", 20*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{
+		"VolumeId": d.Get("volume_id").(string),
+	}
+	if v, ok := d.GetOk("description"); ok {
+		params["Description"] = v.(string)
+	}
+
+	var resp struct {
+		SnapshotId string `xml:"snapshotId"`
+	}
+
+	log.Printf("[DEBUG] EBS snapshot create: %#v", params)
+	if err := conn.Request("CreateSnapshot", params, &resp); err != nil {
+		return fmt.Errorf("Error creating EBS snapshot: %s", err)
+	}
+
+	d.SetId(resp.SnapshotId)
+	log.Printf("[INFO] EBS snapshot ID: %s", d.Id())
+
+	// Tag the snapshot straight away, so that one that never completes
+	// can still be told apart
+	if err := setTags(meta.(*AWSClient).ec2conn(), d); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Waiting for EBS snapshot (%s) to complete", d.Id())
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending"},
+		Target:     "completed",
+		Refresh:    EbsSnapshotStateRefreshFunc(conn, d.Id()),
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for EBS snapshot (%s) to complete: %s",
+			d.Id(), err)
+	}
+
+	return resourceAwsEbsSnapshotRead(d, meta)
+}
+
+func resourceAwsEbsSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	snapRaw, _, err := EbsSnapshotStateRefreshFunc(conn, d.Id())()
+	if err != nil {
+		return err
+	}
+	if snapRaw == nil {
+		log.Printf("[WARN] EBS snapshot %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	snap := snapRaw.(*ec2Snapshot)
+	d.Set("volume_id", snap.VolumeId)
+	d.Set("description", snap.Description)
+	d.Set("owner_id", snap.OwnerId)
+	d.Set("volume_size", snap.VolumeSize)
+	d.Set("encrypted", snap.Encrypted)
+	d.Set("tags", tagsToMap(snap.Tags))
+
+	return nil
+}
+
+func resourceAwsEbsSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	if err := setTags(ec2conn, d); err != nil {
+		return err
+	}
+
+	return resourceAwsEbsSnapshotRead(d, meta)
+}
+
+func resourceAwsEbsSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{
+		"SnapshotId": d.Id(),
+	}
+
+	log.Printf("[INFO] Deleting EBS snapshot: %s", d.Id())
+	if err := conn.Request("DeleteSnapshot", params, nil); err != nil {
+		if isAWSErr(err, "InvalidSnapshot.NotFound") {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting EBS snapshot %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// EbsSnapshotStateRefreshFunc returns a resource.StateRefreshFunc that is
+// used to watch an EBS snapshot.
+func EbsSnapshotStateRefreshFunc(conn *queryConn, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		params := map[string]string{
+			"SnapshotId.1": id,
+		}
+
+		var resp struct {
+			Snapshots []ec2Snapshot `xml:"snapshotSet>item"`
+		}
+
+		err := conn.Request("DescribeSnapshots", params, &resp)
+		if err != nil {
+			if isAWSErr(err, "InvalidSnapshot.NotFound") {
+				return nil, "", nil
+			}
+
+			log.Printf("[ERROR] Error on EbsSnapshotStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		if len(resp.Snapshots) == 0 {
+			return nil, "", nil
+		}
+
+		snap := &resp.Snapshots[0]
+		return snap, snap.Status, nil
+	}
+}
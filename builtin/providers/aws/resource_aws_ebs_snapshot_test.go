@@ -0,0 +1,158 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSEBSSnapshot_basic(t *testing.T) {
+	var v ec2Snapshot
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEbsSnapshotDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAwsEbsSnapshotConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEbsSnapshotExists("aws_ebs_snapshot.test", &v),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_snapshot.test", "volume_size", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_snapshot.test", "encrypted", "false"),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_snapshot.test", "tags.Name", "tf-acc-test-ebs-snapshot"),
+				),
+			},
+		},
+	})
+}
+
+func TestEbsSnapshotStateRefreshFunc(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "DescribeSnapshots" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		switch q.Get("SnapshotId.1") {
+		case "snap-1234":
+			fmt.Fprint(w, `<DescribeSnapshotsResponse>
+  <snapshotSet>
+    <item>
+      <snapshotId>snap-1234</snapshotId>
+      <volumeId>vol-1234</volumeId>
+      <status>pending</status>
+      <ownerId>123456789012</ownerId>
+      <volumeSize>8</volumeSize>
+      <encrypted>true</encrypted>
+      <tagSet>
+        <item><key>Name</key><value>foo</value></item>
+      </tagSet>
+    </item>
+  </snapshotSet>
+</DescribeSnapshotsResponse>`)
+		default:
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidSnapshot.NotFound</Code>`+
+				`<Message>not found</Message></Error></Errors></Response>`)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2014-10-01",
+		httpClient: http.DefaultClient,
+	}
+
+	raw, state, err := EbsSnapshotStateRefreshFunc(conn, "snap-1234")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	snap := raw.(*ec2Snapshot)
+	if state != "pending" || snap.VolumeSize != 8 || !snap.Encrypted ||
+		snap.OwnerId != "123456789012" || tagsToMap(snap.Tags)["Name"] != "foo" {
+		t.Fatalf("bad: %s, %#v", state, snap)
+	}
+
+	// A snapshot that's gone is nothing rather than an error
+	raw, _, err = EbsSnapshotStateRefreshFunc(conn, "snap-5678")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if raw != nil {
+		t.Fatalf("bad: %#v", raw)
+	}
+}
+
+func testAccCheckEbsSnapshotDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ec2query
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_ebs_snapshot" {
+			continue
+		}
+
+		snap, _, err := EbsSnapshotStateRefreshFunc(conn, rs.Primary.ID)()
+		if err != nil {
+			return err
+		}
+		if snap != nil {
+			return fmt.Errorf("EBS snapshot still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckEbsSnapshotExists(n string, v *ec2Snapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ec2query
+		snap, state, err := EbsSnapshotStateRefreshFunc(conn, rs.Primary.ID)()
+		if err != nil {
+			return err
+		}
+		if snap == nil {
+			return fmt.Errorf("EBS snapshot not found")
+		}
+		if state != "completed" {
+			return fmt.Errorf("EBS snapshot is %s, not completed", state)
+		}
+
+		*v = *snap.(*ec2Snapshot)
+		return nil
+	}
+}
+
+const testAccAwsEbsSnapshotConfig = `
+resource "aws_ebs_volume" "test" {
+	availability_zone = "us-west-2a"
+	size = 1
+}
+
+resource "aws_ebs_snapshot" "test" {
+	volume_id = "${aws_ebs_volume.test.id}"
+	description = "tf-acc-test"
+	tags {
+		Name = "tf-acc-test-ebs-snapshot"
+	}
+}
+`
@@ -0,0 +1,209 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func resourceAwsEbsVolume() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsEbsVolumeCreate,
+		Read:   resourceAwsEbsVolumeRead,
+		Update: resourceAwsEbsVolumeUpdate,
+		Delete: resourceAwsEbsVolumeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"availability_zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"iops": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"encrypted": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"snapshot_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func resourceAwsEbsVolumeCreate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	createOpts := &ec2.CreateVolume{
+		AvailZone:  d.Get("availability_zone").(string),
+		Size:       int64(d.Get("size").(int)),
+		SnapshotId: d.Get("snapshot_id").(string),
+		VolumeType: d.Get("type").(string),
+		IOPS:       int64(d.Get("iops").(int)),
+		Encrypted:  d.Get("encrypted").(bool),
+	}
+
+	log.Printf("[DEBUG] EBS volume create configuration: %#v", createOpts)
+	resp, err := ec2conn.CreateVolume(createOpts)
+	if err != nil {
+		return fmt.Errorf("Error creating EBS volume: %s", err)
+	}
+
+	d.SetId(resp.VolumeId)
+	log.Printf("[INFO] EBS volume ID: %s", d.Id())
+
+	log.Printf("[DEBUG] Waiting for EBS volume (%s) to become available", d.Id())
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"creating"},
+		Target:     "available",
+		Refresh:    VolumeStateRefreshFunc(ec2conn, d.Id()),
+		Timeout:    5 * time.Minute,
+		Delay:      10 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for EBS volume (%s) to become available: %s",
+			d.Id(), err)
+	}
+
+	return resourceAwsEbsVolumeUpdate(d, meta)
+}
+
+func resourceAwsEbsVolumeRead(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	volRaw, _, err := VolumeStateRefreshFunc(ec2conn, d.Id())()
+	if err != nil {
+		return err
+	}
+	if volRaw == nil {
+		// The volume is gone
+		d.SetId("")
+		return nil
+	}
+
+	vol := volRaw.(*ec2.Volume)
+
+	size, err := strconv.Atoi(vol.Size)
+	if err != nil {
+		return err
+	}
+
+	var iops int
+	if vol.IOPS != "" {
+		iops, err = strconv.Atoi(vol.IOPS)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.Set("availability_zone", vol.AvailZone)
+	d.Set("size", size)
+	d.Set("type", vol.VolumeType)
+	d.Set("iops", iops)
+	d.Set("encrypted", vol.Encrypted)
+	d.Set("snapshot_id", vol.SnapshotId)
+	d.Set("tags", tagsToMap(vol.Tags))
+
+	return nil
+}
+
+func resourceAwsEbsVolumeUpdate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	if err := setTags(ec2conn, d); err != nil {
+		return err
+	}
+
+	return resourceAwsEbsVolumeRead(d, meta)
+}
+
+func resourceAwsEbsVolumeDelete(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	log.Printf("[INFO] Deleting EBS volume: %s", d.Id())
+
+	// A volume that was just detached can report VolumeInUse for a little
+	// while, so retry that
+	return resource.Retry(5*time.Minute, func() error {
+		_, err := ec2conn.DeleteVolume(d.Id())
+		if err == nil {
+			return nil
+		}
+
+		ec2err, ok := err.(*ec2.Error)
+		if !ok {
+			return resource.RetryError{err}
+		}
+
+		switch ec2err.Code {
+		case "InvalidVolume.NotFound":
+			return nil
+		case "VolumeInUse":
+			return err // retry
+		}
+
+		return resource.RetryError{err}
+	})
+}
+
+// VolumeStateRefreshFunc returns a resource.StateRefreshFunc that is used to
+// watch an EBS volume.
+func VolumeStateRefreshFunc(conn *ec2.EC2, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := conn.Volumes([]string{id}, ec2.NewFilter())
+		if err != nil {
+			if ec2err, ok := err.(*ec2.Error); ok && ec2err.Code == "InvalidVolume.NotFound" {
+				// Set this to nil as if we didn't find anything.
+				resp = nil
+			} else {
+				log.Printf("[ERROR] Error on VolumeStateRefresh: %s", err)
+				return nil, "", err
+			}
+		}
+
+		if resp == nil || len(resp.Volumes) == 0 {
+			// Sometimes AWS just has consistency issues and doesn't see
+			// our volume yet. Return an empty state.
+			return nil, "", nil
+		}
+
+		vol := &resp.Volumes[0]
+		return vol, vol.Status, nil
+	}
+}
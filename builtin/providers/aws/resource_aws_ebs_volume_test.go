@@ -0,0 +1,101 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestAccAWSEBSVolume_basic(t *testing.T) {
+	var v ec2.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVolumeDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAwsEbsVolumeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVolumeExists("aws_ebs_volume.test", &v),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_volume.test", "size", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_volume.test", "type", "gp2"),
+					resource.TestCheckResourceAttr(
+						"aws_ebs_volume.test", "tags.Name", "tf-acc-test-ebs-volume"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckVolumeDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_ebs_volume" {
+			continue
+		}
+
+		resp, err := conn.Volumes([]string{rs.Primary.ID}, ec2.NewFilter())
+		if err == nil {
+			if len(resp.Volumes) > 0 && resp.Volumes[0].Status != "deleting" {
+				return fmt.Errorf("still exist.")
+			}
+
+			return nil
+		}
+
+		// Verify the error is what we want
+		ec2err, ok := err.(*ec2.Error)
+		if !ok {
+			return err
+		}
+		if ec2err.Code != "InvalidVolume.NotFound" {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckVolumeExists(n string, v *ec2.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
+		resp, err := conn.Volumes([]string{rs.Primary.ID}, ec2.NewFilter())
+		if err != nil {
+			return err
+		}
+		if len(resp.Volumes) == 0 {
+			return fmt.Errorf("Volume not found")
+		}
+
+		*v = resp.Volumes[0]
+
+		return nil
+	}
+}
+
+const testAccAwsEbsVolumeConfig = `
+resource "aws_ebs_volume" "test" {
+	availability_zone = "us-west-2a"
+	size = 1
+	type = "gp2"
+	tags {
+		Name = "tf-acc-test-ebs-volume"
+	}
+}
+`
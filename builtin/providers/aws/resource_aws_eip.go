@@ -59,7 +59,7 @@ func resourceAwsEip() *schema.Resource {
 }
 
 func resourceAwsEipCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// By default, we're not in a VPC
 	domainOpt := ""
@@ -96,7 +96,7 @@ func resourceAwsEipCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsEipRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	domain := resourceAwsEipDomain(d)
 	id := d.Id()
@@ -143,7 +143,7 @@ func resourceAwsEipRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsEipUpdate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	domain := resourceAwsEipDomain(d)
 
@@ -166,7 +166,10 @@ func resourceAwsEipUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 
 		log.Printf("[DEBUG] EIP associate configuration: %#v (domain: %v)", assocOpts, domain)
-		_, err := ec2conn.AssociateAddress(&assocOpts)
+		err := retryEipAssociate(func() error {
+			_, err := ec2conn.AssociateAddress(&assocOpts)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("Failure associating instances: %s", err)
 		}
@@ -176,7 +179,7 @@ func resourceAwsEipUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsEipDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	if err := resourceAwsEipRead(d, meta); err != nil {
 		return err
@@ -226,6 +229,14 @@ func resourceAwsEipDelete(d *schema.ResourceData, meta interface{}) error {
 	})
 }
 
+// retryEipAssociate retries f while EC2 doesn't know the allocation ID
+// yet. A freshly allocated VPC address can take a few seconds to become
+// visible to AssociateAddress.
+func retryEipAssociate(f resource.RetryFunc) error {
+	return resource.Retry(1*time.Minute,
+		retryOnAwsCodes([]string{"InvalidAllocationID.NotFound"}, f))
+}
+
 func resourceAwsEipDomain(d *schema.ResourceData) string {
 	if v, ok := d.GetOk("domain"); ok {
 		return v.(string)
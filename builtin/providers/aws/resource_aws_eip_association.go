@@ -0,0 +1,195 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceAwsEipAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsEipAssociationCreate,
+		Read:   resourceAwsEipAssociationRead,
+		Delete: resourceAwsEipAssociationDelete,
+
+		ValidateFunc: resourceAwsEipAssociationValidate,
+
+		Schema: map[string]*schema.Schema{
+			// A VPC address is given by its allocation ID, and an
+			// EC2-Classic one by its public IP
+			"allocation_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"public_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"instance_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"private_ip_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// ec2Address is an Elastic IP as returned by DescribeAddresses.
+type ec2Address struct {
+	PublicIp         string `xml:"publicIp"`
+	AllocationId     string `xml:"allocationId"`
+	Domain           string `xml:"domain"`
+	InstanceId       string `xml:"instanceId"`
+	AssociationId    string `xml:"associationId"`
+	PrivateIpAddress string `xml:"privateIpAddress"`
+}
+
+func resourceAwsEipAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{
+		"InstanceId": d.Get("instance_id").(string),
+	}
+	if v, ok := d.GetOk("allocation_id"); ok {
+		params["AllocationId"] = v.(string)
+	} else {
+		params["PublicIp"] = d.Get("public_ip").(string)
+	}
+	if v, ok := d.GetOk("private_ip_address"); ok {
+		params["PrivateIpAddress"] = v.(string)
+	}
+
+	var resp struct {
+		AssociationId string `xml:"associationId"`
+	}
+
+	log.Printf("[DEBUG] EIP association configuration: %#v", params)
+	err := retryEipAssociate(func() error {
+		return conn.Request("AssociateAddress", params, &resp)
+	})
+	if err != nil {
+		return fmt.Errorf("Error associating EIP: %s", err)
+	}
+
+	// Only VPC addresses have association IDs, an EC2-Classic address
+	// can only be associated once so its public IP does as well
+	if resp.AssociationId != "" {
+		d.SetId(resp.AssociationId)
+	} else {
+		d.SetId(params["PublicIp"])
+	}
+	log.Printf("[INFO] EIP association ID: %s", d.Id())
+
+	return resourceAwsEipAssociationRead(d, meta)
+}
+
+func resourceAwsEipAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{}
+	if v, ok := d.GetOk("allocation_id"); ok {
+		params["AllocationId.1"] = v.(string)
+	} else {
+		params["PublicIp.1"] = d.Get("public_ip").(string)
+	}
+
+	var resp struct {
+		Addresses []ec2Address `xml:"addressesSet>item"`
+	}
+
+	err := conn.Request("DescribeAddresses", params, &resp)
+	if err != nil {
+		if isAWSErr(err, "InvalidAllocationID.NotFound") ||
+			isAWSErr(err, "InvalidAddress.NotFound") {
+			log.Printf("[WARN] EIP for association %s not found, removing", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving EIP for association %s: %s", d.Id(), err)
+	}
+
+	if len(resp.Addresses) == 0 || !eipAssociated(&resp.Addresses[0], d.Id()) {
+		// The address was disassociated, or associated elsewhere,
+		// outside of Terraform
+		log.Printf("[WARN] EIP association %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	address := resp.Addresses[0]
+	d.Set("allocation_id", address.AllocationId)
+	d.Set("public_ip", address.PublicIp)
+	d.Set("instance_id", address.InstanceId)
+	d.Set("private_ip_address", address.PrivateIpAddress)
+
+	return nil
+}
+
+func resourceAwsEipAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{}
+	if d.Get("allocation_id").(string) != "" {
+		params["AssociationId"] = d.Id()
+	} else {
+		params["PublicIp"] = d.Id()
+	}
+
+	log.Printf("[DEBUG] EIP disassociate: %#v", params)
+	if err := conn.Request("DisassociateAddress", params, nil); err != nil {
+		if isAWSErr(err, "InvalidAssociationID.NotFound") ||
+			isAWSErr(err, "InvalidAddress.NotFound") {
+			// Already disassociated, or the address is gone
+			return nil
+		}
+
+		return fmt.Errorf("Error disassociating EIP %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsEipAssociationValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	allocation := c.IsSet("allocation_id")
+	publicIp := c.IsSet("public_ip")
+
+	switch {
+	case allocation && publicIp:
+		es = append(es, fmt.Errorf(
+			"Only one of allocation_id, for a VPC address, or public_ip, "+
+				"for an EC2-Classic address, can be set"))
+	case !allocation && !publicIp:
+		es = append(es, fmt.Errorf(
+			"One of allocation_id, for a VPC address, or public_ip, "+
+				"for an EC2-Classic address, must be set"))
+	}
+
+	return
+}
+
+// eipAssociated returns true if the address still has the association
+// with the ID id. An EC2-Classic association is identified by the
+// address's public IP, so there it only has to be associated at all.
+func eipAssociated(address *ec2Address, id string) bool {
+	if address.AllocationId != "" {
+		return address.AssociationId == id
+	}
+
+	return address.PublicIp == id && address.InstanceId != ""
+}
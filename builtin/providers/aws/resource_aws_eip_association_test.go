@@ -0,0 +1,221 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSEIPAssociation_basic(t *testing.T) {
+	var address ec2Address
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSEIPAssociationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSEIPAssociationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSEIPAssociationExists("aws_eip_association.foo", &address),
+					testAccCheckAWSEIPAssociationInstance("aws_instance.foo", &address),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAwsEipAssociationValidate(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			map[string]interface{}{
+				"allocation_id": "eipalloc-12345678",
+			},
+			false,
+		},
+		{
+			map[string]interface{}{
+				"public_ip": "203.0.113.10",
+			},
+			false,
+		},
+		{
+			map[string]interface{}{
+				"allocation_id": "eipalloc-12345678",
+				"public_ip":     "203.0.113.10",
+			},
+			true,
+		},
+		{
+			map[string]interface{}{},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		tc.Config["instance_id"] = "i-12345678"
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsEipAssociationValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestEipAssociated(t *testing.T) {
+	cases := []struct {
+		Address ec2Address
+		Id      string
+		Result  bool
+	}{
+		// VPC
+		{
+			ec2Address{AllocationId: "eipalloc-1", AssociationId: "eipassoc-1", InstanceId: "i-1"},
+			"eipassoc-1",
+			true,
+		},
+		{
+			ec2Address{AllocationId: "eipalloc-1", AssociationId: "eipassoc-2", InstanceId: "i-2"},
+			"eipassoc-1",
+			false,
+		},
+		{
+			ec2Address{AllocationId: "eipalloc-1"},
+			"eipassoc-1",
+			false,
+		},
+
+		// EC2-Classic
+		{
+			ec2Address{PublicIp: "203.0.113.10", InstanceId: "i-1"},
+			"203.0.113.10",
+			true,
+		},
+		{
+			ec2Address{PublicIp: "203.0.113.10"},
+			"203.0.113.10",
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		if r := eipAssociated(&tc.Address, tc.Id); r != tc.Result {
+			t.Fatalf("%d: bad: %t", i, r)
+		}
+	}
+}
+
+func testAccCheckAWSEIPAssociationDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ec2query
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_eip_association" {
+			continue
+		}
+
+		params := map[string]string{
+			"Filter.1.Name":    "association-id",
+			"Filter.1.Value.1": rs.Primary.ID,
+		}
+
+		var resp struct {
+			Addresses []ec2Address `xml:"addressesSet>item"`
+		}
+		if err := conn.Request("DescribeAddresses", params, &resp); err != nil {
+			return err
+		}
+		if len(resp.Addresses) > 0 {
+			return fmt.Errorf("EIP association still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSEIPAssociationExists(n string, res *ec2Address) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No EIP association ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ec2query
+		params := map[string]string{
+			"AllocationId.1": rs.Primary.Attributes["allocation_id"],
+		}
+
+		var resp struct {
+			Addresses []ec2Address `xml:"addressesSet>item"`
+		}
+		if err := conn.Request("DescribeAddresses", params, &resp); err != nil {
+			return err
+		}
+		if len(resp.Addresses) != 1 || resp.Addresses[0].AssociationId != rs.Primary.ID {
+			return fmt.Errorf("EIP association not found")
+		}
+
+		*res = resp.Addresses[0]
+		return nil
+	}
+}
+
+func testAccCheckAWSEIPAssociationInstance(n string, address *ec2Address) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if address.InstanceId != rs.Primary.ID {
+			return fmt.Errorf("bad instance: %s, expected %s", address.InstanceId, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSEIPAssociationConfig = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_internet_gateway" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_subnet" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+	cidr_block = "10.1.1.0/24"
+}
+
+resource "aws_instance" "foo" {
+	# us-west-2
+	ami = "ami-4fccb37f"
+	instance_type = "m1.small"
+	subnet_id = "${aws_subnet.foo.id}"
+}
+
+resource "aws_eip" "foo" {
+	vpc = true
+	depends_on = ["aws_internet_gateway.foo"]
+}
+
+resource "aws_eip_association" "foo" {
+	allocation_id = "${aws_eip.foo.allocation_id}"
+	instance_id = "${aws_instance.foo.id}"
+}
+`
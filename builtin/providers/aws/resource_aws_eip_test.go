@@ -56,8 +56,38 @@ func TestAccAWSEIP_instance(t *testing.T) {
 	})
 }
 
+func TestRetryEipAssociate(t *testing.T) {
+	calls := 0
+	err := retryEipAssociate(func() error {
+		calls++
+		if calls < 3 {
+			return &ec2.Error{Code: "InvalidAllocationID.NotFound"}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 3 {
+		t.Fatalf("bad calls: %d", calls)
+	}
+
+	calls = 0
+	err = retryEipAssociate(func() error {
+		calls++
+		return &ec2.Error{Code: "InvalidInstanceID.NotFound"}
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("bad calls: %d", calls)
+	}
+}
+
 func testAccCheckAWSEIPDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_eip" {
@@ -112,7 +142,7 @@ func testAccCheckAWSEIPExists(n string, res *ec2.Address) resource.TestCheckFunc
 			return fmt.Errorf("No EIP ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 		if strings.Contains(rs.Primary.ID, "eipalloc") {
 			describe, err := conn.Addresses([]string{}, []string{rs.Primary.ID}, nil)
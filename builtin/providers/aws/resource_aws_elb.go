@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/elb"
 )
 
@@ -17,6 +24,8 @@ func resourceAwsElb() *schema.Resource {
 		Update: resourceAwsElbUpdate,
 		Delete: resourceAwsElbDelete,
 
+		ValidateFunc: resourceAwsElbValidate,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -31,9 +40,63 @@ func resourceAwsElb() *schema.Resource {
 				Computed: true,
 			},
 
+			// AWS disables cross-zone load balancing on a new ELB, so
+			// the default matches it and leaving it out doesn't drift
 			"cross_zone_load_balancing": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  false,
+			},
+
+			"idle_timeout": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"connection_draining": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+
+			"connection_draining_timeout": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			// access_logs has at most one element. Logging is enabled
+			// when it's set and disabled when it's removed.
+			"access_logs": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"bucket_prefix": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"interval": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  60,
+						},
+					},
+				},
+			},
+
+			// additional_attributes sets ELB attributes that don't have
+			// fields of their own yet
+			"additional_attributes": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
 			},
 
 			"availability_zones": &schema.Schema{
@@ -47,6 +110,8 @@ func resourceAwsElb() *schema.Resource {
 				},
 			},
 
+			// instances is only managed once it's set, so that ELBs an
+			// autoscaling group registers instances with can leave it out
 			"instances": &schema.Schema{
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
@@ -57,12 +122,22 @@ func resourceAwsElb() *schema.Resource {
 				},
 			},
 
-			// TODO: could be not ForceNew
+			// registered_instances mirrors every instance registered with
+			// the ELB, including ones Terraform didn't add itself, such as
+			// those registered by an autoscaling group.
+			"registered_instances": &schema.Schema{
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+				Set: func(v interface{}) int {
+					return hashcode.String(v.(string))
+				},
+			},
+
 			"security_groups": &schema.Schema{
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 				Set: func(v interface{}) int {
 					return hashcode.String(v.(string))
@@ -79,21 +154,24 @@ func resourceAwsElb() *schema.Resource {
 				},
 			},
 
-			// TODO: could be not ForceNew
 			"listener": &schema.Schema{
 				Type:     schema.TypeSet,
 				Required: true,
-				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						// The instance port and protocol default to the
+						// lb_port and lb_protocol when they're expanded
 						"instance_port": &schema.Schema{
 							Type:     schema.TypeInt,
-							Required: true,
+							Optional: true,
+							Computed: true,
 						},
 
 						"instance_protocol": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Computed:  true,
+							StateFunc: upperCaseStateFunc,
 						},
 
 						"lb_port": &schema.Schema{
@@ -102,8 +180,9 @@ func resourceAwsElb() *schema.Resource {
 						},
 
 						"lb_protocol": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Required:  true,
+							StateFunc: upperCaseStateFunc,
 						},
 
 						"ssl_certificate_id": &schema.Schema{
@@ -115,9 +194,40 @@ func resourceAwsElb() *schema.Resource {
 				Set: resourceAwsElbListenerHash,
 			},
 
+			"ssl_policy": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"lb_port": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"reference_security_policy": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"attributes": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+					},
+				},
+				Set: resourceAwsElbSslPolicyHash,
+			},
+
 			// TODO: could be not ForceNew
+			// An ELB has a single health check, so only one block is
+			// accepted
 			"health_check": &schema.Schema{
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Optional: true,
 				ForceNew: true,
 				Computed: true,
@@ -149,19 +259,43 @@ func resourceAwsElb() *schema.Resource {
 						},
 					},
 				},
-				Set: resourceAwsElbHealthCheckHash,
 			},
 
 			"dns_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			// The hosted zone of dns_name, for Route 53 alias records
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			// wait_for_dns only affects creation: "populated" waits for
+			// the ELB to report its DNS name, "resolvable" also waits
+			// until that name resolves.
+			"wait_for_dns": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateElbWaitForDns,
+			},
+
+			// verify_attributes reads the attributes back after setting
+			// them, until they match
+			"verify_attributes": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"timeouts": timeoutsSchema("create", "update", "delete"),
 		},
 	}
 }
 
 func resourceAwsElbCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbconn
+	elbconn := meta.(*AWSClient).elbconn()
 
 	// Expand the "listener" set to goamz compat []elb.Listener
 	listeners, err := expandListeners(d.Get("listener").(*schema.Set).List())
@@ -178,6 +312,9 @@ func resourceAwsElbCreate(d *schema.ResourceData, meta interface{}) error {
 
 	if v, ok := d.GetOk("availability_zones"); ok {
 		elbOpts.AvailZone = expandStringList(v.(*schema.Set).List())
+		if err := validateAvailabilityZones(meta.(*AWSClient), elbOpts.AvailZone); err != nil {
+			return err
+		}
 	}
 
 	if v, ok := d.GetOk("security_groups"); ok {
@@ -207,7 +344,7 @@ func resourceAwsElbCreate(d *schema.ResourceData, meta interface{}) error {
 	d.SetPartial("subnets")
 
 	if d.HasChange("health_check") {
-		vs := d.Get("health_check").(*schema.Set).List()
+		vs := d.Get("health_check").([]interface{})
 		if len(vs) > 0 {
 			check := vs[0].(map[string]interface{})
 
@@ -228,12 +365,36 @@ func resourceAwsElbCreate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 	}
+	d.SetPartial("health_check")
+
+	if v := d.Get("wait_for_dns").(string); v != "" {
+		timeout, err := resourceTimeout(d, "create", 10*time.Minute)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Waiting for ELB (%s) DNS name to be %s", d.Id(), v)
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"pending"},
+			Target:     v,
+			Refresh:    ELBDNSStateRefreshFunc(elbconn, d.Id(), v == "resolvable"),
+			Timeout:    timeout,
+			MinTimeout: 5 * time.Second,
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			// The ELB itself exists and is saved in the state, so only
+			// the wait has to be retried
+			return fmt.Errorf(
+				"Error waiting for ELB (%s) DNS name to be %s: %s",
+				d.Id(), v, err)
+		}
+	}
 
-	return resourceAwsElbUpdate(d, meta)
+	return resourceAwsElbApply(d, meta, "create")
 }
 
 func resourceAwsElbRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbconn
+	elbconn := meta.(*AWSClient).elbconn()
 
 	// Retrieve the ELB properties for updating the state
 	describeElbOpts := &elb.DescribeLoadBalancer{
@@ -258,24 +419,89 @@ func resourceAwsElbRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.Set("name", lb.LoadBalancerName)
 	d.Set("dns_name", lb.DNSName)
-	d.Set("internal", lb.Scheme == "internal")
-	d.Set("availability_zones", lb.AvailabilityZones)
-	d.Set("instances", flattenInstances(lb.Instances))
-	d.Set("listener", flattenListeners(lb.Listeners))
-	d.Set("security_groups", lb.SecurityGroups)
+
+	zoneId, err := elbHostedZoneId(elbconn.Region.Name)
+	if err != nil {
+		return err
+	}
+	d.Set("zone_id", zoneId)
+
+	internal, ok := elbSchemeInternal(lb.Scheme)
+	if !ok {
+		log.Printf("[WARN] ELB %s has unknown scheme %q, leaving internal alone", d.Id(), lb.Scheme)
+	} else {
+		if v, set := d.GetOk("internal"); set && v.(bool) != internal {
+			// internal can't be changed in place, so this makes the next
+			// plan replace the ELB
+			log.Printf(
+				"[INFO] ELB %s has scheme %q, but internal was %t",
+				d.Id(), lb.Scheme, v.(bool))
+		}
+		d.Set("internal", internal)
+	}
+
+	// VPC ELBs report the zones of their subnets as well. Those follow
+	// from the subnets, but availability_zones is computed, so they're
+	// read without a diff and can be referred to like a classic ELB's.
 	d.Set("subnets", lb.Subnets)
+	d.Set("availability_zones", lb.AvailabilityZones)
+	if instances := elbManagedInstances(d.Get("instances").(*schema.Set), lb.Instances); instances != nil {
+		d.Set("instances", instances)
+	}
+	d.Set("registered_instances", flattenInstances(lb.Instances))
+
+	listeners := flattenListeners(lb.Listeners)
+	if v, ok := d.GetOk("listener"); ok {
+		preserveElbListenerCertificates(listeners, v.(*schema.Set).List())
+	}
+	d.Set("listener", listeners)
+
+	d.Set("security_groups", flattenStringSet(lb.SecurityGroups))
 
-	// There's only one health check, so save that to state as we
-	// currently can
+	// There's only one health check, which is written as the only block
 	if lb.HealthCheck.Target != "" {
 		d.Set("health_check", flattenHealthCheck(lb.HealthCheck))
 	}
 
+	sslPolicies, err := readElbSslPolicies(
+		meta.(*AWSClient).elbquery, d.Id(), d.Get("ssl_policy").(*schema.Set).List())
+	if err != nil {
+		return err
+	}
+	d.Set("ssl_policy", sslPolicies)
+
+	attrs, err := describeElbAttributes(meta.(*AWSClient).elbquery, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("cross_zone_load_balancing", attrs.CrossZoneLoadBalancing.Enabled)
+	d.Set("idle_timeout", attrs.ConnectionSettings.IdleTimeout)
+	d.Set("connection_draining", attrs.ConnectionDraining.Enabled)
+	d.Set("connection_draining_timeout", attrs.ConnectionDraining.Timeout)
+	d.Set("access_logs", flattenElbAccessLog(attrs.AccessLog))
+	d.Set("additional_attributes", flattenElbAdditionalAttributes(
+		attrs, d.Get("additional_attributes").(map[string]interface{})))
+
 	return nil
 }
 
 func resourceAwsElbUpdate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbconn
+	return resourceAwsElbApply(d, meta, "update")
+}
+
+// resourceAwsElbApply applies the changes that don't need a new ELB. It
+// finishes both create and update, and op names the operation whose
+// timeout bounds the waits.
+func resourceAwsElbApply(d *schema.ResourceData, meta interface{}, op string) error {
+	elbconn := meta.(*AWSClient).elbconn()
+
+	// Without a configured timeout we don't wait for instances to be
+	// registered, as before
+	timeout, err := resourceTimeout(d, op, 0)
+	if err != nil {
+		return err
+	}
 
 	d.Partial(true)
 
@@ -312,23 +538,177 @@ func resourceAwsElbUpdate(d *schema.ResourceData, meta interface{}) error {
 			}
 		}
 
+		if timeout > 0 {
+			err := resourceAwsElbWaitForInstances(elbconn, d.Id(), add, remove, timeout)
+			if err != nil {
+				return err
+			}
+		}
+
 		d.SetPartial("instances")
 	}
 
-	log.Println("[INFO] outside modify attributes")
-	if d.HasChange("cross_zone_load_balancing") {
-		log.Println("[INFO] inside modify attributes")
-		attrs := elb.ModifyLoadBalancerAttributes{
-			LoadBalancerName: d.Get("name").(string),
-			LoadBalancerAttributes: elb.LoadBalancerAttributes{
-				CrossZoneLoadBalancingEnabled: d.Get("cross_zone_load_balancing").(bool),
-			},
+	if d.HasChange("listener") {
+		o, n := d.GetChange("listener")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		oldListeners, err := expandListeners(os.Difference(ns).List())
+		if err != nil {
+			return err
 		}
-		_, err := elbconn.ModifyLoadBalancerAttributes(&attrs)
+		newListeners, err := expandListeners(ns.Difference(os).List())
 		if err != nil {
-			return fmt.Errorf("Failure configuring cross zone balancing: %s", err)
+			return err
+		}
+
+		rotate, replace, remove, add := diffElbListeners(oldListeners, newListeners)
+
+		// A listener whose certificate is all that changed keeps its
+		// port, so the certificate is swapped without touching it.
+		for _, l := range rotate {
+			certOpts := elb.SetLoadBalancerListenerSSLCertificate{
+				LoadBalancerName: d.Id(),
+				LoadBalancerPort: l.LoadBalancerPort,
+				SSLCertificateId: l.SSLCertificateId,
+			}
+
+			log.Printf("[DEBUG] ELB set listener certificate: %#v", certOpts)
+			_, err := elbconn.SetLoadBalancerListenerSSLCertificate(&certOpts)
+			if err != nil {
+				return fmt.Errorf(
+					"Failure setting certificate on listener %d: %s",
+					l.LoadBalancerPort, err)
+			}
+		}
+
+		// A listener on a port that's taken has to be removed before the
+		// one replacing it can be added. The rest are only removed once
+		// the new listeners are up, so the ELB keeps serving meanwhile.
+		if err := deleteElbListeners(elbconn, d.Id(), replace); err != nil {
+			return err
+		}
+		if len(add) > 0 {
+			createOpts := elb.CreateLoadBalancerListeners{
+				LoadBalancerName: d.Id(),
+				Listeners:        add,
+			}
+
+			log.Printf("[DEBUG] ELB create listeners: %#v", createOpts)
+			if _, err := elbconn.CreateLoadBalancerListeners(&createOpts); err != nil {
+				return fmt.Errorf("Failure adding listeners: %s", err)
+			}
 		}
+		if err := deleteElbListeners(elbconn, d.Id(), remove); err != nil {
+			return err
+		}
+
+		d.SetPartial("listener")
+	}
+
+	// The attributes that were set, for verify_attributes
+	var modifiedAttrs *elbAttributes
+	modifiedAdditional := make(map[string]string)
+
+	if d.HasChange("cross_zone_load_balancing") || d.HasChange("idle_timeout") ||
+		d.HasChange("connection_draining") ||
+		d.HasChange("connection_draining_timeout") || d.HasChange("access_logs") {
+		attrs := expandElbAttributes(d)
+		if err := modifyElbAttributes(meta.(*AWSClient).elbquery, d.Id(), attrs); err != nil {
+			return err
+		}
+		modifiedAttrs = attrs
+
 		d.SetPartial("cross_zone_load_balancing")
+		d.SetPartial("idle_timeout")
+		d.SetPartial("connection_draining")
+		d.SetPartial("connection_draining_timeout")
+		d.SetPartial("access_logs")
+	}
+
+	if d.HasChange("additional_attributes") {
+		o, n := d.GetChange("additional_attributes")
+		attrs := expandElbAdditionalAttributes(
+			o.(map[string]interface{}), n.(map[string]interface{}))
+		if len(attrs) > 0 {
+			err := modifyElbAdditionalAttributes(meta.(*AWSClient).elbquery, d.Id(), attrs)
+			if err != nil {
+				return err
+			}
+			modifiedAdditional = attrs
+		}
+
+		d.SetPartial("additional_attributes")
+	}
+
+	if d.Get("verify_attributes").(bool) && (modifiedAttrs != nil || len(modifiedAdditional) > 0) {
+		err := waitForElbAttributes(meta.(*AWSClient).elbquery, d.Id(),
+			modifiedAttrs, modifiedAdditional, elbAttributesVerifyTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("security_groups") {
+		// The groups given replace the ELB's groups entirely
+		applyOpts := elb.ApplySecurityGroupsToLoadBalancer{
+			LoadBalancerName: d.Id(),
+			SecurityGroups: expandStringList(
+				d.Get("security_groups").(*schema.Set).List()),
+		}
+
+		log.Printf("[DEBUG] ELB apply security groups: %#v", applyOpts)
+		_, err := elbconn.ApplySecurityGroupsToLoadBalancer(&applyOpts)
+		if err != nil {
+			return fmt.Errorf("Failure applying security groups: %s", err)
+		}
+
+		d.SetPartial("security_groups")
+	}
+
+	// The subnets were placed at creation, so only updates change them
+	if op == "update" && d.HasChange("subnets") {
+		o, n := d.GetChange("subnets")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		if len(ns.List()) == 0 {
+			return fmt.Errorf(
+				"An ELB in a VPC must have at least one subnet, " +
+					"subnets can't be emptied")
+		}
+
+		err := updateElbSubnets(
+			meta.(*AWSClient).elbquery, d.Id(),
+			expandStringList(ns.Difference(os).List()),
+			expandStringList(os.Difference(ns).List()))
+		if err != nil {
+			return err
+		}
+
+		d.SetPartial("subnets")
+	}
+
+	if d.HasChange("ssl_policy") {
+		o, n := d.GetChange("ssl_policy")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		// Detach and delete the policies that are gone first, so that a
+		// policy that changed but kept its name can be created again below.
+		for _, raw := range os.Difference(ns).List() {
+			if err := resourceAwsElbRemoveSslPolicy(elbconn, d.Id(), raw.(map[string]interface{})); err != nil {
+				return err
+			}
+		}
+
+		for _, raw := range ns.Difference(os).List() {
+			if err := resourceAwsElbAddSslPolicy(elbconn, d.Id(), raw.(map[string]interface{})); err != nil {
+				return err
+			}
+		}
+
+		d.SetPartial("ssl_policy")
 	}
 
 	d.Partial(false)
@@ -336,7 +716,7 @@ func resourceAwsElbUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsElbDelete(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbconn
+	elbconn := meta.(*AWSClient).elbconn()
 
 	log.Printf("[INFO] Deleting ELB: %s", d.Id())
 
@@ -348,17 +728,564 @@ func resourceAwsElbDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error deleting ELB: %s", err)
 	}
 
+	timeout, err := resourceTimeout(d, "delete", 0)
+	if err != nil {
+		return err
+	}
+	if timeout == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Waiting for ELB (%s) to be deleted", d.Id())
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"deleting"},
+		Target:  "",
+		Refresh: func() (interface{}, string, error) {
+			resp, err := elbconn.DescribeLoadBalancers(&elb.DescribeLoadBalancer{
+				Names: []string{d.Id()},
+			})
+			if err != nil {
+				if elberr, ok := err.(*elb.Error); ok && elberr.Code == "LoadBalancerNotFound" {
+					return nil, "", nil
+				}
+				return nil, "", err
+			}
+			if len(resp.LoadBalancers) == 0 {
+				return nil, "", nil
+			}
+
+			return resp, "deleting", nil
+		},
+		Timeout: timeout,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for ELB (%s) to be deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceAwsElbWaitForInstances waits until all of the added instances
+// are registered with the ELB and all of the removed ones are gone.
+func resourceAwsElbWaitForInstances(
+	conn *elb.ELB, name string, add, remove []string, timeout time.Duration) error {
+	var pending []string
+
+	log.Printf("[DEBUG] Waiting for ELB (%s) instances to register", name)
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  "registered",
+		Refresh: func() (interface{}, string, error) {
+			resp, err := conn.DescribeLoadBalancers(&elb.DescribeLoadBalancer{
+				Names: []string{name},
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			if len(resp.LoadBalancers) != 1 {
+				return nil, "", fmt.Errorf("Unable to find ELB: %#v", resp.LoadBalancers)
+			}
+
+			pending = pendingElbInstances(
+				flattenInstances(resp.LoadBalancers[0].Instances), add, remove)
+			if len(pending) > 0 {
+				return pending, "pending", nil
+			}
+
+			return pending, "registered", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for ELB (%s) instances %s to register or deregister: %s",
+			name, strings.Join(pending, ", "), err)
+	}
+
+	return nil
+}
+
+// pendingElbInstances returns the instances that are still to be
+// registered (those in add but not in registered) or deregistered (those
+// in remove that are still registered).
+func pendingElbInstances(registered, add, remove []string) []string {
+	current := make(map[string]struct{}, len(registered))
+	for _, id := range registered {
+		current[id] = struct{}{}
+	}
+
+	var pending []string
+	for _, id := range add {
+		if _, ok := current[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+	for _, id := range remove {
+		if _, ok := current[id]; ok {
+			pending = append(pending, id)
+		}
+	}
+
+	return pending
+}
+
+// ELBDNSStateRefreshFunc returns a resource.StateRefreshFunc that is used
+// to watch for an ELB's DNS name to be "populated", or, if resolve is
+// true, "resolvable".
+func ELBDNSStateRefreshFunc(conn *elb.ELB, name string, resolve bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := conn.DescribeLoadBalancers(&elb.DescribeLoadBalancer{
+			Names: []string{name},
+		})
+		if err != nil {
+			log.Printf("[ERROR] Error on ELBDNSStateRefresh: %s", err)
+			return nil, "", err
+		}
+		if len(resp.LoadBalancers) != 1 {
+			return nil, "", fmt.Errorf("Unable to find ELB: %#v", resp.LoadBalancers)
+		}
+
+		lb := &resp.LoadBalancers[0]
+		if lb.DNSName == "" {
+			return lb, "pending", nil
+		}
+		if !resolve {
+			return lb, "populated", nil
+		}
+
+		if _, err := net.LookupHost(lb.DNSName); err != nil {
+			log.Printf("[DEBUG] ELB DNS name %s not resolvable yet: %s", lb.DNSName, err)
+			return lb, "pending", nil
+		}
+
+		return lb, "resolvable", nil
+	}
+}
+
+func validateElbWaitForDns(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "", "populated", "resolvable":
+	default:
+		es = append(es, fmt.Errorf(
+			"%s must be \"populated\" or \"resolvable\", got %q", k, v))
+	}
+
+	return
+}
+
+// resourceAwsElbAddSslPolicy creates an SSL negotiation policy on the
+// load balancer and makes it the policy of the listener on its lb_port.
+func resourceAwsElbAddSslPolicy(conn *elb.ELB, lbName string, m map[string]interface{}) error {
+	policyName := m["name"].(string)
+	lbPort := int64(m["lb_port"].(int))
+
+	createOpts := elb.CreateLoadBalancerPolicy{
+		LoadBalancerName: lbName,
+		PolicyName:       policyName,
+		PolicyTypeName:   elbSslPolicyType,
+		PolicyAttributes: expandSslPolicyAttributes(m),
+	}
+
+	log.Printf("[DEBUG] ELB create SSL policy: %#v", createOpts)
+	_, err := conn.CreateLoadBalancerPolicy(&createOpts)
+	if isAWSErr(err, "DuplicatePolicyName") {
+		// The policy was detached from its listener outside of Terraform,
+		// so it's no longer in the state but still exists. Replace it, as
+		// its attributes may have changed too.
+		log.Printf("[DEBUG] ELB SSL policy %s exists, replacing it", policyName)
+		deleteOpts := elb.DeleteLoadBalancerPolicy{
+			LoadBalancerName: lbName,
+			PolicyName:       policyName,
+		}
+		if _, err := conn.DeleteLoadBalancerPolicy(&deleteOpts); err != nil {
+			return fmt.Errorf("Error deleting SSL policy %s: %s", policyName, err)
+		}
+
+		_, err = conn.CreateLoadBalancerPolicy(&createOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating SSL policy %s: %s", policyName, err)
+	}
+
+	setOpts := elb.SetLoadBalancerPoliciesOfListener{
+		LoadBalancerName: lbName,
+		LoadBalancerPort: lbPort,
+		PolicyNames:      []string{policyName},
+	}
+
+	log.Printf("[DEBUG] ELB set listener policies: %#v", setOpts)
+	if _, err := conn.SetLoadBalancerPoliciesOfListener(&setOpts); err != nil {
+		return fmt.Errorf(
+			"Error setting SSL policy %s on listener %d: %s",
+			policyName, lbPort, err)
+	}
+
 	return nil
 }
 
-func resourceAwsElbHealthCheckHash(v interface{}) int {
+// resourceAwsElbRemoveSslPolicy clears the policies of the listener on
+// the policy's lb_port and then deletes the policy itself.
+func resourceAwsElbRemoveSslPolicy(conn *elb.ELB, lbName string, m map[string]interface{}) error {
+	policyName := m["name"].(string)
+	lbPort := int64(m["lb_port"].(int))
+
+	setOpts := elb.SetLoadBalancerPoliciesOfListener{
+		LoadBalancerName: lbName,
+		LoadBalancerPort: lbPort,
+		PolicyNames:      []string{},
+	}
+
+	log.Printf("[DEBUG] ELB clear listener policies: %#v", setOpts)
+	if _, err := conn.SetLoadBalancerPoliciesOfListener(&setOpts); err != nil {
+		elberr, ok := err.(*elb.Error)
+		if !ok || elberr.Code != "ListenerNotFound" {
+			return fmt.Errorf(
+				"Error clearing policies on listener %d: %s", lbPort, err)
+		}
+	}
+
+	deleteOpts := elb.DeleteLoadBalancerPolicy{
+		LoadBalancerName: lbName,
+		PolicyName:       policyName,
+	}
+
+	log.Printf("[DEBUG] ELB delete SSL policy: %#v", deleteOpts)
+	if _, err := conn.DeleteLoadBalancerPolicy(&deleteOpts); err != nil {
+		elberr, ok := err.(*elb.Error)
+		if !ok || elberr.Code != "PolicyNotFound" {
+			return fmt.Errorf("Error deleting SSL policy %s: %s", policyName, err)
+		}
+	}
+
+	return nil
+}
+
+// diffElbListeners compares the listeners removed from and added to the
+// configuration. A listener that only changed its certificate shows up in
+// both, on the same lb_port, and is returned in rotate with the new
+// certificate. Every other new listener is returned in add. The old
+// listeners on the ports of those are returned in replace, and the rest
+// in remove.
+func diffElbListeners(o, n []elb.Listener) (rotate, replace, remove, add []elb.Listener) {
+	byPort := make(map[int64]elb.Listener, len(o))
+	for _, l := range o {
+		byPort[l.LoadBalancerPort] = l
+	}
+
+	taken := make(map[int64]bool, len(n))
+	for _, l := range n {
+		old, ok := byPort[l.LoadBalancerPort]
+		if ok && old.InstancePort == l.InstancePort &&
+			strings.EqualFold(old.InstanceProtocol, l.InstanceProtocol) &&
+			strings.EqualFold(old.Protocol, l.Protocol) &&
+			old.SSLCertificateId != l.SSLCertificateId &&
+			l.SSLCertificateId != "" {
+			rotate = append(rotate, l)
+			delete(byPort, l.LoadBalancerPort)
+			continue
+		}
+
+		add = append(add, l)
+		taken[l.LoadBalancerPort] = true
+	}
+
+	for _, l := range o {
+		if _, ok := byPort[l.LoadBalancerPort]; !ok {
+			continue
+		}
+
+		if taken[l.LoadBalancerPort] {
+			replace = append(replace, l)
+		} else {
+			remove = append(remove, l)
+		}
+	}
+
+	return
+}
+
+// deleteElbListeners deletes the listeners from the ELB, if there are any.
+func deleteElbListeners(conn *elb.ELB, name string, listeners []elb.Listener) error {
+	if len(listeners) == 0 {
+		return nil
+	}
+
+	ports := make([]int64, 0, len(listeners))
+	for _, l := range listeners {
+		ports = append(ports, l.LoadBalancerPort)
+	}
+
+	deleteOpts := elb.DeleteLoadBalancerListeners{
+		LoadBalancerName:  name,
+		LoadBalancerPorts: ports,
+	}
+
+	log.Printf("[DEBUG] ELB delete listeners: %#v", deleteOpts)
+	if _, err := conn.DeleteLoadBalancerListeners(&deleteOpts); err != nil {
+		return fmt.Errorf("Failure removing listeners: %s", err)
+	}
+
+	return nil
+}
+
+// updateElbSubnets attaches the ELB to the subnets in attach, then
+// detaches it from those in detach. An ELB in a VPC must always be in at
+// least one subnet, so attaching first lets the only subnet be swapped
+// for another.
+func updateElbSubnets(conn *queryConn, name string, attach, detach []string) error {
+	if len(attach) > 0 {
+		params := elbSubnetParams(name, attach)
+		log.Printf("[DEBUG] ELB attach to subnets: %#v", params)
+		if err := conn.Request("AttachLoadBalancerToSubnets", params, nil); err != nil {
+			return fmt.Errorf("Failure attaching ELB to subnets: %s", err)
+		}
+	}
+
+	if len(detach) > 0 {
+		params := elbSubnetParams(name, detach)
+		log.Printf("[DEBUG] ELB detach from subnets: %#v", params)
+		if err := conn.Request("DetachLoadBalancerFromSubnets", params, nil); err != nil {
+			return fmt.Errorf("Failure detaching ELB from subnets: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func elbSubnetParams(name string, subnets []string) map[string]string {
+	params := map[string]string{
+		"LoadBalancerName": name,
+	}
+	for i, s := range subnets {
+		params[fmt.Sprintf("Subnets.member.%d", i+1)] = s
+	}
+
+	return params
+}
+
+// resourceAwsElbValidate checks at plan time what the schema can't: where
+// the ELB is placed, that there's at most one health check, the
+// certificates of the listeners and the keys of additional_attributes.
+func resourceAwsElbValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	if err := resourceAwsElbValidatePlacement(c); err != nil {
+		es = append(es, err)
+	}
+	if _, ok := c.Get("health_check.1"); ok {
+		es = append(es, fmt.Errorf("Only one health_check block can be set"))
+	}
+	es = append(es, resourceAwsElbValidateListeners(c)...)
+	es = append(es, resourceAwsElbValidateAdditionalAttributes(c)...)
+	return
+}
+
+// resourceAwsElbValidatePlacement checks that exactly one of subnets and
+// availability_zones is set, and that subnets isn't empty. ELBs in a VPC
+// are placed in subnets, and ones in EC2-Classic in availability zones;
+// the API rejects both together.
+func resourceAwsElbValidatePlacement(c *terraform.ResourceConfig) error {
+	subnets := c.IsSet("subnets")
+	zones := c.IsSet("availability_zones")
+
+	switch {
+	case subnets && zones:
+		return fmt.Errorf(
+			"Only one of subnets, for an ELB in a VPC, or availability_zones, " +
+				"for an ELB in EC2-Classic, can be set")
+	case !subnets && !zones:
+		return fmt.Errorf(
+			"One of subnets, for an ELB in a VPC, or availability_zones, " +
+				"for an ELB in EC2-Classic, must be set")
+	}
+
+	// An ELB in a VPC can't be left without a subnet
+	if raw, ok := c.Get("subnets"); ok {
+		if l, ok := raw.([]interface{}); ok && len(l) == 0 {
+			return fmt.Errorf("subnets must have at least one subnet")
+		}
+	}
+
+	return nil
+}
+
+// resourceAwsElbValidateListeners checks that only the listeners that
+// terminate SSL have certificates. Listeners with a computed protocol are
+// left for expandListeners to check at apply time.
+func resourceAwsElbValidateListeners(c *terraform.ResourceConfig) (es []error) {
+	raw, ok := c.Get("listener")
+	if !ok {
+		return
+	}
+
+	var listeners []map[string]interface{}
+	switch l := raw.(type) {
+	case []map[string]interface{}:
+		listeners = l
+	case []interface{}:
+		for _, v := range l {
+			if m, ok := v.(map[string]interface{}); ok {
+				listeners = append(listeners, m)
+			}
+		}
+	}
+
+	for _, m := range listeners {
+		protocol, _ := m["lb_protocol"].(string)
+		if protocol == "" || protocol == config.UnknownVariableValue {
+			continue
+		}
+
+		l := elb.Listener{Protocol: protocol}
+		l.SSLCertificateId, _ = m["ssl_certificate_id"].(string)
+		if port, err := strconv.ParseInt(fmt.Sprint(m["lb_port"]), 10, 64); err == nil {
+			l.LoadBalancerPort = port
+		}
+
+		if err := validateListenerCertificate(l); err != nil {
+			es = append(es, err)
+		}
+	}
+
+	return
+}
+
+// resourceAwsElbValidateAdditionalAttributes checks the keys of
+// additional_attributes. The schema can't validate maps itself.
+func resourceAwsElbValidateAdditionalAttributes(c *terraform.ResourceConfig) []error {
+	if c.IsComputed("additional_attributes") {
+		return nil
+	}
+
+	raw, ok := c.Get("additional_attributes")
+	if !ok {
+		return nil
+	}
+
+	// Maps come out of the configuration as a list of maps
+	attrs := make(map[string]interface{})
+	switch m := raw.(type) {
+	case map[string]interface{}:
+		attrs = m
+	case []map[string]interface{}:
+		for _, inner := range m {
+			for k, v := range inner {
+				attrs[k] = v
+			}
+		}
+	case []interface{}:
+		for _, inner := range m {
+			if inner, ok := inner.(map[string]interface{}); ok {
+				for k, v := range inner {
+					attrs[k] = v
+				}
+			}
+		}
+	}
+
+	return validateElbAdditionalAttributes(attrs)
+}
+
+// elbManagedInstances returns the registered instances to read into
+// instances, or nil if Terraform doesn't manage them. Terraform only
+// manages the instances when some were set, and then keeps the list exact.
+// Otherwise something else, such as an autoscaling group, registers them,
+// and reading them in would only make Terraform try to deregister them
+// once instances is set. registered_instances has them either way.
+func elbManagedInstances(known *schema.Set, registered []elb.Instance) []string {
+	if known.Len() == 0 {
+		return nil
+	}
+
+	return flattenInstances(registered)
+}
+
+// elbSchemeInternal maps the scheme of an ELB to the internal attribute.
+// It returns false for ok if the scheme isn't one we know.
+func elbSchemeInternal(scheme string) (internal bool, ok bool) {
+	switch scheme {
+	case "internal":
+		return true, true
+	case "internet-facing":
+		return false, true
+	}
+
+	return false, false
+}
+
+// preserveElbListenerCertificates keeps the spelling of the certificate
+// IDs we know for listeners read back from the API. IAM doesn't care about
+// the case of certificate names, and changing it would change the hash
+// of the listener and show a diff that can never be applied.
+func preserveElbListenerCertificates(listeners []map[string]interface{}, known []interface{}) {
+	certs := make(map[int64]string, len(known))
+	for _, raw := range known {
+		m := raw.(map[string]interface{})
+		if v, ok := m["ssl_certificate_id"].(string); ok && v != "" {
+			certs[int64(m["lb_port"].(int))] = v
+		}
+	}
+
+	for _, l := range listeners {
+		cert, ok := certs[l["lb_port"].(int64)]
+		if ok && elbCertificateIdsEqual(cert, l["ssl_certificate_id"].(string)) {
+			l["ssl_certificate_id"] = cert
+		}
+	}
+}
+
+// elbCertificateIdsEqual returns true if two certificate ARNs name the
+// same certificate. Both IAM server certificates and ACM certificates are
+// accepted as they are, but the ELB API doesn't always return them the way
+// they were given: the parts of the ARN other than the resource are case
+// insensitive, as are IAM certificate names, and IAM ARNs may or may not
+// include the path of the certificate.
+func elbCertificateIdsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	pa := strings.SplitN(a, ":", 6)
+	pb := strings.SplitN(b, ":", 6)
+	if len(pa) != 6 || len(pb) != 6 || !strings.EqualFold(pa[0], "arn") {
+		return strings.EqualFold(a, b)
+	}
+	for i := 0; i < 5; i++ {
+		if !strings.EqualFold(pa[i], pb[i]) {
+			return false
+		}
+	}
+
+	switch strings.ToLower(pa[2]) {
+	case "iam":
+		// server-certificate/path/name
+		ra := strings.SplitN(pa[5], "/", 2)
+		rb := strings.SplitN(pb[5], "/", 2)
+		if len(ra) != 2 || len(rb) != 2 || !strings.EqualFold(ra[0], rb[0]) {
+			return false
+		}
+
+		na := ra[1][strings.LastIndex(ra[1], "/")+1:]
+		nb := rb[1][strings.LastIndex(rb[1], "/")+1:]
+		return strings.EqualFold(na, nb)
+	case "acm":
+		// certificate/id, where the id is a lowercase UUID
+		return strings.EqualFold(pa[5], pb[5])
+	}
+
+	return pa[5] == pb[5]
+}
+
+func resourceAwsElbSslPolicyHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%d-", m["healthy_threshold"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["unhealthy_threshold"].(int)))
-	buf.WriteString(fmt.Sprintf("%s-", m["target"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["interval"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["timeout"].(int)))
+	buf.WriteString(fmt.Sprintf("%d-", m["lb_port"].(int)))
+	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
+
+	// The expanded attributes are sorted, so they give us a stable hash
+	// of both the reference policy and any custom attributes.
+	for _, attr := range expandSslPolicyAttributes(m) {
+		buf.WriteString(fmt.Sprintf("%s=%s-", attr.AttributeName, attr.AttributeValue))
+	}
 
 	return hashcode.String(buf.String())
 }
@@ -366,10 +1293,24 @@ func resourceAwsElbHealthCheckHash(v interface{}) int {
 func resourceAwsElbListenerHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%d-", m["instance_port"].(int)))
-	buf.WriteString(fmt.Sprintf("%s-", m["instance_protocol"].(string)))
+
+	// A listener without an instance port or protocol gets the lb_port
+	// or lb_protocol, so it's hashed the same as one that has them
+	instancePort, _ := m["instance_port"].(int)
+	if instancePort == 0 {
+		instancePort = m["lb_port"].(int)
+	}
+	instanceProtocol, _ := m["instance_protocol"].(string)
+	if instanceProtocol == "" {
+		instanceProtocol = m["lb_protocol"].(string)
+	}
+
+	buf.WriteString(fmt.Sprintf("%d-", instancePort))
+	// Protocols are hashed in lowercase, whatever case they're in, so a
+	// listener hashes the same as it did when they were stored that way
+	buf.WriteString(fmt.Sprintf("%s-", strings.ToLower(instanceProtocol)))
 	buf.WriteString(fmt.Sprintf("%d-", m["lb_port"].(int)))
-	buf.WriteString(fmt.Sprintf("%s-", m["lb_protocol"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", strings.ToLower(m["lb_protocol"].(string))))
 
 	if v, ok := m["ssl_certificate_id"]; ok {
 		buf.WriteString(fmt.Sprintf("%s-", v.(string)))
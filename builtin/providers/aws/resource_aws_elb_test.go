@@ -2,13 +2,23 @@ package aws
 
 import (
 	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
 	"github.com/mitchellh/goamz/elb"
 )
 
@@ -37,13 +47,13 @@ func TestAccAWSELB_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"aws_elb.bar", "listener.206423021.instance_port", "8000"),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "listener.206423021.instance_protocol", "http"),
+						"aws_elb.bar", "listener.206423021.instance_protocol", "HTTP"),
 					resource.TestCheckResourceAttr(
 						"aws_elb.bar", "listener.206423021.ssl_certificate_id", ssl_certificate_id),
 					resource.TestCheckResourceAttr(
 						"aws_elb.bar", "listener.206423021.lb_port", "80"),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "listener.206423021.lb_protocol", "http"),
+						"aws_elb.bar", "listener.206423021.lb_protocol", "HTTP"),
 					resource.TestCheckResourceAttr(
 						"aws_elb.bar", "cross_zone_load_balancing", "true"),
 				),
@@ -82,12 +92,83 @@ func TestAccAWSELB_InstanceAttaching(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAWSELBExists("aws_elb.bar", &conf),
 					testCheckInstanceAttached(1),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "registered_instances.#", "1"),
 				),
 			},
 		},
 	})
 }
 
+func TestElbManagedInstances(t *testing.T) {
+	registered := []elb.Instance{
+		elb.Instance{InstanceId: "i-1234"},
+		elb.Instance{InstanceId: "i-5678"},
+	}
+	hash := func(v interface{}) int {
+		return hashcode.String(v.(string))
+	}
+
+	// Left to an autoscaling group
+	if instances := elbManagedInstances(&schema.Set{F: hash}, registered); instances != nil {
+		t.Fatalf("bad: %#v", instances)
+	}
+
+	// Managed by Terraform, which keeps the list exact
+	known := &schema.Set{F: hash}
+	known.Add("i-1234")
+	instances := elbManagedInstances(known, registered)
+	if !reflect.DeepEqual(instances, []string{"i-1234", "i-5678"}) {
+		t.Fatalf("bad: %#v", instances)
+	}
+}
+
+func TestAccAWSELB_Internal(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigInternal,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "internal", "true"),
+					func(*terraform.State) error {
+						if conf.Scheme != "internal" {
+							return fmt.Errorf("bad scheme: %s", conf.Scheme)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestElbSchemeInternal(t *testing.T) {
+	cases := []struct {
+		Scheme   string
+		Internal bool
+		Ok       bool
+	}{
+		{"internal", true, true},
+		{"internet-facing", false, true},
+		{"", false, false},
+		{"Internal", false, false},
+	}
+
+	for _, tc := range cases {
+		internal, ok := elbSchemeInternal(tc.Scheme)
+		if internal != tc.Internal || ok != tc.Ok {
+			t.Fatalf("%q: bad: %t, %t", tc.Scheme, internal, ok)
+		}
+	}
+}
+
 func TestAccAWSELB_AddSubnet(t *testing.T) {
 	var conf elb.LoadBalancer
 
@@ -109,7 +190,7 @@ func TestAccAWSELB_AddSubnet(t *testing.T) {
 				Config: testAccAWSELBConfigVPC,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAWSELBExists("aws_elb.bar", &conf),
-					testAccCheckAWSELBAttributes(&conf),
+					testCheckSubnetsAdded(1),
 				),
 			},
 
@@ -118,12 +199,240 @@ func TestAccAWSELB_AddSubnet(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAWSELBExists("aws_elb.bar", &conf),
 					testCheckSubnetsAdded(2),
+					testAccCheckAWSELBZonesFromSubnets("aws_elb.bar", &conf),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_SwapSubnet(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	testCheckSubnet := func(n string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			rs, ok := s.RootModule().Resources[n]
+			if !ok {
+				return fmt.Errorf("Not found: %s", n)
+			}
+			if len(conf.Subnets) != 1 || conf.Subnets[0] != rs.Primary.ID {
+				return fmt.Errorf("bad subnets: %v, expected %s", conf.Subnets, rs.Primary.ID)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBSwapSubnet, "baz"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckSubnet("aws_subnet.baz"),
+				),
+			},
+
+			// The only subnet is replaced in place by another
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBSwapSubnet, "foo"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckSubnet("aws_subnet.foo"),
+				),
+			},
+		},
+	})
+}
+
+func TestUpdateElbSubnets(t *testing.T) {
+	var requests []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		fmt.Fprint(w, `<Response></Response>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2012-06-01",
+		httpClient: http.DefaultClient,
+	}
+
+	// Swapping the only subnet attaches the new one before the old one is
+	// detached, so the ELB is never without a subnet
+	err := updateElbSubnets(conn, "foo", []string{"subnet-new"}, []string{"subnet-old"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	expected := []map[string]string{
+		{
+			"Action":           "AttachLoadBalancerToSubnets",
+			"LoadBalancerName": "foo",
+			"Subnets.member.1": "subnet-new",
+		},
+		{
+			"Action":           "DetachLoadBalancerFromSubnets",
+			"LoadBalancerName": "foo",
+			"Subnets.member.1": "subnet-old",
+		},
+	}
+	for i, e := range expected {
+		for k, v := range e {
+			if requests[i].Get(k) != v {
+				t.Fatalf("%d: bad %s: %q, expected %q", i, k, requests[i].Get(k), v)
+			}
+		}
+	}
+
+	// Nothing to do, nothing is sent
+	requests = nil
+	if err := updateElbSubnets(conn, "foo", nil, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("expected no requests, got %d", len(requests))
+	}
+}
+
+func TestAccAWSELB_SecurityGroups(t *testing.T) {
+	var conf elb.LoadBalancer
+	var dnsName string
+
+	testCheckSecurityGroup := func(n string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			rs, ok := s.RootModule().Resources[n]
+			if !ok {
+				return fmt.Errorf("Not found: %s", n)
+			}
+
+			if len(conf.SecurityGroups) != 1 || conf.SecurityGroups[0] != rs.Primary.ID {
+				return fmt.Errorf(
+					"expected security group %s, got %#v",
+					rs.Primary.ID, conf.SecurityGroups)
+			}
+			return nil
+		}
+	}
+
+	// Swapping the groups must not recreate the ELB, which would give it
+	// a new DNS name
+	testCheckDNSName := func(*terraform.State) error {
+		if dnsName == "" {
+			dnsName = conf.DNSName
+		}
+		if conf.DNSName != dnsName {
+			return fmt.Errorf("ELB was recreated: %s != %s", conf.DNSName, dnsName)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigSecurityGroups,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckSecurityGroup("aws_security_group.foo"),
+					testCheckDNSName,
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSELBConfigSecurityGroupsUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckSecurityGroup("aws_security_group.bar"),
+					testCheckDNSName,
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "name", "foobar-terraform-test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_WaitForDns(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigWaitForDns,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					func(*terraform.State) error {
+						if _, err := net.LookupHost(conf.DNSName); err != nil {
+							return fmt.Errorf("DNS name not resolvable: %s", err)
+						}
+						return nil
+					},
 				),
 			},
 		},
 	})
 }
 
+func TestValidateElbWaitForDns(t *testing.T) {
+	for _, v := range []string{"", "populated", "resolvable"} {
+		if _, es := validateElbWaitForDns(v, "wait_for_dns"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	if _, es := validateElbWaitForDns("true", "wait_for_dns"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestPendingElbInstances(t *testing.T) {
+	cases := []struct {
+		Registered, Add, Remove []string
+		Pending                 []string
+	}{
+		// Everything settled
+		{
+			Registered: []string{"i-1", "i-2"},
+			Add:        []string{"i-2"},
+			Remove:     []string{"i-3"},
+		},
+
+		// Still registering
+		{
+			Registered: []string{"i-1"},
+			Add:        []string{"i-2"},
+			Pending:    []string{"i-2"},
+		},
+
+		// Still deregistering
+		{
+			Registered: []string{"i-1", "i-3"},
+			Remove:     []string{"i-3"},
+			Pending:    []string{"i-3"},
+		},
+	}
+
+	for i, tc := range cases {
+		pending := pendingElbInstances(tc.Registered, tc.Add, tc.Remove)
+		if !reflect.DeepEqual(pending, tc.Pending) {
+			t.Fatalf("%d: bad: %#v", i, pending)
+		}
+	}
+}
+
 func TestAccAWSELB_HealthCheck(t *testing.T) {
 	var conf elb.LoadBalancer
 
@@ -138,22 +447,727 @@ func TestAccAWSELB_HealthCheck(t *testing.T) {
 					testAccCheckAWSELBExists("aws_elb.bar", &conf),
 					testAccCheckAWSELBAttributesHealthCheck(&conf),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "health_check.3484319807.healthy_threshold", "5"),
+						"aws_elb.bar", "health_check.0.healthy_threshold", "5"),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "health_check.0.unhealthy_threshold", "5"),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "health_check.0.target", "HTTP:8000/"),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "health_check.0.timeout", "30"),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "health_check.0.interval", "60"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_attributes(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testAccCheckAWSELBAttributesApplied("aws_elb.bar", true, 60, false),
+				),
+			},
+
+			// Changing two attributes at once mustn't reset the others
+			resource.TestStep{
+				Config: testAccAWSELBConfigAttributes,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testAccCheckAWSELBAttributesApplied("aws_elb.bar", true, 400, true),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "idle_timeout", "400"),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "health_check.3484319807.unhealthy_threshold", "5"),
+						"aws_elb.bar", "connection_draining", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_crossZoneDefault(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigCrossZoneDefault,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testAccCheckAWSELBAttributesApplied("aws_elb.bar", false, 60, false),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "cross_zone_load_balancing", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_accessLogs(t *testing.T) {
+	var conf elb.LoadBalancer
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigAccessLogs,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testAccCheckAWSELBAccessLogsEnabled("aws_elb.bar", true),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "health_check.3484319807.target", "HTTP:8000/"),
+						"aws_elb.bar", "access_logs.#", "1"),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "health_check.3484319807.timeout", "30"),
+						"aws_elb.bar", "access_logs.0.bucket", testAccAWSELBAccessLogsBucket),
+				),
+			},
+
+			// Removing the block turns logging off again
+			resource.TestStep{
+				Config: testAccAWSELBConfigAccessLogsRemoved,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBAccessLogsEnabled("aws_elb.bar", false),
 					resource.TestCheckResourceAttr(
-						"aws_elb.bar", "health_check.3484319807.interval", "60"),
+						"aws_elb.bar", "access_logs.#", "0"),
 				),
 			},
 		},
 	})
 }
+
+func TestAccAWSELB_SslPolicy(t *testing.T) {
+	var conf elb.LoadBalancer
+	ssl_certificate_id := os.Getenv("AWS_SSL_CERTIFICATE_ID")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBConfigSslPolicy, ssl_certificate_id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "ssl_policy.#", "1"),
+				),
+			},
+
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBConfigListenerSSLCertificateId, ssl_certificate_id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_elb.bar", "ssl_policy.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_RotateSSLCertificate(t *testing.T) {
+	var conf elb.LoadBalancer
+	var dnsName string
+	ssl_certificate_id := os.Getenv("AWS_SSL_CERTIFICATE_ID")
+	rotated_certificate_id := os.Getenv("AWS_SSL_CERTIFICATE_ID_ROTATED")
+
+	testCheckCertificate := func(id string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if len(conf.Listeners) != 1 {
+				return fmt.Errorf("expected 1 listener, got %#v", conf.Listeners)
+			}
+			if conf.Listeners[0].SSLCertificateId != id {
+				return fmt.Errorf(
+					"expected certificate %s, got %s",
+					id, conf.Listeners[0].SSLCertificateId)
+			}
+			return nil
+		}
+	}
+
+	// Rotating the certificate must not recreate the ELB, which would
+	// give it a new DNS name
+	testCheckDNSName := func(*terraform.State) error {
+		if dnsName == "" {
+			dnsName = conf.DNSName
+		}
+		if conf.DNSName != dnsName {
+			return fmt.Errorf("ELB was recreated: %s != %s", conf.DNSName, dnsName)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBConfigListenerSSLCertificateId, ssl_certificate_id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckCertificate(ssl_certificate_id),
+					testCheckDNSName,
+				),
+			},
+
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBConfigListenerSSLCertificateId, rotated_certificate_id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckCertificate(rotated_certificate_id),
+					testCheckDNSName,
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSELB_ChangeListenerProtocol(t *testing.T) {
+	var conf elb.LoadBalancer
+	var dnsName string
+	ssl_certificate_id := os.Getenv("AWS_SSL_CERTIFICATE_ID")
+
+	testCheckProtocol := func(protocol string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if len(conf.Listeners) != 1 {
+				return fmt.Errorf("expected 1 listener, got %#v", conf.Listeners)
+			}
+			if l := conf.Listeners[0]; l.LoadBalancerPort != 443 ||
+				!strings.EqualFold(l.Protocol, protocol) {
+				return fmt.Errorf("bad listener: %#v", l)
+			}
+			return nil
+		}
+	}
+
+	// The listener is replaced on the same port, in place
+	testCheckDNSName := func(*terraform.State) error {
+		if dnsName == "" {
+			dnsName = conf.DNSName
+		}
+		if conf.DNSName != dnsName {
+			return fmt.Errorf("ELB was recreated: %s != %s", conf.DNSName, dnsName)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSELBDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSELBConfigListenerHTTP443,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckProtocol("http"),
+					testCheckDNSName,
+				),
+			},
+
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSELBConfigListenerSSLCertificateId, ssl_certificate_id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSELBExists("aws_elb.bar", &conf),
+					testCheckProtocol("https"),
+					testCheckDNSName,
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAwsElbListener_refreshCase(t *testing.T) {
+	configured := map[string]interface{}{
+		"instance_port":      8000,
+		"instance_protocol":  "HTTP",
+		"lb_port":            443,
+		"lb_protocol":        "HTTPS",
+		"ssl_certificate_id": "arn:aws:iam::123456789012:server-certificate/MyCert",
+	}
+
+	// What the API returns for the listener above
+	testElbListenerRefreshDiff(t, configured, elb.Listener{
+		InstancePort:     8000,
+		InstanceProtocol: "HTTP",
+		LoadBalancerPort: 443,
+		Protocol:         "HTTPS",
+		SSLCertificateId: "arn:aws:iam::123456789012:server-certificate/mycert",
+	})
+}
+
+func TestResourceAwsElbListener_refreshACM(t *testing.T) {
+	configured := map[string]interface{}{
+		"instance_port":      8000,
+		"instance_protocol":  "http",
+		"lb_port":            443,
+		"lb_protocol":        "https",
+		"ssl_certificate_id": "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+	}
+
+	testElbListenerRefreshDiff(t, configured, elb.Listener{
+		InstancePort:     8000,
+		InstanceProtocol: "HTTP",
+		LoadBalancerPort: 443,
+		Protocol:         "HTTPS",
+		SSLCertificateId: "arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+	})
+}
+
+func TestResourceAwsElb_subnetZonesNoDiff(t *testing.T) {
+	attrs := map[string]string{
+		"name":                 "foo",
+		"subnets.#":            "2",
+		"availability_zones.#": "2",
+	}
+	for _, subnet := range []string{"subnet-1234", "subnet-5678"} {
+		attrs[fmt.Sprintf("subnets.%d", hashcode.String(subnet))] = subnet
+	}
+	for _, zone := range []string{"us-west-2a", "us-west-2b"} {
+		attrs[fmt.Sprintf("availability_zones.%d", hashcode.String(zone))] = zone
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":    "foo",
+		"subnets": []interface{}{"subnet-1234", "subnet-5678"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	diff, err := resourceAwsElb().Diff(
+		&terraform.InstanceState{ID: "foo", Attributes: attrs},
+		terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil {
+		return
+	}
+
+	for k, attr := range diff.Attributes {
+		if strings.HasPrefix(k, "availability_zones") {
+			t.Fatalf("unexpected availability_zones diff: %s: %#v", k, attr)
+		}
+	}
+}
+
+func TestResourceAwsElb_crossZoneDefaultNoDiff(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":               "foo",
+		"availability_zones": []interface{}{"us-west-2a"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// An ELB created without the attribute reads back disabled
+	diff, err := resourceAwsElb().Diff(
+		&terraform.InstanceState{
+			ID: "foo",
+			Attributes: map[string]string{
+				"name":                      "foo",
+				"cross_zone_load_balancing": "false",
+			},
+		},
+		terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil {
+		return
+	}
+
+	if attr, ok := diff.Attributes["cross_zone_load_balancing"]; ok {
+		t.Fatalf("unexpected cross_zone_load_balancing diff: %#v", attr)
+	}
+}
+
+func TestResourceAwsElbListener_refreshDefaults(t *testing.T) {
+	configured := map[string]interface{}{
+		"lb_port":     80,
+		"lb_protocol": "http",
+	}
+
+	testElbListenerRefreshDiff(t, configured, elb.Listener{
+		InstancePort:     80,
+		InstanceProtocol: "HTTP",
+		LoadBalancerPort: 80,
+		Protocol:         "HTTP",
+	})
+}
+
+func TestElbCertificateIdsEqual(t *testing.T) {
+	cases := []struct {
+		A, B  string
+		Equal bool
+	}{
+		{
+			"arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+			"arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+			true,
+		},
+		{
+			"arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+			"arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789abc",
+			false,
+		},
+		{
+			"arn:aws:acm:us-east-1:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+			"arn:aws:acm:us-west-2:123456789012:certificate/12345678-1234-1234-1234-123456789012",
+			false,
+		},
+		{
+			"arn:aws:iam::123456789012:server-certificate/MyCert",
+			"arn:aws:iam::123456789012:server-certificate/mycert",
+			true,
+		},
+		{
+			"arn:aws:iam::123456789012:server-certificate/MyCert",
+			"arn:aws:iam::123456789012:server-certificate/cloudfront/MyCert",
+			true,
+		},
+		{
+			"arn:aws:iam::123456789012:server-certificate/MyCert",
+			"arn:aws:iam::210987654321:server-certificate/MyCert",
+			false,
+		},
+		{
+			"arn:aws:iam::123456789012:server-certificate/MyCert",
+			"arn:aws:acm:us-east-1:123456789012:certificate/MyCert",
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		if elbCertificateIdsEqual(tc.A, tc.B) != tc.Equal {
+			t.Fatalf("%d: expected %t: %s, %s", i, tc.Equal, tc.A, tc.B)
+		}
+		if elbCertificateIdsEqual(tc.B, tc.A) != tc.Equal {
+			t.Fatalf("%d: not symmetric: %s, %s", i, tc.A, tc.B)
+		}
+	}
+}
+
+// testElbListenerRefreshDiff checks that refreshing the configured
+// listener, when the API returns it as l, doesn't make a diff.
+func testElbListenerRefreshDiff(t *testing.T, configured map[string]interface{}, l elb.Listener) {
+	listeners := flattenListeners([]elb.Listener{l})
+	preserveElbListenerCertificates(listeners, []interface{}{configured})
+
+	// Write the listeners to the state the way they'd be stored in a set
+	attrs := map[string]string{"listener.#": fmt.Sprintf("%d", len(listeners))}
+	for _, l := range listeners {
+		m := make(map[string]interface{}, len(l))
+		for k, v := range l {
+			if i, ok := v.(int64); ok {
+				v = int(i)
+			}
+			m[k] = v
+		}
+
+		hash := resourceAwsElbListenerHash(m)
+		for k, v := range m {
+			attrs[fmt.Sprintf("listener.%d.%s", hash, k)] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"listener": []map[string]interface{}{configured},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	diff, err := resourceAwsElb().Diff(
+		&terraform.InstanceState{ID: "foo", Attributes: attrs},
+		terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil {
+		return
+	}
+
+	for k, attr := range diff.Attributes {
+		if strings.HasPrefix(k, "listener.") {
+			t.Fatalf("unexpected listener diff: %s: %#v", k, attr)
+		}
+	}
+}
+
+func TestResourceAwsElbValidate_listeners(t *testing.T) {
+	listener := func(protocol, instanceProtocol, cert string) map[string]interface{} {
+		m := map[string]interface{}{
+			"instance_port":     8000,
+			"instance_protocol": instanceProtocol,
+			"lb_port":           443,
+			"lb_protocol":       protocol,
+		}
+		if cert != "" {
+			m["ssl_certificate_id"] = cert
+		}
+		return m
+	}
+	cert := "arn:aws:iam::123456789012:server-certificate/foo"
+
+	cases := []struct {
+		Listener map[string]interface{}
+		Err      bool
+	}{
+		{listener("http", "http", ""), false},
+		{listener("tcp", "tcp", ""), false},
+		{listener("https", "http", cert), false},
+		{listener("ssl", "tcp", cert), false},
+		{listener("http", "http", cert), true},
+		{listener("tcp", "tcp", cert), true},
+		{listener("https", "http", ""), true},
+		{listener("ssl", "tcp", ""), true},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(map[string]interface{}{
+			"availability_zones": []interface{}{"us-west-2a"},
+			"listener":           []map[string]interface{}{tc.Listener},
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsElbValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestResourceAwsElbValidate_placement(t *testing.T) {
+	listener := map[string]interface{}{
+		"instance_port":     8000,
+		"instance_protocol": "http",
+		"lb_port":           80,
+		"lb_protocol":       "http",
+	}
+
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			map[string]interface{}{
+				"availability_zones": []interface{}{"us-west-2a"},
+			},
+			false,
+		},
+		{
+			map[string]interface{}{
+				"subnets": []interface{}{"subnet-12345678"},
+			},
+			false,
+		},
+		{
+			map[string]interface{}{
+				"availability_zones": []interface{}{"us-west-2a"},
+				"subnets":            []interface{}{"subnet-12345678"},
+			},
+			true,
+		},
+		{
+			map[string]interface{}{},
+			true,
+		},
+		{
+			map[string]interface{}{
+				"subnets": []interface{}{},
+			},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		tc.Config["listener"] = []map[string]interface{}{listener}
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsElbValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestResourceAwsElbValidate_healthCheck(t *testing.T) {
+	check := map[string]interface{}{
+		"healthy_threshold":   5,
+		"unhealthy_threshold": 5,
+		"target":              "HTTP:8000/",
+		"interval":            60,
+		"timeout":             30,
+	}
+
+	cases := []struct {
+		Checks []map[string]interface{}
+		Err    bool
+	}{
+		{nil, false},
+		{[]map[string]interface{}{check}, false},
+		{[]map[string]interface{}{check, check}, true},
+	}
+
+	for i, tc := range cases {
+		raw := map[string]interface{}{
+			"availability_zones": []interface{}{"us-west-2a"},
+			"listener": []map[string]interface{}{
+				map[string]interface{}{
+					"instance_port":     8000,
+					"instance_protocol": "http",
+					"lb_port":           80,
+					"lb_protocol":       "http",
+				},
+			},
+		}
+		if tc.Checks != nil {
+			raw["health_check"] = tc.Checks
+		}
+
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsElbValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestDiffElbListeners(t *testing.T) {
+	http := elb.Listener{
+		InstancePort:     8000,
+		InstanceProtocol: "http",
+		LoadBalancerPort: 80,
+		Protocol:         "http",
+	}
+	https := elb.Listener{
+		InstancePort:     8000,
+		InstanceProtocol: "http",
+		LoadBalancerPort: 443,
+		Protocol:         "https",
+		SSLCertificateId: "arn:aws:iam::123456789012:server-certificate/old",
+	}
+	rotated := https
+	rotated.SSLCertificateId = "arn:aws:iam::123456789012:server-certificate/new"
+	rotatedUpper := rotated
+	rotatedUpper.InstanceProtocol = "HTTP"
+	rotatedUpper.Protocol = "HTTPS"
+	moved := https
+	moved.InstancePort = 8443
+	moved.SSLCertificateId = rotated.SSLCertificateId
+	secured := http
+	secured.Protocol = "https"
+	secured.SSLCertificateId = rotated.SSLCertificateId
+
+	cases := []struct {
+		Old, New                     []elb.Listener
+		Rotate, Replace, Remove, Add []elb.Listener
+	}{
+		// Only the certificate changed
+		{
+			Old:    []elb.Listener{https},
+			New:    []elb.Listener{rotated},
+			Rotate: []elb.Listener{rotated},
+		},
+
+		// Protocols read back lowercased from the API
+		{
+			Old:    []elb.Listener{https},
+			New:    []elb.Listener{rotatedUpper},
+			Rotate: []elb.Listener{rotatedUpper},
+		},
+
+		// The instance port changed too, so the listener is replaced
+		{
+			Old:     []elb.Listener{https},
+			New:     []elb.Listener{moved},
+			Replace: []elb.Listener{https},
+			Add:     []elb.Listener{moved},
+		},
+
+		// The protocol changed on the same port
+		{
+			Old:     []elb.Listener{http},
+			New:     []elb.Listener{secured},
+			Replace: []elb.Listener{http},
+			Add:     []elb.Listener{secured},
+		},
+
+		// Unrelated listeners on other ports
+		{
+			Old:    []elb.Listener{http},
+			New:    []elb.Listener{rotated},
+			Remove: []elb.Listener{http},
+			Add:    []elb.Listener{rotated},
+		},
+	}
+
+	for i, tc := range cases {
+		rotate, replace, remove, add := diffElbListeners(tc.Old, tc.New)
+		if !reflect.DeepEqual(rotate, tc.Rotate) {
+			t.Fatalf("%d: bad rotate: %#v", i, rotate)
+		}
+		if !reflect.DeepEqual(replace, tc.Replace) {
+			t.Fatalf("%d: bad replace: %#v", i, replace)
+		}
+		if !reflect.DeepEqual(remove, tc.Remove) {
+			t.Fatalf("%d: bad remove: %#v", i, remove)
+		}
+		if !reflect.DeepEqual(add, tc.Add) {
+			t.Fatalf("%d: bad add: %#v", i, add)
+		}
+	}
+}
+
+// testAccCheckAWSELBZonesFromSubnets checks that a VPC ELB has the zones
+// of its subnets as its availability_zones.
+func testAccCheckAWSELBZonesFromSubnets(n string, conf *elb.LoadBalancer) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if len(conf.AvailabilityZones) == 0 {
+			return fmt.Errorf("ELB has no availability zones")
+		}
+		count := rs.Primary.Attributes["availability_zones.#"]
+		if count != fmt.Sprintf("%d", len(conf.AvailabilityZones)) {
+			return fmt.Errorf(
+				"bad availability_zones.#: %s, ELB has %v", count, conf.AvailabilityZones)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckAWSELBDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).elbconn
+	conn := testAccProvider.Meta().(*AWSClient).elbconn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_elb" {
@@ -219,6 +1233,55 @@ func testAccCheckAWSELBAttributes(conf *elb.LoadBalancer) resource.TestCheckFunc
 	}
 }
 
+func testAccCheckAWSELBAttributesApplied(
+	n string, crossZone bool, idleTimeout int, draining bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).elbquery
+		attrs, err := describeElbAttributes(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if attrs.CrossZoneLoadBalancing.Enabled != crossZone {
+			return fmt.Errorf("bad cross zone load balancing: %#v", attrs)
+		}
+		if attrs.ConnectionSettings.IdleTimeout != idleTimeout {
+			return fmt.Errorf("bad idle timeout: %#v", attrs)
+		}
+		if attrs.ConnectionDraining.Enabled != draining {
+			return fmt.Errorf("bad connection draining: %#v", attrs)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSELBAccessLogsEnabled(n string, enabled bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).elbquery
+		attrs, err := describeElbAttributes(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if attrs.AccessLog.Enabled != enabled {
+			return fmt.Errorf("bad access log: %#v", attrs.AccessLog)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckAWSELBAttributesHealthCheck(conf *elb.LoadBalancer) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		zones := []string{"us-west-2a", "us-west-2b", "us-west-2c"}
@@ -265,7 +1328,7 @@ func testAccCheckAWSELBExists(n string, res *elb.LoadBalancer) resource.TestChec
 			return fmt.Errorf("No ELB ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).elbconn
+		conn := testAccProvider.Meta().(*AWSClient).elbconn()
 
 		describe, err := conn.DescribeLoadBalancers(&elb.DescribeLoadBalancer{
 			Names: []string{rs.Primary.ID},
@@ -302,6 +1365,89 @@ resource "aws_elb" "bar" {
 }
 `
 
+const testAccAWSELBConfigCrossZoneDefault = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+}
+`
+
+var testAccAWSELBAccessLogsBucket = fmt.Sprintf("tf-test-elb-logs-%d", rand.Int())
+
+// The ELB account of us-west-2 has to be allowed to write the logs
+var testAccAWSELBConfigAccessLogsBucket = fmt.Sprintf(`
+resource "aws_s3_bucket" "logs" {
+  bucket = "%[1]s"
+  force_destroy = true
+}
+
+resource "aws_s3_bucket_policy" "logs" {
+  bucket = "${aws_s3_bucket.logs.id}"
+  policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"AWS\": \"arn:aws:iam::797873946194:root\"}, \"Action\": \"s3:PutObject\", \"Resource\": \"arn:aws:s3:::%[1]s/*\"}]}"
+}
+`, testAccAWSELBAccessLogsBucket)
+
+var testAccAWSELBConfigAccessLogs = testAccAWSELBConfigAccessLogsBucket + `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  access_logs {
+    bucket = "${aws_s3_bucket.logs.id}"
+    interval = 5
+  }
+
+  depends_on = ["aws_s3_bucket_policy.logs"]
+}
+`
+
+var testAccAWSELBConfigAccessLogsRemoved = testAccAWSELBConfigAccessLogsBucket + `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+}
+`
+
+const testAccAWSELBConfigAttributes = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  cross_zone_load_balancing = true
+  idle_timeout = 400
+  connection_draining = true
+  connection_draining_timeout = 400
+}
+`
+
 const testAccAWSELBConfigNewInstance = `
 resource "aws_elb" "bar" {
   name = "foobar-terraform-test"
@@ -327,7 +1473,6 @@ const testAccAWSELBConfigVPC = `
 resource "aws_elb" "bar" {
   vpc_id = "${aws_vpc.foobar.id}"
   name = "foobar-terraform-test"
-  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
 
   listener {
     instance_port = 8000
@@ -350,11 +1495,35 @@ resource "aws_vpc" "foobar" {
 }
 `
 
+const testAccAWSELBConfigInternal = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  internal = true
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  subnets = ["${aws_subnet.baz.id}"]
+}
+
+resource "aws_subnet" "baz" {
+  vpc_id = "${aws_vpc.foobar.id}"
+  cidr_block = "10.0.69.0/24"
+}
+
+resource "aws_vpc" "foobar" {
+  cidr_block = "10.0.0.0/16"
+}
+`
+
 const testAccAWSELBAddSubnets = `
 resource "aws_elb" "bar" {
   vpc_id = "${aws_vpc.foobar.id}"
   name = "foobar-terraform-test"
-  availability_zones = ["us-west-2a", "us-west-2b", "us-west-2c"]
 
   listener {
     instance_port = 8000
@@ -382,6 +1551,119 @@ resource "aws_vpc" "foobar" {
 }
 `
 
+const testAccAWSELBSwapSubnet = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  subnets = ["${aws_subnet.%s.id}"]
+}
+
+resource "aws_subnet" "foo" {
+  vpc_id = "${aws_vpc.foobar.id}"
+  cidr_block = "10.0.68.0/24"
+  availability_zone = "us-west-2a"
+}
+
+resource "aws_subnet" "baz" {
+  vpc_id = "${aws_vpc.foobar.id}"
+  cidr_block = "10.0.69.0/24"
+  availability_zone = "us-west-2b"
+}
+
+resource "aws_vpc" "foobar" {
+  cidr_block = "10.0.0.0/16"
+}
+`
+
+const testAccAWSELBConfigSecurityGroups = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  subnets = ["${aws_subnet.baz.id}"]
+  security_groups = ["${aws_security_group.foo.id}"]
+}
+
+resource "aws_security_group" "foo" {
+  name = "tf-test-elb-foo"
+  description = "tf-test-elb-foo"
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+
+resource "aws_security_group" "bar" {
+  name = "tf-test-elb-bar"
+  description = "tf-test-elb-bar"
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+
+resource "aws_subnet" "baz" {
+  vpc_id = "${aws_vpc.foobar.id}"
+  cidr_block = "10.0.69.0/24"
+}
+
+resource "aws_vpc" "foobar" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_internet_gateway" "gw" {
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+`
+
+const testAccAWSELBConfigSecurityGroupsUpdated = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+
+  subnets = ["${aws_subnet.baz.id}"]
+  security_groups = ["${aws_security_group.bar.id}"]
+}
+
+resource "aws_security_group" "foo" {
+  name = "tf-test-elb-foo"
+  description = "tf-test-elb-foo"
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+
+resource "aws_security_group" "bar" {
+  name = "tf-test-elb-bar"
+  description = "tf-test-elb-bar"
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+
+resource "aws_subnet" "baz" {
+  vpc_id = "${aws_vpc.foobar.id}"
+  cidr_block = "10.0.69.0/24"
+}
+
+resource "aws_vpc" "foobar" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_internet_gateway" "gw" {
+  vpc_id = "${aws_vpc.foobar.id}"
+}
+`
+
 const testAccAWSELBConfigListenerSSLCertificateId = `
 resource "aws_elb" "bar" {
   name = "foobar-terraform-test"
@@ -397,6 +1679,35 @@ resource "aws_elb" "bar" {
 }
 `
 
+const testAccAWSELBConfigListenerHTTP443 = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 443
+    lb_protocol = "http"
+  }
+}
+`
+
+const testAccAWSELBConfigWaitForDns = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a"]
+  wait_for_dns = "resolvable"
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    lb_port = 80
+    lb_protocol = "http"
+  }
+}
+`
+
 const testAccAWSELBConfigHealthCheck = `
 resource "aws_elb" "bar" {
   name = "foobar-terraform-test"
@@ -418,3 +1729,27 @@ resource "aws_elb" "bar" {
   }
 }
 `
+
+const testAccAWSELBConfigSslPolicy = `
+resource "aws_elb" "bar" {
+  name = "foobar-terraform-test"
+  availability_zones = ["us-west-2a"]
+
+  listener {
+    instance_port = 8000
+    instance_protocol = "http"
+    ssl_certificate_id = "%s"
+    lb_port = 443
+    lb_protocol = "https"
+  }
+
+  ssl_policy {
+    lb_port = 443
+    name = "foobar-terraform-test-tls"
+    reference_security_policy = "ELBSecurityPolicy-2015-02"
+    attributes {
+      Protocol-SSLv3 = "false"
+    }
+  }
+}
+`
@@ -0,0 +1,226 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsIamInstanceProfile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamInstanceProfileCreate,
+		Read:   resourceAwsIamInstanceProfileRead,
+		Update: resourceAwsIamInstanceProfileUpdate,
+		Delete: resourceAwsIamInstanceProfileDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "/",
+				ForceNew: true,
+			},
+
+			"roles": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set: func(v interface{}) int {
+					return hashcode.String(v.(string))
+				},
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"unique_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// iamInstanceProfile is an instance profile as returned by
+// GetInstanceProfile and CreateInstanceProfile.
+type iamInstanceProfile struct {
+	Arn                 string   `xml:"Arn"`
+	InstanceProfileId   string   `xml:"InstanceProfileId"`
+	InstanceProfileName string   `xml:"InstanceProfileName"`
+	Path                string   `xml:"Path"`
+	Roles               []string `xml:"Roles>member>RoleName"`
+}
+
+func resourceAwsIamInstanceProfileCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	params := map[string]string{
+		"InstanceProfileName": d.Get("name").(string),
+		"Path":                d.Get("path").(string),
+	}
+
+	var resp struct {
+		InstanceProfile iamInstanceProfile `xml:"CreateInstanceProfileResult>InstanceProfile"`
+	}
+
+	log.Printf("[DEBUG] IAM create instance profile: %#v", params)
+	if err := conn.Request("CreateInstanceProfile", params, &resp); err != nil {
+		return fmt.Errorf("Error creating instance profile: %s", err)
+	}
+
+	d.SetId(resp.InstanceProfile.InstanceProfileName)
+	log.Printf("[INFO] IAM instance profile ID: %s", d.Id())
+
+	roles := d.Get("roles").(*schema.Set)
+	if err := addIamInstanceProfileRoles(conn, d.Id(), roles.List()); err != nil {
+		return err
+	}
+
+	return resourceAwsIamInstanceProfileRead(d, meta)
+}
+
+func resourceAwsIamInstanceProfileRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	profile, err := getIamInstanceProfile(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		log.Printf("[WARN] IAM instance profile %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("path", profile.Path)
+	d.Set("roles", flattenStringSet(profile.Roles))
+	d.Set("arn", profile.Arn)
+	d.Set("unique_id", profile.InstanceProfileId)
+
+	return nil
+}
+
+func resourceAwsIamInstanceProfileUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	if d.HasChange("roles") {
+		o, n := d.GetChange("roles")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		// Remove the old roles first, since a profile can only hold a
+		// single role at a time
+		if err := removeIamInstanceProfileRoles(conn, d.Id(), os.Difference(ns).List()); err != nil {
+			return err
+		}
+		if err := addIamInstanceProfileRoles(conn, d.Id(), ns.Difference(os).List()); err != nil {
+			return err
+		}
+	}
+
+	return resourceAwsIamInstanceProfileRead(d, meta)
+}
+
+func resourceAwsIamInstanceProfileDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	// A profile that still has roles can't be deleted
+	roles := d.Get("roles").(*schema.Set)
+	if err := removeIamInstanceProfileRoles(conn, d.Id(), roles.List()); err != nil {
+		return err
+	}
+
+	params := map[string]string{
+		"InstanceProfileName": d.Id(),
+	}
+
+	log.Printf("[DEBUG] IAM delete instance profile: %s", d.Id())
+	if err := conn.Request("DeleteInstanceProfile", params, nil); err != nil {
+		if isIamNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting instance profile: %s", err)
+	}
+
+	return nil
+}
+
+// getIamInstanceProfile returns the named instance profile, or nil if it
+// doesn't exist.
+func getIamInstanceProfile(conn *queryConn, name string) (*iamInstanceProfile, error) {
+	params := map[string]string{
+		"InstanceProfileName": name,
+	}
+
+	var resp struct {
+		InstanceProfile iamInstanceProfile `xml:"GetInstanceProfileResult>InstanceProfile"`
+	}
+
+	log.Printf("[DEBUG] IAM get instance profile: %s", name)
+	if err := conn.Request("GetInstanceProfile", params, &resp); err != nil {
+		if isIamNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving instance profile: %s", err)
+	}
+
+	return &resp.InstanceProfile, nil
+}
+
+func addIamInstanceProfileRoles(conn *queryConn, profile string, roles []interface{}) error {
+	for _, role := range roles {
+		params := map[string]string{
+			"InstanceProfileName": profile,
+			"RoleName":            role.(string),
+		}
+
+		log.Printf("[DEBUG] IAM add role to instance profile: %#v", params)
+		if err := conn.Request("AddRoleToInstanceProfile", params, nil); err != nil {
+			return fmt.Errorf(
+				"Error adding role %s to instance profile: %s", role, err)
+		}
+	}
+
+	return nil
+}
+
+func removeIamInstanceProfileRoles(conn *queryConn, profile string, roles []interface{}) error {
+	for _, role := range roles {
+		params := map[string]string{
+			"InstanceProfileName": profile,
+			"RoleName":            role.(string),
+		}
+
+		log.Printf("[DEBUG] IAM remove role from instance profile: %#v", params)
+		if err := conn.Request("RemoveRoleFromInstanceProfile", params, nil); err != nil {
+			// The role, or the whole profile, is already gone
+			if isIamNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf(
+				"Error removing role %s from instance profile: %s", role, err)
+		}
+	}
+
+	return nil
+}
+
+// isIamNotFound returns true if the error is IAM reporting that an entity
+// doesn't exist.
+func isIamNotFound(err error) bool {
+	qerr, ok := err.(*queryError)
+	return ok && qerr.Code == "NoSuchEntity"
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSIamInstanceProfile_basic(t *testing.T) {
+	var profile iamInstanceProfile
+	roleName := os.Getenv("AWS_IAM_ROLE_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if roleName == "" {
+				t.Fatal("AWS_IAM_ROLE_NAME must be set to an existing role")
+			}
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIamInstanceProfileDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccAWSIamInstanceProfileConfig, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIamInstanceProfileExists("aws_iam_instance_profile.foo", &profile),
+					resource.TestCheckResourceAttr(
+						"aws_iam_instance_profile.foo", "path", "/terraform/"),
+					resource.TestCheckResourceAttr(
+						"aws_iam_instance_profile.foo", "roles.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestIamEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"us-east-1":     "https://iam.amazonaws.com",
+		"eu-west-1":     "https://iam.amazonaws.com",
+		"us-gov-west-1": "https://iam.us-gov.amazonaws.com",
+		"cn-north-1":    "https://iam.cn-north-1.amazonaws.com.cn",
+	}
+
+	for region, expected := range cases {
+		actual := iamEndpoint(aws.Region{Name: region})
+		if actual != expected {
+			t.Fatalf("%s: bad: %s", region, actual)
+		}
+	}
+}
+
+func testAccCheckAWSIamInstanceProfileDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).iamconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_iam_instance_profile" {
+			continue
+		}
+
+		profile, err := getIamInstanceProfile(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if profile != nil {
+			return fmt.Errorf("Instance profile still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSIamInstanceProfileExists(n string, profile *iamInstanceProfile) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance profile ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).iamconn
+		p, err := getIamInstanceProfile(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("Instance profile not found")
+		}
+
+		if p.Arn != rs.Primary.Attributes["arn"] {
+			return fmt.Errorf("Bad ARN: %s", rs.Primary.Attributes["arn"])
+		}
+
+		*profile = *p
+		return nil
+	}
+}
+
+const testAccAWSIamInstanceProfileConfig = `
+resource "aws_iam_instance_profile" "foo" {
+  name = "foo-terraform-test"
+  path = "/terraform/"
+  roles = ["%s"]
+}
+`
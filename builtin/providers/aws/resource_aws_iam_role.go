@@ -0,0 +1,389 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceAwsIamRole() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamRoleCreate,
+		Read:   resourceAwsIamRoleRead,
+		Update: resourceAwsIamRoleUpdate,
+		Delete: resourceAwsIamRoleDelete,
+
+		ValidateFunc: resourceAwsIamRoleValidate,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIamRoleName,
+			},
+
+			"name_prefix": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIamRoleNamePrefix,
+			},
+
+			"path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "/",
+				ForceNew: true,
+			},
+
+			"assume_role_policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    jsonStateFunc,
+				ValidateFunc: validateJsonString,
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"unique_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// iamRole is a role as returned by GetRole and CreateRole.
+type iamRole struct {
+	Arn                      string `xml:"Arn"`
+	RoleId                   string `xml:"RoleId"`
+	RoleName                 string `xml:"RoleName"`
+	Path                     string `xml:"Path"`
+	AssumeRolePolicyDocument string `xml:"AssumeRolePolicyDocument"`
+}
+
+func resourceAwsIamRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	var name string
+	if v, ok := d.GetOk("name"); ok {
+		name = v.(string)
+	} else if v, ok := d.GetOk("name_prefix"); ok {
+		name = resource.PrefixedUniqueId(v.(string))
+	} else {
+		name = resource.UniqueId()
+	}
+
+	params := map[string]string{
+		"RoleName":                 name,
+		"Path":                     d.Get("path").(string),
+		"AssumeRolePolicyDocument": d.Get("assume_role_policy").(string),
+	}
+
+	var resp struct {
+		Role iamRole `xml:"CreateRoleResult>Role"`
+	}
+
+	log.Printf("[DEBUG] IAM create role: %#v", params)
+	if err := conn.Request("CreateRole", params, &resp); err != nil {
+		return fmt.Errorf("Error creating IAM role: %s", err)
+	}
+
+	d.SetId(resp.Role.RoleName)
+	log.Printf("[INFO] IAM role ID: %s", d.Id())
+
+	return resourceAwsIamRoleRead(d, meta)
+}
+
+func resourceAwsIamRoleRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	role, err := getIamRole(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		log.Printf("[WARN] IAM role %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	policy, err := decodeIamPolicyDocument(role.AssumeRolePolicyDocument)
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", role.RoleName)
+	d.Set("path", role.Path)
+	d.Set("assume_role_policy", policy)
+	d.Set("arn", role.Arn)
+	d.Set("unique_id", role.RoleId)
+
+	return nil
+}
+
+func resourceAwsIamRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	if d.HasChange("assume_role_policy") {
+		params := map[string]string{
+			"RoleName":       d.Id(),
+			"PolicyDocument": d.Get("assume_role_policy").(string),
+		}
+
+		log.Printf("[DEBUG] IAM update assume role policy: %#v", params)
+		if err := conn.Request("UpdateAssumeRolePolicy", params, nil); err != nil {
+			return fmt.Errorf("Error updating IAM role assume role policy: %s", err)
+		}
+	}
+
+	return resourceAwsIamRoleRead(d, meta)
+}
+
+func resourceAwsIamRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	// A role can't be deleted while it's in an instance profile or has
+	// managed or inline policies, wherever those were set up
+	profiles, err := listIamRoleInstanceProfiles(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		err := removeIamInstanceProfileRoles(conn, profile, []interface{}{d.Id()})
+		if err != nil {
+			return err
+		}
+	}
+
+	policies, err := listIamRoleAttachedPolicies(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	for _, arn := range policies {
+		params := map[string]string{
+			"RoleName":  d.Id(),
+			"PolicyArn": arn,
+		}
+
+		log.Printf("[DEBUG] IAM detach role policy: %#v", params)
+		if err := conn.Request("DetachRolePolicy", params, nil); err != nil && !isIamNotFound(err) {
+			return fmt.Errorf("Error detaching policy %s from IAM role: %s", arn, err)
+		}
+	}
+
+	inline, err := listIamRolePolicies(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	for _, name := range inline {
+		params := map[string]string{
+			"RoleName":   d.Id(),
+			"PolicyName": name,
+		}
+
+		log.Printf("[DEBUG] IAM delete role policy: %#v", params)
+		if err := conn.Request("DeleteRolePolicy", params, nil); err != nil && !isIamNotFound(err) {
+			return fmt.Errorf("Error deleting policy %s of IAM role: %s", name, err)
+		}
+	}
+
+	params := map[string]string{
+		"RoleName": d.Id(),
+	}
+
+	log.Printf("[DEBUG] IAM delete role: %s", d.Id())
+	if err := conn.Request("DeleteRole", params, nil); err != nil {
+		if isIamNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IAM role: %s", err)
+	}
+
+	return nil
+}
+
+func resourceAwsIamRoleValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	if c.IsSet("name") && c.IsSet("name_prefix") {
+		es = append(es, fmt.Errorf("Only one of name or name_prefix can be set"))
+	}
+
+	return
+}
+
+// getIamRole returns the named role, or nil if it doesn't exist.
+func getIamRole(conn *queryConn, name string) (*iamRole, error) {
+	params := map[string]string{
+		"RoleName": name,
+	}
+
+	var resp struct {
+		Role iamRole `xml:"GetRoleResult>Role"`
+	}
+
+	log.Printf("[DEBUG] IAM get role: %s", name)
+	if err := conn.Request("GetRole", params, &resp); err != nil {
+		if isIamNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving IAM role: %s", err)
+	}
+
+	return &resp.Role, nil
+}
+
+// listIamRoleInstanceProfiles returns the names of the instance profiles
+// the role is in, following the pages of the response.
+func listIamRoleInstanceProfiles(conn *queryConn, role string) ([]string, error) {
+	var result []string
+	params := map[string]string{
+		"RoleName": role,
+	}
+	for {
+		var resp struct {
+			Names       []string `xml:"ListInstanceProfilesForRoleResult>InstanceProfiles>member>InstanceProfileName"`
+			IsTruncated bool     `xml:"ListInstanceProfilesForRoleResult>IsTruncated"`
+			Marker      string   `xml:"ListInstanceProfilesForRoleResult>Marker"`
+		}
+
+		log.Printf("[DEBUG] IAM list instance profiles for role: %#v", params)
+		if err := conn.Request("ListInstanceProfilesForRole", params, &resp); err != nil {
+			// A role that's already gone has none
+			if isIamNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("Error listing IAM role instance profiles: %s", err)
+		}
+
+		result = append(result, resp.Names...)
+		if !resp.IsTruncated {
+			return result, nil
+		}
+		params["Marker"] = resp.Marker
+	}
+}
+
+// listIamRoleAttachedPolicies returns the ARNs of the managed policies
+// attached to the role.
+func listIamRoleAttachedPolicies(conn *queryConn, role string) ([]string, error) {
+	var result []string
+	params := map[string]string{
+		"RoleName": role,
+	}
+	for {
+		var resp struct {
+			Arns        []string `xml:"ListAttachedRolePoliciesResult>AttachedPolicies>member>PolicyArn"`
+			IsTruncated bool     `xml:"ListAttachedRolePoliciesResult>IsTruncated"`
+			Marker      string   `xml:"ListAttachedRolePoliciesResult>Marker"`
+		}
+
+		log.Printf("[DEBUG] IAM list attached role policies: %#v", params)
+		if err := conn.Request("ListAttachedRolePolicies", params, &resp); err != nil {
+			// A role that's already gone has none
+			if isIamNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("Error listing IAM role attached policies: %s", err)
+		}
+
+		result = append(result, resp.Arns...)
+		if !resp.IsTruncated {
+			return result, nil
+		}
+		params["Marker"] = resp.Marker
+	}
+}
+
+// listIamRolePolicies returns the names of the role's inline policies.
+func listIamRolePolicies(conn *queryConn, role string) ([]string, error) {
+	var result []string
+	params := map[string]string{
+		"RoleName": role,
+	}
+	for {
+		var resp struct {
+			Names       []string `xml:"ListRolePoliciesResult>PolicyNames>member"`
+			IsTruncated bool     `xml:"ListRolePoliciesResult>IsTruncated"`
+			Marker      string   `xml:"ListRolePoliciesResult>Marker"`
+		}
+
+		log.Printf("[DEBUG] IAM list role policies: %#v", params)
+		if err := conn.Request("ListRolePolicies", params, &resp); err != nil {
+			// A role that's already gone has none
+			if isIamNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("Error listing IAM role policies: %s", err)
+		}
+
+		result = append(result, resp.Names...)
+		if !resp.IsTruncated {
+			return result, nil
+		}
+		params["Marker"] = resp.Marker
+	}
+}
+
+// decodeIamPolicyDocument returns the normalized JSON of a policy
+// document read from IAM, which URL encodes them.
+func decodeIamPolicyDocument(raw string) (string, error) {
+	policy, err := url.QueryUnescape(raw)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding IAM policy document: %s", err)
+	}
+
+	return jsonStateFunc(policy), nil
+}
+
+// Role names can be up to 64 characters long, of letters, digits and
+// "+=,.@_-".
+func validateIamRoleName(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if len(value) < 1 || len(value) > 64 {
+		es = append(es, fmt.Errorf(
+			"%s must be between 1 and 64 characters long, is %d", k, len(value)))
+	}
+	es = append(es, validateIamNameChars(value, k)...)
+
+	return
+}
+
+func validateIamRoleNamePrefix(v interface{}, k string) (ws []string, es []error) {
+	max := 64 - resource.UniqueIdSuffixLength
+	if len(v.(string)) > max {
+		es = append(es, fmt.Errorf(
+			"%s can be at most %d characters long, is %d", k, max, len(v.(string))))
+	}
+	es = append(es, validateIamNameChars(v.(string), k)...)
+
+	return
+}
+
+func validateIamNameChars(value, k string) (es []error) {
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '+' || r == '=' || r == ',' || r == '.' || r == '@' || r == '_' || r == '-':
+		default:
+			es = append(es, fmt.Errorf(
+				"%s can only contain letters, digits and \"+=,.@_-\", got %q", k, value))
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceAwsIamRolePolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIamRolePolicyPut,
+		Read:   resourceAwsIamRolePolicyRead,
+		Update: resourceAwsIamRolePolicyPut,
+		Delete: resourceAwsIamRolePolicyDelete,
+
+		ValidateFunc: resourceAwsIamRolePolicyValidate,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIamRolePolicyName,
+			},
+
+			"name_prefix": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIamRolePolicyNamePrefix,
+			},
+
+			"role": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    jsonStateFunc,
+				ValidateFunc: validateJsonString,
+			},
+		},
+	}
+}
+
+// resourceAwsIamRolePolicyPut creates the policy or replaces its document.
+// PutRolePolicy does both, so the policy is updated in place.
+func resourceAwsIamRolePolicyPut(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	role := d.Get("role").(string)
+	// An existing policy has its name, generated or not, in the state
+	name := d.Get("name").(string)
+	if name == "" {
+		if v, ok := d.GetOk("name_prefix"); ok {
+			name = resource.PrefixedUniqueId(v.(string))
+		} else {
+			name = resource.UniqueId()
+		}
+	}
+
+	params := map[string]string{
+		"RoleName":       role,
+		"PolicyName":     name,
+		"PolicyDocument": d.Get("policy").(string),
+	}
+
+	log.Printf("[DEBUG] IAM put role policy: %#v", params)
+	if err := conn.Request("PutRolePolicy", params, nil); err != nil {
+		return fmt.Errorf("Error putting IAM role policy %s: %s", name, err)
+	}
+
+	d.SetId(role + ":" + name)
+	return resourceAwsIamRolePolicyRead(d, meta)
+}
+
+func resourceAwsIamRolePolicyRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	role, name, err := parseIamRolePolicyId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{
+		"RoleName":   role,
+		"PolicyName": name,
+	}
+
+	var resp struct {
+		PolicyDocument string `xml:"GetRolePolicyResult>PolicyDocument"`
+	}
+
+	log.Printf("[DEBUG] IAM get role policy: %#v", params)
+	if err := conn.Request("GetRolePolicy", params, &resp); err != nil {
+		// The policy, or the whole role, is gone
+		if isIamNotFound(err) {
+			log.Printf("[WARN] IAM role policy %s not found, removing", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving IAM role policy %s: %s", d.Id(), err)
+	}
+
+	policy, err := decodeIamPolicyDocument(resp.PolicyDocument)
+	if err != nil {
+		return err
+	}
+
+	d.Set("role", role)
+	d.Set("name", name)
+	d.Set("policy", policy)
+
+	return nil
+}
+
+func resourceAwsIamRolePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	role, name, err := parseIamRolePolicyId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{
+		"RoleName":   role,
+		"PolicyName": name,
+	}
+
+	log.Printf("[DEBUG] IAM delete role policy: %#v", params)
+	if err := conn.Request("DeleteRolePolicy", params, nil); err != nil {
+		if isIamNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting IAM role policy %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsIamRolePolicyValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	if c.IsSet("name") && c.IsSet("name_prefix") {
+		es = append(es, fmt.Errorf("Only one of name or name_prefix can be set"))
+	}
+
+	return
+}
+
+// parseIamRolePolicyId splits the ID of a role policy, "role:name", into
+// the role's name and the policy's. Neither name can contain a colon.
+func parseIamRolePolicyId(id string) (role, name string, err error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf(
+			"IAM role policy ID must be of the form role:name, got %q", id)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// Policy names can be up to 128 characters long, of the same characters
+// as role names.
+func validateIamRolePolicyName(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if len(value) < 1 || len(value) > 128 {
+		es = append(es, fmt.Errorf(
+			"%s must be between 1 and 128 characters long, is %d", k, len(value)))
+	}
+	es = append(es, validateIamNameChars(value, k)...)
+
+	return
+}
+
+func validateIamRolePolicyNamePrefix(v interface{}, k string) (ws []string, es []error) {
+	max := 128 - resource.UniqueIdSuffixLength
+	if len(v.(string)) > max {
+		es = append(es, fmt.Errorf(
+			"%s can be at most %d characters long, is %d", k, max, len(v.(string))))
+	}
+	es = append(es, validateIamNameChars(v.(string), k)...)
+
+	return
+}
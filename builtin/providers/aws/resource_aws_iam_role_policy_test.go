@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSIamRolePolicy_basic(t *testing.T) {
+	var id string
+
+	testCheckSameId := func(s *terraform.State) error {
+		rs := s.RootModule().Resources["aws_iam_role_policy.foo"]
+		if rs.Primary.ID != id {
+			return fmt.Errorf("policy was recreated: %s, was %s", rs.Primary.ID, id)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIamRolePolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIamRolePolicyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIamRolePolicyExists("aws_iam_role_policy.foo", &id),
+					resource.TestCheckResourceAttr(
+						"aws_iam_role_policy.foo", "policy",
+						`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"*"}],"Version":"2012-10-17"}`),
+				),
+			},
+
+			// Changing the document updates the policy in place, which
+			// would otherwise get a new generated name
+			resource.TestStep{
+				Config: testAccAWSIamRolePolicyConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSameId,
+					resource.TestCheckResourceAttr(
+						"aws_iam_role_policy.foo", "policy",
+						`{"Statement":[{"Action":["s3:GetObject","s3:PutObject"],"Effect":"Allow","Resource":"*"}],"Version":"2012-10-17"}`),
+				),
+			},
+		},
+	})
+}
+
+func TestParseIamRolePolicyId(t *testing.T) {
+	role, name, err := parseIamRolePolicyId("web-role:s3-access")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if role != "web-role" || name != "s3-access" {
+		t.Fatalf("bad: %s, %s", role, name)
+	}
+
+	for _, id := range []string{"", "web-role", "web-role:", ":s3-access", "a:b:c"} {
+		if _, _, err := parseIamRolePolicyId(id); err == nil {
+			t.Fatalf("%q should be invalid", id)
+		}
+	}
+}
+
+func testAccCheckAWSIamRolePolicyDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).iamconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_iam_role_policy" {
+			continue
+		}
+
+		role, name, err := parseIamRolePolicyId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		params := map[string]string{
+			"RoleName":   role,
+			"PolicyName": name,
+		}
+		err = conn.Request("GetRolePolicy", params, nil)
+		if err == nil {
+			return fmt.Errorf("IAM role policy still exists: %s", rs.Primary.ID)
+		}
+		if !isIamNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSIamRolePolicyExists(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No IAM role policy ID is set")
+		}
+
+		role, name, err := parseIamRolePolicyId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).iamconn
+		params := map[string]string{
+			"RoleName":   role,
+			"PolicyName": name,
+		}
+		if err := conn.Request("GetRolePolicy", params, nil); err != nil {
+			return err
+		}
+
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+const testAccAWSIamRolePolicyConfig = `
+resource "aws_iam_role" "foo" {
+  name_prefix = "foo-terraform-test-"
+  assume_role_policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"Service\": \"ec2.amazonaws.com\"}, \"Action\": \"sts:AssumeRole\"}]}"
+}
+
+resource "aws_iam_role_policy" "foo" {
+  name_prefix = "foo-terraform-test-"
+  role = "${aws_iam_role.foo.name}"
+  policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Action\": \"s3:GetObject\", \"Resource\": \"*\"}]}"
+}
+`
+
+const testAccAWSIamRolePolicyConfigUpdate = `
+resource "aws_iam_role" "foo" {
+  name_prefix = "foo-terraform-test-"
+  assume_role_policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"Service\": \"ec2.amazonaws.com\"}, \"Action\": \"sts:AssumeRole\"}]}"
+}
+
+resource "aws_iam_role_policy" "foo" {
+  name_prefix = "foo-terraform-test-"
+  role = "${aws_iam_role.foo.name}"
+  policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Action\": [\"s3:GetObject\", \"s3:PutObject\"], \"Resource\": \"*\"}]}"
+}
+`
@@ -0,0 +1,258 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSIamRole_basic(t *testing.T) {
+	var role iamRole
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSIamRoleDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSIamRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIamRoleExists("aws_iam_role.foo", &role),
+					resource.TestCheckResourceAttr(
+						"aws_iam_role.foo", "path", "/terraform/"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSIamRoleConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSIamRoleExists("aws_iam_role.foo", &role),
+					resource.TestCheckResourceAttr(
+						"aws_iam_role.foo", "assume_role_policy",
+						`{"Statement":[{"Action":"sts:AssumeRole","Effect":"Allow","Principal":{"Service":"ecs.amazonaws.com"}}],"Version":"2012-10-17"}`),
+				),
+			},
+		},
+	})
+}
+
+func TestDecodeIamPolicyDocument(t *testing.T) {
+	raw := "%7B%22Version%22%3A%20%222012-10-17%22%2C%20%22Statement%22%3A%20%5B%5D%7D"
+
+	actual, err := decodeIamPolicyDocument(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := `{"Statement":[],"Version":"2012-10-17"}`
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestValidateIamRoleName(t *testing.T) {
+	valid := []string{
+		"web",
+		"web-role_1+=,.@",
+		strings.Repeat("a", 64),
+	}
+	for _, v := range valid {
+		if _, es := validateIamRoleName(v, "name"); len(es) > 0 {
+			t.Fatalf("%q should be valid: %v", v, es)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"web role",
+		"web/role",
+		strings.Repeat("a", 65),
+	}
+	for _, v := range invalid {
+		if _, es := validateIamRoleName(v, "name"); len(es) == 0 {
+			t.Fatalf("%q should be invalid", v)
+		}
+	}
+
+	max := 64 - resource.UniqueIdSuffixLength
+	if _, es := validateIamRoleNamePrefix(strings.Repeat("a", max), "name_prefix"); len(es) > 0 {
+		t.Fatalf("bad: %v", es)
+	}
+	if _, es := validateIamRoleNamePrefix(strings.Repeat("a", max+1), "name_prefix"); len(es) == 0 {
+		t.Fatal("prefix should be too long")
+	}
+}
+
+func TestListIamRoleInstanceProfiles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "ListInstanceProfilesForRole" || q.Get("RoleName") != "web" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		switch q.Get("Marker") {
+		case "":
+			fmt.Fprint(w, `<ListInstanceProfilesForRoleResponse>
+  <ListInstanceProfilesForRoleResult>
+    <InstanceProfiles>
+      <member><InstanceProfileName>foo</InstanceProfileName></member>
+    </InstanceProfiles>
+    <IsTruncated>true</IsTruncated>
+    <Marker>next</Marker>
+  </ListInstanceProfilesForRoleResult>
+</ListInstanceProfilesForRoleResponse>`)
+		case "next":
+			fmt.Fprint(w, `<ListInstanceProfilesForRoleResponse>
+  <ListInstanceProfilesForRoleResult>
+    <InstanceProfiles>
+      <member><InstanceProfileName>bar</InstanceProfileName></member>
+    </InstanceProfiles>
+    <IsTruncated>false</IsTruncated>
+  </ListInstanceProfilesForRoleResult>
+</ListInstanceProfilesForRoleResponse>`)
+		default:
+			t.Errorf("bad marker: %s", q.Get("Marker"))
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2010-05-08",
+		httpClient: http.DefaultClient,
+	}
+
+	actual, err := listIamRoleInstanceProfiles(conn, "web")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"foo", "bar"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestListIamRolePolicies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "ListRolePolicies" || q.Get("RoleName") != "web" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		switch q.Get("Marker") {
+		case "":
+			fmt.Fprint(w, `<ListRolePoliciesResponse>
+  <ListRolePoliciesResult>
+    <PolicyNames>
+      <member>foo</member>
+    </PolicyNames>
+    <IsTruncated>true</IsTruncated>
+    <Marker>next</Marker>
+  </ListRolePoliciesResult>
+</ListRolePoliciesResponse>`)
+		case "next":
+			fmt.Fprint(w, `<ListRolePoliciesResponse>
+  <ListRolePoliciesResult>
+    <PolicyNames>
+      <member>bar</member>
+    </PolicyNames>
+    <IsTruncated>false</IsTruncated>
+  </ListRolePoliciesResult>
+</ListRolePoliciesResponse>`)
+		default:
+			t.Errorf("bad marker: %s", q.Get("Marker"))
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2010-05-08",
+		httpClient: http.DefaultClient,
+	}
+
+	actual, err := listIamRolePolicies(conn, "web")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"foo", "bar"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func testAccCheckAWSIamRoleDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).iamconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_iam_role" {
+			continue
+		}
+
+		role, err := getIamRole(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if role != nil {
+			return fmt.Errorf("IAM role still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSIamRoleExists(n string, role *iamRole) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No IAM role ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).iamconn
+		r, err := getIamRole(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return fmt.Errorf("IAM role not found")
+		}
+
+		if r.Arn != rs.Primary.Attributes["arn"] {
+			return fmt.Errorf("Bad ARN: %s", rs.Primary.Attributes["arn"])
+		}
+
+		*role = *r
+		return nil
+	}
+}
+
+const testAccAWSIamRoleConfig = `
+resource "aws_iam_role" "foo" {
+  name_prefix = "foo-terraform-test-"
+  path = "/terraform/"
+  assume_role_policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"Service\": \"ec2.amazonaws.com\"}, \"Action\": \"sts:AssumeRole\"}]}"
+}
+`
+
+const testAccAWSIamRoleConfigUpdate = `
+resource "aws_iam_role" "foo" {
+  name_prefix = "foo-terraform-test-"
+  path = "/terraform/"
+  assume_role_policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"Service\": \"ecs.amazonaws.com\"}, \"Action\": \"sts:AssumeRole\"}]}"
+}
+`
@@ -1,7 +1,6 @@
 package aws
 
 import (
-	"bytes"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -23,6 +22,8 @@ func resourceAwsInstance() *schema.Resource {
 		Update: resourceAwsInstanceUpdate,
 		Delete: resourceAwsInstanceDelete,
 
+		ValidateFunc: validateBlockDeviceConfig,
+
 		Schema: map[string]*schema.Schema{
 			"ami": &schema.Schema{
 				Type:     schema.TypeString,
@@ -134,68 +135,13 @@ func resourceAwsInstance() *schema.Resource {
 			},
 			"tags": tagsSchema(),
 
-			"block_device": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"device_name": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-							ForceNew: true,
-						},
-
-						"virtual_name": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							ForceNew: true,
-						},
-
-						"snapshot_id": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
-							ForceNew: true,
-						},
-
-						"volume_type": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
-							ForceNew: true,
-						},
-
-						"volume_size": &schema.Schema{
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
-							ForceNew: true,
-						},
-
-						"delete_on_termination": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-							ForceNew: true,
-						},
-
-						"encrypted": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-							Computed: true,
-							ForceNew: true,
-						},
-					},
-				},
-				Set: resourceAwsInstanceBlockDevicesHash,
-			},
+			"block_device": blockDeviceSchema(),
 		},
 	}
 }
 
 func resourceAwsInstanceCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Figure out user data
 	userData := ""
@@ -241,17 +187,7 @@ func resourceAwsInstanceCreate(d *schema.ResourceData, meta interface{}) error {
 	if v := d.Get("block_device"); v != nil {
 		vs := v.(*schema.Set).List()
 		if len(vs) > 0 {
-			runOpts.BlockDevices = make([]ec2.BlockDeviceMapping, len(vs))
-			for i, v := range vs {
-				bd := v.(map[string]interface{})
-				runOpts.BlockDevices[i].DeviceName = bd["device_name"].(string)
-				runOpts.BlockDevices[i].VirtualName = bd["virtual_name"].(string)
-				runOpts.BlockDevices[i].SnapshotId = bd["snapshot_id"].(string)
-				runOpts.BlockDevices[i].VolumeType = bd["volume_type"].(string)
-				runOpts.BlockDevices[i].VolumeSize = int64(bd["volume_size"].(int))
-				runOpts.BlockDevices[i].DeleteOnTermination = bd["delete_on_termination"].(bool)
-				runOpts.BlockDevices[i].Encrypted = bd["encrypted"].(bool)
-			}
+			runOpts.BlockDevices = expandBlockDevices(vs)
 		}
 	}
 
@@ -308,7 +244,7 @@ func resourceAwsInstanceCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsInstanceRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	resp, err := ec2conn.Instances([]string{d.Id()}, ec2.NewFilter())
 	if err != nil {
@@ -389,27 +325,53 @@ func resourceAwsInstanceRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	bds := make([]map[string]interface{}, len(volResp.Volumes))
+	// AWS reports the default EBS key for encrypted volumes that weren't
+	// given one, so only read the key back for devices configured with it
+	configuredKeys := make(map[string]bool)
+	if v, ok := d.GetOk("block_device"); ok {
+		for _, raw := range v.(*schema.Set).List() {
+			bd := raw.(map[string]interface{})
+			if bd["kms_key_id"].(string) != "" {
+				configuredKeys[bd["device_name"].(string)] = true
+			}
+		}
+	}
+
+	bds := make([]ec2.BlockDeviceMapping, len(volResp.Volumes))
 	for i, vol := range volResp.Volumes {
 		volSize, err := strconv.Atoi(vol.Size)
 		if err != nil {
 			return err
 		}
-		bds[i] = make(map[string]interface{})
-		bds[i]["device_name"] = bdByVolID[vol.VolumeId].DeviceName
-		bds[i]["snapshot_id"] = vol.SnapshotId
-		bds[i]["volume_type"] = vol.VolumeType
-		bds[i]["volume_size"] = volSize
-		bds[i]["delete_on_termination"] = bdByVolID[vol.VolumeId].DeleteOnTermination
-		bds[i]["encrypted"] = vol.Encrypted
+
+		var iops int
+		if vol.IOPS != "" {
+			iops, err = strconv.Atoi(vol.IOPS)
+			if err != nil {
+				return err
+			}
+		}
+
+		bds[i] = ec2.BlockDeviceMapping{
+			DeviceName:          bdByVolID[vol.VolumeId].DeviceName,
+			SnapshotId:          vol.SnapshotId,
+			VolumeType:          vol.VolumeType,
+			VolumeSize:          int64(volSize),
+			IOPS:                int64(iops),
+			DeleteOnTermination: bdByVolID[vol.VolumeId].DeleteOnTermination,
+			Encrypted:           vol.Encrypted,
+		}
+		if configuredKeys[bds[i].DeviceName] {
+			bds[i].KmsKeyId = vol.KmsKeyId
+		}
 	}
-	d.Set("block_device", bds)
+	d.Set("block_device", flattenBlockDevices(bds))
 
 	return nil
 }
 
 func resourceAwsInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	modify := false
 	opts := new(ec2.ModifyInstance)
@@ -440,7 +402,7 @@ func resourceAwsInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsInstanceDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	log.Printf("[INFO] Terminating instance: %s", d.Id())
 	if _, err := ec2conn.TerminateInstances([]string{d.Id()}); err != nil {
@@ -496,12 +458,3 @@ func InstanceStateRefreshFunc(conn *ec2.EC2, instanceID string) resource.StateRe
 		return i, i.State.Name, nil
 	}
 }
-
-func resourceAwsInstanceBlockDevicesHash(v interface{}) int {
-	var buf bytes.Buffer
-	m := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%s-", m["device_name"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["virtual_name"].(string)))
-	buf.WriteString(fmt.Sprintf("%t-", m["delete_on_termination"].(bool)))
-	return hashcode.String(buf.String())
-}
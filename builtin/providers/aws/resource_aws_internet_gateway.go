@@ -3,6 +3,7 @@ package aws
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -28,7 +29,7 @@ func resourceAwsInternetGateway() *schema.Resource {
 }
 
 func resourceAwsInternetGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Create the gateway
 	log.Printf("[DEBUG] Creating internet gateway")
@@ -43,11 +44,19 @@ func resourceAwsInternetGatewayCreate(d *schema.ResourceData, meta interface{})
 	log.Printf("[INFO] InternetGateway ID: %s", d.Id())
 
 	// Attach the new gateway to the correct vpc
-	return resourceAwsInternetGatewayAttach(d, meta)
+	if err := resourceAwsInternetGatewayAttach(d, meta); err != nil {
+		return err
+	}
+
+	if err := setTags(ec2conn, d); err != nil {
+		return err
+	}
+
+	return resourceAwsInternetGatewayRead(d, meta)
 }
 
 func resourceAwsInternetGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	igRaw, _, err := IGStateRefreshFunc(ec2conn, d.Id())()
 	if err != nil {
@@ -85,7 +94,7 @@ func resourceAwsInternetGatewayUpdate(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	if err := setTags(ec2conn, d); err != nil {
 		return err
@@ -93,11 +102,13 @@ func resourceAwsInternetGatewayUpdate(d *schema.ResourceData, meta interface{})
 
 	d.SetPartial("tags")
 
-	return nil
+	// Read the tags back, so that the state only holds the tags that
+	// are left on the gateway
+	return resourceAwsInternetGatewayRead(d, meta)
 }
 
 func resourceAwsInternetGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Detach if it is attached
 	if err := resourceAwsInternetGatewayDetach(d, meta); err != nil {
@@ -106,30 +117,90 @@ func resourceAwsInternetGatewayDelete(d *schema.ResourceData, meta interface{})
 
 	log.Printf("[INFO] Deleting Internet Gateway: %s", d.Id())
 
-	return resource.Retry(5*time.Minute, func() error {
+	return retryDependencyViolation(5*time.Minute, "InvalidInternetGatewayID.NotFound", func() error {
 		_, err := ec2conn.DeleteInternetGateway(d.Id())
-		if err == nil {
-			return nil
-		}
+		return err
+	})
+}
 
-		ec2err, ok := err.(*ec2.Error)
-		if !ok {
-			return err
+// ec2PublicAddress is a public IP mapped to a network interface, as
+// described by DescribeNetworkInterfaces.
+type ec2PublicAddress struct {
+	NetworkInterfaceId string `xml:"networkInterfaceId"`
+	InstanceId         string `xml:"attachment>instanceId"`
+	PublicIp           string `xml:"association>publicIp"`
+	AllocationId       string `xml:"association>allocationId"`
+}
+
+// detachInternetGateway detaches the gateway from the VPC. EC2 refuses
+// with a DependencyViolation while public addresses are mapped in the
+// VPC, which is retried until the timeout as they're often still being
+// released. If they outlast it, the error names them.
+func detachInternetGateway(conn *queryConn, id, vpcID string, timeout time.Duration) error {
+	params := map[string]string{
+		"InternetGatewayId": id,
+		"VpcId":             vpcID,
+	}
+
+	err := resource.Retry(timeout, retryOnAwsCodes([]string{"DependencyViolation"}, func() error {
+		return conn.Request("DetachInternetGateway", params, nil)
+	}))
+	if isAWSErr(err, "DependencyViolation") {
+		return internetGatewayDependencyError(conn, id, vpcID, err)
+	}
+
+	return err
+}
+
+// internetGatewayDependencyError explains a DependencyViolation that
+// outlasted the retries of a detach, naming the public addresses mapped
+// to network interfaces in the VPC.
+func internetGatewayDependencyError(conn *queryConn, id, vpcID string, err error) error {
+	params := map[string]string{
+		"Filter.1.Name":    "vpc-id",
+		"Filter.1.Value.1": vpcID,
+	}
+
+	var resp struct {
+		Addresses []ec2PublicAddress `xml:"networkInterfaceSet>item"`
+	}
+
+	var blocking []string
+	if derr := conn.Request("DescribeNetworkInterfaces", params, &resp); derr != nil {
+		log.Printf("[WARN] Error describing network interfaces in %s: %s", vpcID, derr)
+	}
+	for _, a := range resp.Addresses {
+		if a.PublicIp == "" {
+			continue
 		}
 
-		switch ec2err.Code {
-		case "InvalidInternetGatewayID.NotFound":
-			return nil
-		case "DependencyViolation":
-			return err // retry
+		owner := a.NetworkInterfaceId
+		if a.InstanceId != "" {
+			owner += " of " + a.InstanceId
 		}
+		if a.AllocationId != "" {
+			blocking = append(blocking, fmt.Sprintf(
+				"Elastic IP %s (%s) on %s", a.PublicIp, a.AllocationId, owner))
+		} else {
+			blocking = append(blocking, fmt.Sprintf("%s on %s", a.PublicIp, owner))
+		}
+	}
 
-		return resource.RetryError{err}
-	})
+	if len(blocking) == 0 {
+		return fmt.Errorf(
+			"Error detaching Internet Gateway %s from %s, something still "+
+				"depends on it: %s",
+			id, vpcID, err)
+	}
+
+	return fmt.Errorf(
+		"Error detaching Internet Gateway %s from %s, public addresses in "+
+			"the VPC have to be released first: %s: %s",
+		id, vpcID, strings.Join(blocking, ", "), err)
 }
 
 func resourceAwsInternetGatewayAttach(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	if d.Get("vpc_id").(string) == "" {
 		log.Printf(
@@ -171,7 +242,7 @@ func resourceAwsInternetGatewayAttach(d *schema.ResourceData, meta interface{})
 }
 
 func resourceAwsInternetGatewayDetach(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Get the old VPC ID to detach from
 	vpcID, _ := d.GetChange("vpc_id")
@@ -189,22 +260,15 @@ func resourceAwsInternetGatewayDetach(d *schema.ResourceData, meta interface{})
 		vpcID.(string))
 
 	wait := true
-	_, err := ec2conn.DetachInternetGateway(d.Id(), vpcID.(string))
+	err := detachInternetGateway(
+		meta.(*AWSClient).ec2query, d.Id(), vpcID.(string), 5*time.Minute)
 	if err != nil {
-		ec2err, ok := err.(*ec2.Error)
-		if ok {
-			if ec2err.Code == "InvalidInternetGatewayID.NotFound" {
-				err = nil
-				wait = false
-			} else if ec2err.Code == "Gateway.NotAttached" {
-				err = nil
-				wait = false
-			}
-		}
-
-		if err != nil {
+		if !isAWSErr(err, "InvalidInternetGatewayID.NotFound") &&
+			!isAWSErr(err, "Gateway.NotAttached") {
 			return err
 		}
+
+		wait = false
 	}
 
 	if !wait {
@@ -234,8 +298,7 @@ func IGStateRefreshFunc(ec2conn *ec2.EC2, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		resp, err := ec2conn.DescribeInternetGateways([]string{id}, ec2.NewFilter())
 		if err != nil {
-			ec2err, ok := err.(*ec2.Error)
-			if ok && ec2err.Code == "InvalidInternetGatewayID.NotFound" {
+			if isAWSErr(err, "InvalidInternetGatewayID.NotFound") {
 				resp = nil
 			} else {
 				log.Printf("[ERROR] Error on IGStateRefresh: %s", err)
@@ -265,8 +328,7 @@ func IGAttachStateRefreshFunc(conn *ec2.EC2, id string, expected string) resourc
 
 		resp, err := conn.DescribeInternetGateways([]string{id}, ec2.NewFilter())
 		if err != nil {
-			ec2err, ok := err.(*ec2.Error)
-			if ok && ec2err.Code == "InvalidInternetGatewayID.NotFound" {
+			if isAWSErr(err, "InvalidInternetGatewayID.NotFound") {
 				resp = nil
 			} else {
 				log.Printf("[ERROR] Error on IGStateRefresh: %s", err)
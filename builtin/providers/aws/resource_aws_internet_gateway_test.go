@@ -2,10 +2,15 @@ package aws
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
 	"github.com/mitchellh/goamz/ec2"
 )
 
@@ -112,8 +117,111 @@ func TestAccInternetGateway_tags(t *testing.T) {
 	})
 }
 
+func TestAccInternetGateway_tagsRemove(t *testing.T) {
+	var v ec2.InternetGateway
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckInternetGatewayDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckInternetGatewayConfigTwoTags,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckInternetGatewayExists("aws_internet_gateway.foo", &v),
+					testAccCheckTags(&v.Tags, "foo", "bar"),
+					testAccCheckTags(&v.Tags, "bar", "baz"),
+					resource.TestCheckResourceAttr(
+						"aws_internet_gateway.foo", "tags.#", "2"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccCheckInternetGatewayConfigTagsUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckInternetGatewayExists("aws_internet_gateway.foo", &v),
+					testAccCheckTags(&v.Tags, "foo", ""),
+					testAccCheckTags(&v.Tags, "bar", "baz"),
+					resource.TestCheckResourceAttr(
+						"aws_internet_gateway.foo", "tags.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestDetachInternetGateway_dependencyViolation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("Action") {
+		case "DetachInternetGateway":
+			if q.Get("InternetGatewayId") != "igw-1234" || q.Get("VpcId") != "vpc-1234" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+
+			// The dependency never goes away
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response>
+  <Errors><Error><Code>DependencyViolation</Code><Message>Network vpc-1234 has some mapped public address(es). Please unmap those public address(es) before detaching the gateway.</Message></Error></Errors>
+  <RequestID>abc</RequestID>
+</Response>`)
+		case "DescribeNetworkInterfaces":
+			if q.Get("Filter.1.Name") != "vpc-id" || q.Get("Filter.1.Value.1") != "vpc-1234" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+
+			fmt.Fprint(w, `<DescribeNetworkInterfacesResponse>
+  <networkInterfaceSet>
+    <item>
+      <networkInterfaceId>eni-1</networkInterfaceId>
+      <attachment><instanceId>i-1234</instanceId></attachment>
+      <association><publicIp>54.0.0.1</publicIp><allocationId>eipalloc-1234</allocationId></association>
+    </item>
+    <item>
+      <networkInterfaceId>eni-2</networkInterfaceId>
+      <association><publicIp>54.0.0.2</publicIp></association>
+    </item>
+    <item>
+      <networkInterfaceId>eni-3</networkInterfaceId>
+      <attachment><instanceId>i-5678</instanceId></attachment>
+    </item>
+  </networkInterfaceSet>
+</DescribeNetworkInterfacesResponse>`)
+		default:
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2016-11-15",
+		httpClient: http.DefaultClient,
+	}
+
+	err := detachInternetGateway(conn, "igw-1234", "vpc-1234", time.Second)
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	msg := err.Error()
+	for _, s := range []string{
+		"Elastic IP 54.0.0.1 (eipalloc-1234) on eni-1 of i-1234",
+		"54.0.0.2 on eni-2",
+		"has some mapped public address(es)",
+	} {
+		if !strings.Contains(msg, s) {
+			t.Fatalf("expected %q in: %s", s, msg)
+		}
+	}
+	if strings.Contains(msg, "eni-3") {
+		t.Fatalf("an interface without a public address was named: %s", msg)
+	}
+}
+
 func testAccCheckInternetGatewayDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_internet_gateway" {
@@ -155,7 +263,7 @@ func testAccCheckInternetGatewayExists(n string, ig *ec2.InternetGateway) resour
 			return fmt.Errorf("No ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 		resp, err := conn.DescribeInternetGateways(
 			[]string{rs.Primary.ID}, ec2.NewFilter())
 		if err != nil {
@@ -214,6 +322,20 @@ resource "aws_internet_gateway" "foo" {
 }
 `
 
+const testAccCheckInternetGatewayConfigTwoTags = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_internet_gateway" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+	tags {
+		foo = "bar"
+		bar = "baz"
+	}
+}
+`
+
 const testAccCheckInternetGatewayConfigTagsUpdate = `
 resource "aws_vpc" "foo" {
 	cidr_block = "10.1.0.0/16"
@@ -3,7 +3,10 @@ package aws
 import (
 	"fmt"
 
+	"code.google.com/p/go.crypto/ssh"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 func resourceAwsKeyPair() *schema.Resource {
@@ -13,16 +16,26 @@ func resourceAwsKeyPair() *schema.Resource {
 		Update: nil,
 		Delete: resourceAwsKeyPairDelete,
 
+		ValidateFunc: resourceAwsKeyPairValidate,
+
 		Schema: map[string]*schema.Schema{
 			"key_name": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
+			"key_name_prefix": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateKeyPairNamePrefix,
+			},
 			"public_key": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateOpenSSHPublicKey,
 			},
 			"fingerprint": &schema.Schema{
 				Type:     schema.TypeString,
@@ -33,9 +46,15 @@ func resourceAwsKeyPair() *schema.Resource {
 }
 
 func resourceAwsKeyPairCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	var keyName string
+	if v, ok := d.GetOk("key_name"); ok {
+		keyName = v.(string)
+	} else {
+		keyName = resource.PrefixedUniqueId(d.Get("key_name_prefix").(string))
+	}
 
-	keyName := d.Get("key_name").(string)
 	publicKey := d.Get("public_key").(string)
 	resp, err := ec2conn.ImportKeyPair(keyName, publicKey)
 	if err != nil {
@@ -48,7 +67,7 @@ func resourceAwsKeyPairCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsKeyPairRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	resp, err := ec2conn.KeyPairs([]string{d.Id()}, nil)
 	if err != nil {
@@ -67,8 +86,42 @@ func resourceAwsKeyPairRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsKeyPairDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	_, err := ec2conn.DeleteKeyPair(d.Id())
 	return err
 }
+
+func resourceAwsKeyPairValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	name, prefix := c.IsSet("key_name"), c.IsSet("key_name_prefix")
+	if name && prefix {
+		es = append(es, fmt.Errorf("Only one of key_name or key_name_prefix can be set"))
+	}
+	if !name && !prefix {
+		es = append(es, fmt.Errorf("One of key_name or key_name_prefix must be set"))
+	}
+
+	return
+}
+
+// Key pair names can be up to 255 characters long.
+func validateKeyPairNamePrefix(v interface{}, k string) (ws []string, es []error) {
+	max := 255 - resource.UniqueIdSuffixLength
+	if len(v.(string)) > max {
+		es = append(es, fmt.Errorf(
+			"%s can be at most %d characters long, is %d", k, max, len(v.(string))))
+	}
+
+	return
+}
+
+// validateOpenSSHPublicKey checks that the public key is in the OpenSSH
+// authorized_keys format, such as "ssh-rsa AAAA... comment".
+func validateOpenSSHPublicKey(v interface{}, k string) (ws []string, es []error) {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.(string))); err != nil {
+		es = append(es, fmt.Errorf(
+			"%s: must be a public key in OpenSSH format: %s", k, err))
+	}
+
+	return
+}
@@ -2,8 +2,10 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/ec2"
@@ -28,8 +30,103 @@ func TestAccAWSKeyPair_normal(t *testing.T) {
 	})
 }
 
+func TestAccAWSKeyPair_prefix(t *testing.T) {
+	var conf ec2.KeyPair
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSKeyPairDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSKeyPairPrefixConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSKeyPairExists("aws_key_pair.a_key_pair", &conf),
+					testAccCheckAWSKeyPairNamePrefix("tf-acc-key-pair-", &conf),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateOpenSSHPublicKey(t *testing.T) {
+	cases := []struct {
+		Value string
+		Err   bool
+	}{
+		{
+			Value: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQD3F6tyPEFEzV0LX3X8BsXdMsQz1x2cEikKDEY0aIj41qgxMCP/iteneqXSIFZBp5vizPvaoIR3Um9xK7PGoW8giupGn+EPuxIA4cDM4vzOqOkiMPhz5XK0whEjkVzTo4+S0puvDZuwIsdiW9mxhJc7tgBNL0cYlWSYVkz4G/fslNfRPW5mYAM49f4fhtxPb5ok4Q2Lg9dPKVHO/Bgeu5woMc7RY0p1ej6D4CKFE6lymSDJpW0YHX/wqE9+cfEauh7xZcG0q9t2ta6F6fmX0agvpFyZo8aFbXeUBr7osSCJNgvavWbM/06niWrOvYX2xwWdhXmXSrbX8ZbabVohBK41 phodgson@thoughtworks.com",
+			Err:   false,
+		},
+		{
+			Value: "not-a-key",
+			Err:   true,
+		},
+		{
+			Value: "-----BEGIN PUBLIC KEY-----",
+			Err:   true,
+		},
+	}
+
+	for i, tc := range cases {
+		_, es := validateOpenSSHPublicKey(tc.Value, "public_key")
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestResourceAwsKeyPairValidate(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{"key_name": "deployer"},
+			Err:    false,
+		},
+		{
+			Config: map[string]interface{}{"key_name_prefix": "deployer-"},
+			Err:    false,
+		},
+		{
+			Config: map[string]interface{}{
+				"key_name":        "deployer",
+				"key_name_prefix": "deployer-",
+			},
+			Err: true,
+		},
+		{
+			Config: map[string]interface{}{},
+			Err:    true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		_, es := resourceAwsKeyPairValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestValidateKeyPairNamePrefix(t *testing.T) {
+	max := 255 - resource.UniqueIdSuffixLength
+	if _, es := validateKeyPairNamePrefix(strings.Repeat("a", max), "key_name_prefix"); len(es) > 0 {
+		t.Fatalf("bad: %v", es)
+	}
+	if _, es := validateKeyPairNamePrefix(strings.Repeat("a", max+1), "key_name_prefix"); len(es) == 0 {
+		t.Fatal("prefix should be too long")
+	}
+}
+
 func testAccCheckAWSKeyPairDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_key_pair" {
@@ -68,6 +165,15 @@ func testAccCheckAWSKeyPairFingerprint(expectedFingerprint string, conf *ec2.Key
 	}
 }
 
+func testAccCheckAWSKeyPairNamePrefix(prefix string, conf *ec2.KeyPair) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !strings.HasPrefix(conf.Name, prefix) {
+			return fmt.Errorf("incorrect key name. expected prefix %s, got %s", prefix, conf.Name)
+		}
+		return nil
+	}
+}
+
 func testAccCheckAWSKeyPairExists(n string, res *ec2.KeyPair) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -79,7 +185,7 @@ func testAccCheckAWSKeyPairExists(n string, res *ec2.KeyPair) resource.TestCheck
 			return fmt.Errorf("No KeyPair name is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 		resp, err := conn.KeyPairs(
 			[]string{rs.Primary.ID}, nil)
@@ -102,3 +208,10 @@ resource "aws_key_pair" "a_key_pair" {
 	public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQD3F6tyPEFEzV0LX3X8BsXdMsQz1x2cEikKDEY0aIj41qgxMCP/iteneqXSIFZBp5vizPvaoIR3Um9xK7PGoW8giupGn+EPuxIA4cDM4vzOqOkiMPhz5XK0whEjkVzTo4+S0puvDZuwIsdiW9mxhJc7tgBNL0cYlWSYVkz4G/fslNfRPW5mYAM49f4fhtxPb5ok4Q2Lg9dPKVHO/Bgeu5woMc7RY0p1ej6D4CKFE6lymSDJpW0YHX/wqE9+cfEauh7xZcG0q9t2ta6F6fmX0agvpFyZo8aFbXeUBr7osSCJNgvavWbM/06niWrOvYX2xwWdhXmXSrbX8ZbabVohBK41 phodgson@thoughtworks.com"
 }
 `
+
+const testAccAWSKeyPairPrefixConfig = `
+resource "aws_key_pair" "a_key_pair" {
+	key_name_prefix = "tf-acc-key-pair-"
+	public_key      = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQD3F6tyPEFEzV0LX3X8BsXdMsQz1x2cEikKDEY0aIj41qgxMCP/iteneqXSIFZBp5vizPvaoIR3Um9xK7PGoW8giupGn+EPuxIA4cDM4vzOqOkiMPhz5XK0whEjkVzTo4+S0puvDZuwIsdiW9mxhJc7tgBNL0cYlWSYVkz4G/fslNfRPW5mYAM49f4fhtxPb5ok4Q2Lg9dPKVHO/Bgeu5woMc7RY0p1ej6D4CKFE6lymSDJpW0YHX/wqE9+cfEauh7xZcG0q9t2ta6F6fmX0agvpFyZo8aFbXeUBr7osSCJNgvavWbM/06niWrOvYX2xwWdhXmXSrbX8ZbabVohBK41 phodgson@thoughtworks.com"
+}
+`
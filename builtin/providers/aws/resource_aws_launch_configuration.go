@@ -1,16 +1,23 @@
 package aws
 
 import (
+	"bytes"
+	"compress/gzip"
 	"crypto/sha1"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/autoscaling"
+	"github.com/mitchellh/goamz/ec2"
 )
 
 func resourceAwsLaunchConfiguration() *schema.Resource {
@@ -19,11 +26,24 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 		Read:   resourceAwsLaunchConfigurationRead,
 		Delete: resourceAwsLaunchConfigurationDelete,
 
+		ValidateFunc: resourceAwsLaunchConfigurationValidate,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateLaunchConfigurationName,
+			},
+
+			// Launch configurations can't be changed, so a generated name
+			// lets create_before_destroy make the replacement first
+			"name_prefix": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateLaunchConfigurationNamePrefix,
 			},
 
 			"image_id": &schema.Schema{
@@ -42,6 +62,9 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					return normalizeIamInstanceProfile(v.(string))
+				},
 			},
 
 			"key_name": &schema.Schema{
@@ -66,6 +89,14 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				},
 			},
 
+			// user_data_gzip compresses user_data before it's sent, to fit
+			// more under the size limit. cloud-init decompresses it.
+			"user_data_gzip": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
 			"security_groups": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -80,6 +111,7 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
+				ForceNew: true,
 			},
 
 			"spot_price": &schema.Schema{
@@ -87,35 +119,123 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+
+			"ebs_optimized": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"placement_tenancy": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			// Detailed monitoring, which AutoScaling enables unless told not to
+			"enable_monitoring": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				ForceNew: true,
+			},
+
+			"kernel_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"ramdisk_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"block_device": blockDeviceSchema(),
 		},
 	}
 }
 
 func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
-
-	var createLaunchConfigurationOpts autoscaling.CreateLaunchConfiguration
-	createLaunchConfigurationOpts.Name = d.Get("name").(string)
-	createLaunchConfigurationOpts.IamInstanceProfile = d.Get("iam_instance_profile").(string)
-	createLaunchConfigurationOpts.ImageId = d.Get("image_id").(string)
-	createLaunchConfigurationOpts.InstanceType = d.Get("instance_type").(string)
-	createLaunchConfigurationOpts.KeyName = d.Get("key_name").(string)
-	createLaunchConfigurationOpts.UserData = d.Get("user_data").(string)
-	createLaunchConfigurationOpts.AssociatePublicIpAddress = d.Get("associate_public_ip_address").(bool)
-	createLaunchConfigurationOpts.SpotPrice = d.Get("spot_price").(string)
+	conn := meta.(*AWSClient).autoscalingquery
 
-	if v, ok := d.GetOk("security_groups"); ok {
-		createLaunchConfigurationOpts.SecurityGroups = expandStringList(
-			v.(*schema.Set).List())
+	var name string
+	if v, ok := d.GetOk("name"); ok {
+		name = v.(string)
+	} else if v, ok := d.GetOk("name_prefix"); ok {
+		name = resource.PrefixedUniqueId(v.(string))
+	} else {
+		name = resource.UniqueId()
+	}
+
+	// goamz doesn't know most of the launch configuration settings, so
+	// it's created with the query API
+	params := map[string]string{
+		"LaunchConfigurationName":    name,
+		"ImageId":                    d.Get("image_id").(string),
+		"InstanceType":               d.Get("instance_type").(string),
+		"InstanceMonitoring.Enabled": strconv.FormatBool(d.Get("enable_monitoring").(bool)),
+	}
+	if v := normalizeIamInstanceProfile(d.Get("iam_instance_profile").(string)); v != "" {
+		params["IamInstanceProfile"] = v
+	}
+	for k, attr := range map[string]string{
+		"KeyName":          "key_name",
+		"SpotPrice":        "spot_price",
+		"PlacementTenancy": "placement_tenancy",
+		"KernelId":         "kernel_id",
+		"RamdiskId":        "ramdisk_id",
+	} {
+		if v, ok := d.GetOk(attr); ok {
+			params[k] = v.(string)
+		}
+	}
+	if d.Get("associate_public_ip_address").(bool) {
+		params["AssociatePublicIpAddress"] = "true"
+	}
+	if d.Get("ebs_optimized").(bool) {
+		params["EbsOptimized"] = "true"
 	}
 
-	log.Printf("[DEBUG] autoscaling create launch configuration: %#v", createLaunchConfigurationOpts)
-	_, err := autoscalingconn.CreateLaunchConfiguration(&createLaunchConfigurationOpts)
+	userData, err := launchConfigurationUserData(
+		d.Get("user_data").(string), d.Get("user_data_gzip").(bool))
 	if err != nil {
+		return err
+	}
+	if userData != "" {
+		params["UserData"] = base64.StdEncoding.EncodeToString([]byte(userData))
+	}
+
+	if v, ok := d.GetOk("security_groups"); ok {
+		for i, sg := range v.(*schema.Set).List() {
+			params[fmt.Sprintf("SecurityGroups.member.%d", i+1)] = sg.(string)
+		}
+	}
+
+	if v, ok := d.GetOk("block_device"); ok {
+		bds := expandBlockDevices(v.(*schema.Set).List())
+		for k, v := range autoscalingBlockDeviceParams(bds) {
+			params[k] = v
+		}
+	}
+
+	// AutoScaling's error for an AMI it can't find doesn't say why, which
+	// is usually that the image is in another region
+	if err := validateImage(meta.(*AWSClient), params["ImageId"]); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] autoscaling create launch configuration: %#v", params)
+	if err := conn.Request("CreateLaunchConfiguration", params, nil); err != nil {
 		return fmt.Errorf("Error creating launch configuration: %s", err)
 	}
 
-	d.SetId(d.Get("name").(string))
+	d.SetId(name)
 	log.Printf("[INFO] launch configuration ID: %s", d.Id())
 
 	// We put a Retry here since sometimes eventual consistency bites
@@ -126,56 +246,322 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 }
 
 func resourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
-	describeOpts := autoscaling.DescribeLaunchConfigurations{
-		Names: []string{d.Id()},
+	lc, err := describeLaunchConfiguration(autoscalingconn, d.Id())
+	if err != nil {
+		return err
+	}
+	if lc == nil {
+		d.SetId("")
+		return nil
 	}
 
-	log.Printf("[DEBUG] launch configuration describe configuration: %#v", describeOpts)
-	describConfs, err := autoscalingconn.DescribeLaunchConfigurations(&describeOpts)
+	rootDevice, err := meta.(*AWSClient).imageRootDeviceName(lc.ImageId)
 	if err != nil {
-		return fmt.Errorf("Error retrieving launch configuration: %s", err)
+		return err
 	}
-	if len(describConfs.LaunchConfigurations) == 0 {
+	known := d.Get("block_device").(*schema.Set).List()
+
+	setLaunchConfiguration(d, lc)
+	d.Set("block_device", replaceRootBlockDevice(
+		flattenBlockDevices(lc.BlockDevices), rootDevice, known))
+
+	settings, err := describeLaunchConfigurationSettings(
+		meta.(*AWSClient).autoscalingquery, d.Id())
+	if err != nil {
+		return err
+	}
+	if settings == nil {
 		d.SetId("")
 		return nil
 	}
 
-	// Verify AWS returned our launch configuration
-	if describConfs.LaunchConfigurations[0].Name != d.Id() {
-		return fmt.Errorf(
-			"Unable to find launch configuration: %#v",
-			describConfs.LaunchConfigurations)
+	userData, err := launchConfigurationUserDataHash(
+		settings.UserData, d.Get("user_data_gzip").(bool))
+	if err != nil {
+		return err
 	}
+	d.Set("user_data", userData)
 
-	lc := describConfs.LaunchConfigurations[0]
-
-	d.Set("key_name", lc.KeyName)
-	d.Set("iam_instance_profile", lc.IamInstanceProfile)
-	d.Set("image_id", lc.ImageId)
-	d.Set("instance_type", lc.InstanceType)
-	d.Set("name", lc.Name)
-	d.Set("security_groups", lc.SecurityGroups)
-	d.Set("spot_price", lc.SpotPrice)
+	// AutoScaling leaves out the settings that weren't given, which for
+	// monitoring means it's enabled
+	d.Set("associate_public_ip_address", boolValue(settings.AssociatePublicIpAddress, false))
+	d.Set("ebs_optimized", boolValue(settings.EbsOptimized, false))
+	d.Set("enable_monitoring", boolValue(settings.InstanceMonitoring.Enabled, true))
+	d.Set("placement_tenancy", settings.PlacementTenancy)
+	d.Set("kernel_id", settings.KernelId)
+	d.Set("ramdisk_id", settings.RamdiskId)
 
 	return nil
 }
 
 func resourceAwsLaunchConfigurationDelete(d *schema.ResourceData, meta interface{}) error {
-	autoscalingconn := meta.(*AWSClient).autoscalingconn
+	autoscalingconn := meta.(*AWSClient).autoscalingconn()
 
 	log.Printf("[DEBUG] Launch Configuration destroy: %v", d.Id())
-	_, err := autoscalingconn.DeleteLaunchConfiguration(
-		&autoscaling.DeleteLaunchConfiguration{Name: d.Id()})
-	if err != nil {
-		autoscalingerr, ok := err.(*autoscaling.Error)
-		if ok && autoscalingerr.Code == "InvalidConfiguration.NotFound" {
+
+	// An autoscaling group that was just deleted, or moved to a new
+	// launch configuration, can keep this one in use for a little while
+	return resource.Retry(2*time.Minute, retryOnAwsCodes([]string{"ResourceInUse"}, func() error {
+		_, err := autoscalingconn.DeleteLaunchConfiguration(
+			&autoscaling.DeleteLaunchConfiguration{Name: d.Id()})
+		if isAWSErr(err, "InvalidConfiguration.NotFound") {
 			return nil
 		}
 
 		return err
+	}))
+}
+
+func resourceAwsLaunchConfigurationValidate(c *terraform.ResourceConfig) ([]string, []error) {
+	ws, es := validateBlockDeviceConfig(c)
+
+	if c.IsSet("name") && c.IsSet("name_prefix") {
+		es = append(es, fmt.Errorf("Only one of name or name_prefix can be set"))
 	}
 
-	return nil
+	// The block devices share the instance schema, but AutoScaling has no
+	// KMS key setting, so a key would never be applied and diff forever
+	if n, ok := c.Get("block_device.#"); ok {
+		for i := 0; i < n.(int); i++ {
+			prefix := fmt.Sprintf("block_device.%d.", i)
+			key, _ := c.Get(prefix + "kms_key_id")
+			if s, _ := key.(string); s == "" && !c.IsComputed(prefix+"kms_key_id") {
+				continue
+			}
+
+			name, _ := c.Get(prefix + "device_name")
+			es = append(es, fmt.Errorf(
+				"block_device %v: kms_key_id isn't supported by launch "+
+					"configurations, encrypted volumes use the default key", name))
+		}
+	}
+
+	return ws, es
+}
+
+// Launch configuration names can be up to 255 characters long
+func validateLaunchConfigurationName(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if len(value) < 1 || len(value) > 255 {
+		es = append(es, fmt.Errorf(
+			"%s must be between 1 and 255 characters long, is %d", k, len(value)))
+	}
+	es = append(es, validateLaunchConfigurationNameChars(value, k)...)
+
+	return
+}
+
+// A name prefix has to leave room for the generated suffix within those
+// 255 characters
+func validateLaunchConfigurationNamePrefix(v interface{}, k string) (ws []string, es []error) {
+	max := 255 - resource.UniqueIdSuffixLength
+	if len(v.(string)) > max {
+		es = append(es, fmt.Errorf(
+			"%s can be at most %d characters long, is %d", k, max, len(v.(string))))
+	}
+	es = append(es, validateLaunchConfigurationNameChars(v.(string), k)...)
+
+	return
+}
+
+// validateLaunchConfigurationNameChars checks that the name only has the
+// characters AutoScaling allows in names: anything that can appear in
+// XML, which rules out most control characters.
+func validateLaunchConfigurationNameChars(value, k string) (es []error) {
+	for _, r := range value {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+		case r >= 0x20 && r <= 0xD7FF:
+		case r >= 0xE000 && r <= 0xFFFD:
+		case r >= 0x10000 && r <= 0x10FFFF:
+		default:
+			es = append(es, fmt.Errorf(
+				"%s can't contain the character %q", k, r))
+		}
+	}
+
+	return
+}
+
+// maxUserDataSize is the most user data EC2 accepts, once it's base64
+// encoded.
+const maxUserDataSize = 16384
+
+// launchConfigurationUserData returns the user data to send, gzipped if
+// compress is set. It returns an error if the data is too big for EC2,
+// which AutoScaling would otherwise only report when launching instances.
+func launchConfigurationUserData(userData string, compress bool) (string, error) {
+	if userData != "" && compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write([]byte(userData)); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+
+		userData = buf.String()
+	}
+
+	if size := base64.StdEncoding.EncodedLen(len(userData)); size > maxUserDataSize {
+		hint := ""
+		if !compress {
+			hint = ". Setting user_data_gzip may make it fit"
+		}
+
+		return "", fmt.Errorf(
+			"user_data is %d bytes once base64 encoded, more than the %d "+
+				"bytes EC2 allows%s", size, maxUserDataSize, hint)
+	}
+
+	return userData, nil
+}
+
+// describeLaunchConfiguration returns the named launch configuration, or
+// nil if it doesn't exist.
+func describeLaunchConfiguration(
+	conn *autoscaling.AutoScaling, name string) (*autoscaling.LaunchConfiguration, error) {
+	describeOpts := autoscaling.DescribeLaunchConfigurations{
+		Names: []string{name},
+	}
+
+	log.Printf("[DEBUG] launch configuration describe configuration: %#v", describeOpts)
+	describConfs, err := conn.DescribeLaunchConfigurations(&describeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving launch configuration: %s", err)
+	}
+	if len(describConfs.LaunchConfigurations) == 0 {
+		return nil, nil
+	}
+
+	// Verify AWS returned our launch configuration
+	if describConfs.LaunchConfigurations[0].Name != name {
+		return nil, fmt.Errorf(
+			"Unable to find launch configuration: %#v",
+			describConfs.LaunchConfigurations)
+	}
+
+	return &describConfs.LaunchConfigurations[0], nil
+}
+
+// autoscalingLaunchConfiguration is the part of a launch configuration,
+// as returned by DescribeLaunchConfigurations, that goamz doesn't decode.
+// The settings that weren't given when it was created are left out of the
+// response, so the booleans are pointers.
+type autoscalingLaunchConfiguration struct {
+	LaunchConfigurationName  string `xml:"LaunchConfigurationName"`
+	UserData                 string `xml:"UserData"`
+	AssociatePublicIpAddress *bool  `xml:"AssociatePublicIpAddress"`
+	EbsOptimized             *bool  `xml:"EbsOptimized"`
+	PlacementTenancy         string `xml:"PlacementTenancy"`
+	KernelId                 string `xml:"KernelId"`
+	RamdiskId                string `xml:"RamdiskId"`
+	InstanceMonitoring       struct {
+		Enabled *bool `xml:"Enabled"`
+	} `xml:"InstanceMonitoring"`
+}
+
+// describeLaunchConfigurationSettings returns the settings of the named
+// launch configuration that goamz doesn't decode, or nil if it doesn't
+// exist.
+func describeLaunchConfigurationSettings(
+	conn *queryConn, name string) (*autoscalingLaunchConfiguration, error) {
+	params := map[string]string{
+		"LaunchConfigurationNames.member.1": name,
+	}
+
+	var resp struct {
+		LaunchConfigurations []autoscalingLaunchConfiguration `xml:"DescribeLaunchConfigurationsResult>LaunchConfigurations>member"`
+	}
+	if err := conn.Request("DescribeLaunchConfigurations", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error retrieving launch configuration: %s", err)
+	}
+
+	for _, lc := range resp.LaunchConfigurations {
+		if lc.LaunchConfigurationName == name {
+			return &lc, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// autoscalingBlockDeviceParams returns the CreateLaunchConfiguration
+// parameters for the block devices. AutoScaling has no KMS key setting, an
+// encrypted volume uses the default key.
+func autoscalingBlockDeviceParams(bds []ec2.BlockDeviceMapping) map[string]string {
+	params := make(map[string]string)
+	for i, bd := range bds {
+		prefix := fmt.Sprintf("BlockDeviceMappings.member.%d.", i+1)
+		params[prefix+"DeviceName"] = bd.DeviceName
+
+		// An ephemeral device is only a name, everything else is EBS
+		if bd.VirtualName != "" {
+			params[prefix+"VirtualName"] = bd.VirtualName
+			continue
+		}
+
+		if bd.SnapshotId != "" {
+			params[prefix+"Ebs.SnapshotId"] = bd.SnapshotId
+		}
+		if bd.VolumeType != "" {
+			params[prefix+"Ebs.VolumeType"] = bd.VolumeType
+		}
+		if bd.VolumeSize > 0 {
+			params[prefix+"Ebs.VolumeSize"] = strconv.FormatInt(bd.VolumeSize, 10)
+		}
+		if bd.IOPS > 0 {
+			params[prefix+"Ebs.Iops"] = strconv.FormatInt(bd.IOPS, 10)
+		}
+		params[prefix+"Ebs.DeleteOnTermination"] = strconv.FormatBool(bd.DeleteOnTermination)
+		if bd.Encrypted {
+			params[prefix+"Ebs.Encrypted"] = "true"
+		}
+	}
+
+	return params
+}
+
+// launchConfigurationUserDataHash returns the user data, as read back
+// base64 encoded from AutoScaling, hashed the way user_data is stored in
+// the state. User data that was gzipped is hashed as it was configured.
+func launchConfigurationUserDataHash(encoded string, gzipped bool) (string, error) {
+	if encoded == "" {
+		return "", nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding launch configuration user data: %s", err)
+	}
+
+	if gzipped {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("Error decompressing launch configuration user data: %s", err)
+		}
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return "", fmt.Errorf("Error decompressing launch configuration user data: %s", err)
+		}
+	}
+
+	hash := sha1.Sum(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// setLaunchConfiguration sets the attributes read back from a launch
+// configuration. It is shared with the launch configuration lookup so
+// that both expose the same fields.
+func setLaunchConfiguration(d *schema.ResourceData, lc *autoscaling.LaunchConfiguration) {
+	d.Set("key_name", lc.KeyName)
+	d.Set("iam_instance_profile", normalizeIamInstanceProfile(lc.IamInstanceProfile))
+	d.Set("image_id", lc.ImageId)
+	d.Set("instance_type", lc.InstanceType)
+	d.Set("name", lc.Name)
+	d.Set("security_groups", flattenStringSet(lc.SecurityGroups))
+	d.Set("spot_price", lc.SpotPrice)
+	d.Set("block_device", flattenBlockDevices(lc.BlockDevices))
 }
@@ -1,12 +1,23 @@
 package aws
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/autoscaling"
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/ec2"
 )
 
 func TestAccAWSLaunchConfiguration(t *testing.T) {
@@ -48,8 +59,342 @@ func TestAccAWSLaunchConfiguration(t *testing.T) {
 	})
 }
 
+func TestAccAWSLaunchConfiguration_namePrefix(t *testing.T) {
+	var conf autoscaling.LaunchConfiguration
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLaunchConfigurationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationNamePrefixConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					testAccCheckAWSLaunchConfigurationGeneratedName(&conf, "foobar-terraform-test-"),
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.baz", &conf),
+					testAccCheckAWSLaunchConfigurationGeneratedName(&conf, resource.UniqueIdPrefix),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateLaunchConfigurationNamePrefix(t *testing.T) {
+	max := 255 - resource.UniqueIdSuffixLength
+
+	if _, es := validateLaunchConfigurationNamePrefix(strings.Repeat("a", max), "name_prefix"); len(es) > 0 {
+		t.Fatalf("unexpected errors: %#v", es)
+	}
+	if _, es := validateLaunchConfigurationNamePrefix(strings.Repeat("a", max+1), "name_prefix"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+	if _, es := validateLaunchConfigurationNamePrefix("foo\x00", "name_prefix"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestValidateLaunchConfigurationName(t *testing.T) {
+	cases := []struct {
+		Value string
+		Err   bool
+	}{
+		{"", true},
+		{"a", false},
+		{strings.Repeat("a", 255), false},
+		{strings.Repeat("a", 256), true},
+		{"terraform-lc (web) 2015/01", false},
+		{"foo\tbar", false},
+		{"foo\x00bar", true},
+		{"foo\x1bbar", true},
+	}
+
+	for i, tc := range cases {
+		_, es := validateLaunchConfigurationName(tc.Value, "name")
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestResourceAwsLaunchConfigurationValidate_kmsKeyId(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"encrypted":   true,
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+					},
+					map[string]interface{}{
+						"device_name": "/dev/sdc",
+						"encrypted":   true,
+						"kms_key_id":  "arn:aws:kms:us-east-1:123456789012:key/abcd",
+					},
+				},
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"block_device": []map[string]interface{}{
+					map[string]interface{}{
+						"device_name": "/dev/sdb",
+						"encrypted":   true,
+						"kms_key_id":  "${aws_kms_key.foo.arn}",
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsLaunchConfigurationValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestLaunchConfigurationUserData(t *testing.T) {
+	// 12288 bytes is exactly 16384 once base64 encoded
+	if _, err := launchConfigurationUserData(strings.Repeat("a", 12288), false); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	oversize := strings.Repeat("a", 12289)
+	_, err := launchConfigurationUserData(oversize, false)
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "16388 bytes once base64 encoded") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	v, err := launchConfigurationUserData(oversize, true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r, err := gzip.NewReader(strings.NewReader(v))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(data) != oversize {
+		t.Fatal("user data doesn't round trip")
+	}
+}
+
+func TestAccAWSLaunchConfiguration_blockDevice(t *testing.T) {
+	var conf autoscaling.LaunchConfiguration
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLaunchConfigurationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationBlockDeviceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "block_device.#", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "block_device.172787947.device_name", "/dev/sdb"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "block_device.172787947.volume_type", "io1"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "block_device.172787947.iops", "100"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLaunchConfiguration_allSettings(t *testing.T) {
+	var conf autoscaling.LaunchConfiguration
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLaunchConfigurationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationAllSettingsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "ebs_optimized", "true"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "placement_tenancy", "default"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "enable_monitoring", "false"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "kernel_id", "aki-fc8f11cc"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "spot_price", "0.05"),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "associate_public_ip_address", "true"),
+					testAccCheckAWSLaunchConfigurationNoDiff(
+						"aws_launch_configuration.bar", testAccAWSLaunchConfigurationAllSettings),
+				),
+			},
+
+			// Refreshing again mustn't replace it
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationAllSettingsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					testAccCheckAWSLaunchConfigurationNoDiff(
+						"aws_launch_configuration.bar", testAccAWSLaunchConfigurationAllSettings),
+				),
+			},
+		},
+	})
+}
+
+func TestDescribeLaunchConfigurationSettings(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("LaunchConfigurationNames.member.1") != "foo" {
+			fmt.Fprint(w, `<DescribeLaunchConfigurationsResponse><DescribeLaunchConfigurationsResult>`+
+				`<LaunchConfigurations></LaunchConfigurations></DescribeLaunchConfigurationsResult>`+
+				`</DescribeLaunchConfigurationsResponse>`)
+			return
+		}
+
+		// Only the settings that were given are returned
+		fmt.Fprint(w, `<DescribeLaunchConfigurationsResponse><DescribeLaunchConfigurationsResult>`+
+			`<LaunchConfigurations><member><LaunchConfigurationName>foo</LaunchConfigurationName>`+
+			`<KernelId>aki-12345678</KernelId><RamdiskId></RamdiskId>`+
+			`<EbsOptimized>true</EbsOptimized><InstanceMonitoring><Enabled>false</Enabled></InstanceMonitoring>`+
+			`</member></LaunchConfigurations></DescribeLaunchConfigurationsResult>`+
+			`</DescribeLaunchConfigurationsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2011-01-01",
+		httpClient: http.DefaultClient,
+	}
+
+	lc, err := describeLaunchConfigurationSettings(conn, "foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if lc == nil {
+		t.Fatal("launch configuration not found")
+	}
+
+	if lc.KernelId != "aki-12345678" || lc.RamdiskId != "" || lc.PlacementTenancy != "" {
+		t.Fatalf("bad: %#v", lc)
+	}
+	if !boolValue(lc.EbsOptimized, false) {
+		t.Fatal("ebs_optimized should be true")
+	}
+	if boolValue(lc.InstanceMonitoring.Enabled, true) {
+		t.Fatal("enable_monitoring should be false")
+	}
+	if lc.AssociatePublicIpAddress != nil {
+		t.Fatalf("associate_public_ip_address should be left out: %t", *lc.AssociatePublicIpAddress)
+	}
+
+	if lc, err := describeLaunchConfigurationSettings(conn, "bar"); err != nil || lc != nil {
+		t.Fatalf("expected no launch configuration: %#v, %v", lc, err)
+	}
+}
+
+func TestLaunchConfigurationUserDataHash(t *testing.T) {
+	// sha1 of "foobar-user-data", the way the user_data StateFunc stores it
+	expected := resourceAwsLaunchConfiguration().Schema["user_data"].StateFunc("foobar-user-data")
+
+	hash, err := launchConfigurationUserDataHash(
+		base64.StdEncoding.EncodeToString([]byte("foobar-user-data")), false)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if hash != expected {
+		t.Fatalf("bad: %s, expected %s", hash, expected)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte("foobar-user-data"))
+	w.Close()
+
+	hash, err = launchConfigurationUserDataHash(
+		base64.StdEncoding.EncodeToString(buf.Bytes()), true)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if hash != expected {
+		t.Fatalf("bad gzipped: %s, expected %s", hash, expected)
+	}
+
+	if hash, err := launchConfigurationUserDataHash("", false); err != nil || hash != "" {
+		t.Fatalf("bad empty: %q, %v", hash, err)
+	}
+}
+
+func TestAutoscalingBlockDeviceParams(t *testing.T) {
+	params := autoscalingBlockDeviceParams([]ec2.BlockDeviceMapping{
+		ec2.BlockDeviceMapping{
+			DeviceName:          "/dev/sdb",
+			VolumeType:          "io1",
+			VolumeSize:          10,
+			IOPS:                100,
+			DeleteOnTermination: true,
+			Encrypted:           true,
+		},
+		ec2.BlockDeviceMapping{
+			DeviceName:  "/dev/sdc",
+			VirtualName: "ephemeral0",
+		},
+	})
+
+	expected := map[string]string{
+		"BlockDeviceMappings.member.1.DeviceName":              "/dev/sdb",
+		"BlockDeviceMappings.member.1.Ebs.VolumeType":          "io1",
+		"BlockDeviceMappings.member.1.Ebs.VolumeSize":          "10",
+		"BlockDeviceMappings.member.1.Ebs.Iops":                "100",
+		"BlockDeviceMappings.member.1.Ebs.DeleteOnTermination": "true",
+		"BlockDeviceMappings.member.1.Ebs.Encrypted":           "true",
+		"BlockDeviceMappings.member.2.DeviceName":              "/dev/sdc",
+		"BlockDeviceMappings.member.2.VirtualName":             "ephemeral0",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("bad: %#v", params)
+	}
+}
+
 func testAccCheckAWSLaunchConfigurationDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).autoscalingconn
+	conn := testAccProvider.Meta().(*AWSClient).autoscalingconn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_launch_configuration" {
@@ -110,7 +455,7 @@ func testAccCheckAWSLaunchConfigurationExists(n string, res *autoscaling.LaunchC
 			return fmt.Errorf("No Launch Configuration ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).autoscalingconn
+		conn := testAccProvider.Meta().(*AWSClient).autoscalingconn()
 
 		describeOpts := autoscaling.DescribeLaunchConfigurations{
 			Names: []string{rs.Primary.ID},
@@ -132,6 +477,46 @@ func testAccCheckAWSLaunchConfigurationExists(n string, res *autoscaling.LaunchC
 	}
 }
 
+// testAccCheckAWSLaunchConfigurationNoDiff checks that the launch
+// configuration, as read back into the state, has no diff against the
+// configuration it was created from.
+func testAccCheckAWSLaunchConfigurationNoDiff(n string, raw map[string]interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			return err
+		}
+
+		diff, err := resourceAwsLaunchConfiguration().Diff(
+			rs.Primary, terraform.NewResourceConfig(c))
+		if err != nil {
+			return err
+		}
+		if diff != nil && !diff.Empty() {
+			return fmt.Errorf("Unexpected diff after refresh: %#v", diff.Attributes)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSLaunchConfigurationGeneratedName(
+	conf *autoscaling.LaunchConfiguration, prefix string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !strings.HasPrefix(conf.Name, prefix) ||
+			len(conf.Name) != len(prefix)+resource.UniqueIdSuffixLength {
+			return fmt.Errorf("Bad generated name: %s", conf.Name)
+		}
+
+		return nil
+	}
+}
+
 const testAccAWSLaunchConfigurationConfig = `
 resource "aws_launch_configuration" "bar" {
   name = "foobar-terraform-test"
@@ -152,3 +537,62 @@ resource "aws_launch_configuration" "bar" {
   spot_price = "0.01"
 }
 `
+
+const testAccAWSLaunchConfigurationBlockDeviceConfig = `
+resource "aws_launch_configuration" "bar" {
+  name = "foobar-terraform-test-bd"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+
+  block_device {
+    device_name = "/dev/sdb"
+    volume_type = "io1"
+    volume_size = 10
+    iops = 100
+  }
+}
+`
+
+// testAccAWSLaunchConfigurationAllSettings is the configuration of
+// testAccAWSLaunchConfigurationAllSettingsConfig. The image is PV, and
+// PV-GRUB takes no ramdisk.
+var testAccAWSLaunchConfigurationAllSettings = map[string]interface{}{
+	"name":                        "foobar-terraform-test-all",
+	"image_id":                    "ami-21f78e11",
+	"instance_type":               "m1.large",
+	"user_data":                   "foobar-user-data",
+	"associate_public_ip_address": true,
+	"spot_price":                  "0.05",
+	"ebs_optimized":               true,
+	"placement_tenancy":           "default",
+	"enable_monitoring":           false,
+	"kernel_id":                   "aki-fc8f11cc",
+}
+
+const testAccAWSLaunchConfigurationAllSettingsConfig = `
+resource "aws_launch_configuration" "bar" {
+  name = "foobar-terraform-test-all"
+  image_id = "ami-21f78e11"
+  instance_type = "m1.large"
+  user_data = "foobar-user-data"
+  associate_public_ip_address = true
+  spot_price = "0.05"
+  ebs_optimized = true
+  placement_tenancy = "default"
+  enable_monitoring = false
+  kernel_id = "aki-fc8f11cc"
+}
+`
+
+const testAccAWSLaunchConfigurationNamePrefixConfig = `
+resource "aws_launch_configuration" "bar" {
+  name_prefix = "foobar-terraform-test-"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+}
+
+resource "aws_launch_configuration" "baz" {
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+}
+`
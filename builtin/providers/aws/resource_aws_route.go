@@ -0,0 +1,183 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func resourceAwsRoute() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsRouteCreate,
+		Read:   resourceAwsRouteRead,
+		Update: resourceAwsRouteUpdate,
+		Delete: resourceAwsRouteDelete,
+
+		Schema: map[string]*schema.Schema{
+			"route_table_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"destination_cidr_block": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// A route to an instance is really a route to its network
+			// interface, so AWS fills in whichever of these two wasn't set
+			"instance_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"network_interface_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsRouteCreate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	rtID := d.Get("route_table_id").(string)
+	cidr := d.Get("destination_cidr_block").(string)
+
+	// Nothing is in the state yet, so these are exactly what was configured
+	targets := 0
+	for _, k := range []string{"gateway_id", "instance_id", "network_interface_id"} {
+		if d.Get(k).(string) != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fmt.Errorf(
+			"Exactly one of gateway_id, instance_id or network_interface_id " +
+				"must be set for a route")
+	}
+
+	createOpts := &ec2.CreateRoute{
+		RouteTableId:         rtID,
+		DestinationCidrBlock: cidr,
+		GatewayId:            d.Get("gateway_id").(string),
+		InstanceId:           d.Get("instance_id").(string),
+		NetworkInterfaceId:   d.Get("network_interface_id").(string),
+	}
+
+	log.Printf("[DEBUG] Route create config: %#v", createOpts)
+	if _, err := ec2conn.CreateRoute(createOpts); err != nil {
+		ec2err, ok := err.(*ec2.Error)
+		if !ok || ec2err.Code != "RouteAlreadyExists" {
+			return fmt.Errorf("Error creating route: %s", err)
+		}
+
+		// Adopt the existing route. If it points somewhere else, the
+		// read below puts that in the state and the next plan replaces it.
+		log.Printf(
+			"[INFO] Route to %s already exists in route table %s, reading it",
+			cidr, rtID)
+	}
+
+	d.SetId(routeID(rtID, cidr))
+	log.Printf("[INFO] Route ID: %s", d.Id())
+
+	return resourceAwsRouteRead(d, meta)
+}
+
+func resourceAwsRouteRead(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	rtRaw, _, err := resourceAwsRouteTableStateRefreshFunc(
+		ec2conn, d.Get("route_table_id").(string))()
+	if err != nil {
+		return err
+	}
+	if rtRaw == nil {
+		// The route table is gone, and the route with it
+		d.SetId("")
+		return nil
+	}
+
+	cidr := d.Get("destination_cidr_block").(string)
+	for _, r := range rtRaw.(*ec2.RouteTable).Routes {
+		if r.DestinationCidrBlock != cidr {
+			continue
+		}
+
+		d.Set("gateway_id", r.GatewayId)
+		d.Set("instance_id", r.InstanceId)
+		d.Set("network_interface_id", r.NetworkInterfaceId)
+		return nil
+	}
+
+	// The route was removed outside of Terraform
+	d.SetId("")
+	return nil
+}
+
+func resourceAwsRouteUpdate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	replaceOpts := &ec2.ReplaceRoute{
+		RouteTableId:         d.Get("route_table_id").(string),
+		DestinationCidrBlock: d.Get("destination_cidr_block").(string),
+	}
+
+	// instance_id and network_interface_id keep their computed values
+	// from the state, so only send the target that was actually changed
+	switch {
+	case d.Get("gateway_id").(string) != "":
+		replaceOpts.GatewayId = d.Get("gateway_id").(string)
+	case d.HasChange("network_interface_id"):
+		replaceOpts.NetworkInterfaceId = d.Get("network_interface_id").(string)
+	default:
+		replaceOpts.InstanceId = d.Get("instance_id").(string)
+	}
+
+	log.Printf("[DEBUG] Route replace config: %#v", replaceOpts)
+	if _, err := ec2conn.ReplaceRoute(replaceOpts); err != nil {
+		return fmt.Errorf("Error replacing route: %s", err)
+	}
+
+	return resourceAwsRouteRead(d, meta)
+}
+
+func resourceAwsRouteDelete(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	rtID := d.Get("route_table_id").(string)
+	cidr := d.Get("destination_cidr_block").(string)
+
+	log.Printf("[INFO] Deleting route to %s from route table %s", cidr, rtID)
+	if _, err := ec2conn.DeleteRoute(rtID, cidr); err != nil {
+		ec2err, ok := err.(*ec2.Error)
+		if ok && (ec2err.Code == "InvalidRoute.NotFound" ||
+			ec2err.Code == "InvalidRouteTableID.NotFound") {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting route: %s", err)
+	}
+
+	return nil
+}
+
+// routeID returns the ID for the route to the CIDR block in the route
+// table. AWS doesn't give routes IDs of their own.
+func routeID(routeTableID, cidr string) string {
+	return fmt.Sprintf("r-%s%d", routeTableID, hashcode.String(cidr))
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/mitchellh/goamz/route53"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 func resourceAwsRoute53Record() *schema.Resource {
@@ -18,6 +18,8 @@ func resourceAwsRoute53Record() *schema.Resource {
 		Read:   resourceAwsRoute53RecordRead,
 		Delete: resourceAwsRoute53RecordDelete,
 
+		ValidateFunc: resourceAwsRoute53RecordValidate,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -39,25 +41,50 @@ func resourceAwsRoute53Record() *schema.Resource {
 
 			"ttl": &schema.Schema{
 				Type:     schema.TypeInt,
-				Required: true,
+				Optional: true,
 				ForceNew: true,
 			},
 
 			"records": &schema.Schema{
 				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+				Optional: true,
 				ForceNew: true,
 				Set: func(v interface{}) int {
 					return hashcode.String(v.(string))
 				},
 			},
+
+			"alias": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:      schema.TypeString,
+							Required:  true,
+							StateFunc: normalizeRoute53AliasName,
+						},
+
+						"zone_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"evaluate_target_health": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).route53
+	conn := meta.(*AWSClient).route53query
 
 	// Get the record
 	rec, err := resourceAwsRoute53RecordBuildSet(d)
@@ -68,15 +95,6 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 	// Create the new records. We abuse StateChangeConf for this to
 	// retry for us since Route53 sometimes returns errors about another
 	// operation happening at the same time.
-	req := &route53.ChangeResourceRecordSetsRequest{
-		Comment: "Managed by Terraform",
-		Changes: []route53.Change{
-			route53.Change{
-				Action: "UPSERT",
-				Record: *rec,
-			},
-		},
-	}
 	zone := d.Get("zone_id").(string)
 	log.Printf("[DEBUG] Creating resource records for zone: %s, name: %s",
 		zone, d.Get("name").(string))
@@ -87,7 +105,8 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 		Timeout:    5 * time.Minute,
 		MinTimeout: 1 * time.Second,
 		Refresh: func() (interface{}, string, error) {
-			resp, err := conn.ChangeResourceRecordSets(zone, req)
+			changeId, err := changeRoute53RecordSet(
+				conn, zone, "UPSERT", "Managed by Terraform", rec)
 			if err != nil {
 				if strings.Contains(err.Error(), "PriorRequestNotComplete") {
 					// There is some pending operation, so just retry
@@ -98,7 +117,7 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 				return nil, "failure", err
 			}
 
-			return resp.ChangeInfo, "accepted", nil
+			return changeId, "accepted", nil
 		},
 	}
 
@@ -106,24 +125,13 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
-	changeInfo := respRaw.(route53.ChangeInfo)
+	changeId := respRaw.(string)
 
 	// Generate an ID
 	d.SetId(fmt.Sprintf("%s_%s_%s", zone, d.Get("name").(string), d.Get("type").(string)))
 
 	// Wait until we are done
-	wait = resource.StateChangeConf{
-		Delay:      30 * time.Second,
-		Pending:    []string{"PENDING"},
-		Target:     "INSYNC",
-		Timeout:    10 * time.Minute,
-		MinTimeout: 5 * time.Second,
-		Refresh: func() (result interface{}, state string, err error) {
-			return resourceAwsRoute53Wait(conn, changeInfo.ID)
-		},
-	}
-	_, err = wait.WaitForState()
-	if err != nil {
+	if err := resourceAwsRoute53RecordWaitInSync(meta, changeId); err != nil {
 		return err
 	}
 
@@ -131,45 +139,34 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceAwsRoute53RecordRead(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).route53
+	conn := meta.(*AWSClient).route53query
 
 	zone := d.Get("zone_id").(string)
-	lopts := &route53.ListOpts{
-		Name: d.Get("name").(string),
-		Type: d.Get("type").(string),
-	}
-	resp, err := conn.ListResourceRecordSets(zone, lopts)
+	record, err := getRoute53RecordSet(
+		conn, zone, d.Get("name").(string), d.Get("type").(string))
 	if err != nil {
 		return err
 	}
+	if record == nil {
+		d.SetId("")
+		return nil
+	}
 
-	// Scan for a matching record
-	found := false
-	for _, record := range resp.Records {
-		if route53.FQDN(record.Name) != route53.FQDN(lopts.Name) {
-			continue
-		}
-		if strings.ToUpper(record.Type) != strings.ToUpper(lopts.Type) {
-			continue
-		}
-
-		found = true
-
-		d.Set("records", record.Records)
-		d.Set("ttl", record.TTL)
-
-		break
+	if record.AliasTarget != nil {
+		d.Set("alias", flattenRoute53AliasTarget(record.AliasTarget))
+		return nil
 	}
 
-	if !found {
-		d.SetId("")
+	if record.ResourceRecords != nil {
+		d.Set("records", record.ResourceRecords.Values)
 	}
+	d.Set("ttl", record.TTL)
 
 	return nil
 }
 
 func resourceAwsRoute53RecordDelete(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).route53
+	conn := meta.(*AWSClient).route53query
 
 	// Get the records
 	rec, err := resourceAwsRoute53RecordBuildSet(d)
@@ -177,27 +174,22 @@ func resourceAwsRoute53RecordDelete(d *schema.ResourceData, meta interface{}) er
 		return err
 	}
 
-	// Create the new records
-	req := &route53.ChangeResourceRecordSetsRequest{
-		Comment: "Deleted by Terraform",
-		Changes: []route53.Change{
-			route53.Change{
-				Action: "DELETE",
-				Record: *rec,
-			},
-		},
-	}
+	// Deleting takes the record set exactly as it is, so this is the
+	// inverse of the change we made
 	zone := d.Get("zone_id").(string)
 	log.Printf("[DEBUG] Deleting resource records for zone: %s, name: %s",
 		zone, d.Get("name").(string))
 
+	var changeId string
 	wait := resource.StateChangeConf{
 		Pending:    []string{"rejected"},
 		Target:     "accepted",
 		Timeout:    5 * time.Minute,
 		MinTimeout: 1 * time.Second,
 		Refresh: func() (interface{}, string, error) {
-			_, err := conn.ChangeResourceRecordSets(zone, req)
+			var err error
+			changeId, err = changeRoute53RecordSet(
+				conn, zone, "DELETE", "Deleted by Terraform", rec)
 			if err != nil {
 				if strings.Contains(err.Error(), "PriorRequestNotComplete") {
 					// There is some pending operation, so just retry
@@ -221,22 +213,109 @@ func resourceAwsRoute53RecordDelete(d *schema.ResourceData, meta interface{}) er
 		return err
 	}
 
-	return nil
+	if changeId == "" {
+		return nil
+	}
+
+	return resourceAwsRoute53RecordWaitInSync(meta, changeId)
 }
 
-func resourceAwsRoute53RecordBuildSet(d *schema.ResourceData) (*route53.ResourceRecordSet, error) {
+// resourceAwsRoute53RecordWaitInSync waits for the change to reach every
+// Route53 name server.
+func resourceAwsRoute53RecordWaitInSync(meta interface{}, changeId string) error {
+	r53 := meta.(*AWSClient).route53()
+
+	wait := resource.StateChangeConf{
+		Delay:      30 * time.Second,
+		Pending:    []string{"PENDING"},
+		Target:     "INSYNC",
+		Timeout:    10 * time.Minute,
+		MinTimeout: 5 * time.Second,
+		Refresh: func() (result interface{}, state string, err error) {
+			return resourceAwsRoute53Wait(r53, changeId)
+		},
+	}
+	_, err := wait.WaitForState()
+	return err
+}
+
+func resourceAwsRoute53RecordBuildSet(d *schema.ResourceData) (*route53RecordSet, error) {
+	rec := &route53RecordSet{
+		Name: d.Get("name").(string),
+		Type: d.Get("type").(string),
+	}
+
+	if v, ok := d.GetOk("alias"); ok {
+		alias := v.([]interface{})[0].(map[string]interface{})
+		rec.AliasTarget = &route53AliasTarget{
+			HostedZoneId:         alias["zone_id"].(string),
+			DNSName:              alias["name"].(string),
+			EvaluateTargetHealth: alias["evaluate_target_health"].(bool),
+		}
+		return rec, nil
+	}
+
 	recs := d.Get("records").(*schema.Set).List()
 	records := make([]string, 0, len(recs))
-
 	for _, r := range recs {
 		records = append(records, r.(string))
 	}
 
-	rec := &route53.ResourceRecordSet{
-		Name:    d.Get("name").(string),
-		Type:    d.Get("type").(string),
-		TTL:     d.Get("ttl").(int),
-		Records: records,
-	}
+	rec.TTL = d.Get("ttl").(int)
+	rec.ResourceRecords = &route53ResourceRecords{Values: records}
 	return rec, nil
 }
+
+// flattenRoute53AliasTarget returns the alias block for the target, with
+// the DNS name normalized like alias.name is stored.
+func flattenRoute53AliasTarget(t *route53AliasTarget) []map[string]interface{} {
+	return []map[string]interface{}{
+		map[string]interface{}{
+			"name":                   normalizeRoute53AliasName(t.DNSName),
+			"zone_id":                t.HostedZoneId,
+			"evaluate_target_health": t.EvaluateTargetHealth,
+		},
+	}
+}
+
+// normalizeRoute53AliasName is the StateFunc of alias.name. Route53 gives
+// the DNS name back in lower case and with a trailing dot, where the
+// dns_name of an ELB keeps the case of the ELB's name, so both are stored
+// in lower case and without the dot.
+func normalizeRoute53AliasName(v interface{}) string {
+	return strings.TrimSuffix(strings.ToLower(v.(string)), ".")
+}
+
+// resourceAwsRoute53RecordValidate checks that a record is either an
+// alias or has a TTL and records, and not both.
+func resourceAwsRoute53RecordValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	raw, alias := c.Get("alias")
+	_, ttl := c.Get("ttl")
+	_, records := c.Get("records")
+
+	if alias {
+		count := 0
+		switch l := raw.(type) {
+		case []interface{}:
+			count = len(l)
+		case []map[string]interface{}:
+			count = len(l)
+		}
+		if count > 1 {
+			es = append(es, fmt.Errorf("only one alias can be given"))
+		}
+
+		if ttl || records {
+			es = append(es, fmt.Errorf(
+				"ttl and records can't be given with alias"))
+		}
+		return
+	}
+
+	if !ttl || !records {
+		es = append(es, fmt.Errorf(
+			"ttl and records are required unless alias is given"))
+	}
+
+	return
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/flatmap"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/route53"
@@ -26,8 +28,136 @@ func TestAccRoute53Record(t *testing.T) {
 	})
 }
 
+func TestAccRoute53Record_alias(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRoute53RecordDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccRoute53RecordConfigAlias,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRoute53RecordExists("aws_route53_record.alias"),
+					resource.TestCheckResourceAttr(
+						"aws_route53_record.alias", "alias.0.name", "www.notexample.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAwsRoute53RecordValidate(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{
+				"ttl":     30,
+				"records": []interface{}{"127.0.0.1"},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"alias": []map[string]interface{}{
+					map[string]interface{}{
+						"name":    "foo-123.us-east-1.elb.amazonaws.com",
+						"zone_id": "Z123",
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"ttl": 30,
+				"alias": []map[string]interface{}{
+					map[string]interface{}{
+						"name":    "foo-123.us-east-1.elb.amazonaws.com",
+						"zone_id": "Z123",
+					},
+				},
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"records": []interface{}{"127.0.0.1"},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsRoute53RecordValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestResourceAwsRoute53Record_aliasNameNoDiff(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"zone_id": "Z123",
+		"name":    "www",
+		"type":    "A",
+		"alias": []map[string]interface{}{
+			map[string]interface{}{
+				"name":                   "Web-ELB-1234567890.us-east-1.elb.amazonaws.com",
+				"zone_id":                "Z35SXDOTRQ7X7K",
+				"evaluate_target_health": true,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Route53 reads the ELB's DNS name back in lower case
+	state := &terraform.InstanceState{
+		ID: "Z123_www_A",
+		Attributes: map[string]string{
+			"zone_id": "Z123",
+			"name":    "www",
+			"type":    "A",
+		},
+	}
+	for k, v := range flatmap.Flatten(map[string]interface{}{
+		"alias": flattenRoute53AliasTarget(&route53AliasTarget{
+			HostedZoneId:         "Z35SXDOTRQ7X7K",
+			DNSName:              "web-elb-1234567890.us-east-1.elb.amazonaws.com.",
+			EvaluateTargetHealth: true,
+		}),
+	}) {
+		state.Attributes[k] = v
+	}
+
+	diff, err := resourceAwsRoute53Record().Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil {
+		return
+	}
+
+	for k, attr := range diff.Attributes {
+		if strings.HasPrefix(k, "alias") {
+			t.Fatalf("unexpected %s diff: %#v", k, attr)
+		}
+	}
+}
+
 func testAccCheckRoute53RecordDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).route53
+	conn := testAccProvider.Meta().(*AWSClient).route53()
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_route53_record" {
 			continue
@@ -56,7 +186,7 @@ func testAccCheckRoute53RecordDestroy(s *terraform.State) error {
 
 func testAccCheckRoute53RecordExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		conn := testAccProvider.Meta().(*AWSClient).route53
+		conn := testAccProvider.Meta().(*AWSClient).route53()
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
 			return fmt.Errorf("Not found: %s", n)
@@ -100,3 +230,28 @@ resource "aws_route53_record" "default" {
 	records = ["127.0.0.1", "127.0.0.27"]
 }
 `
+
+const testAccRoute53RecordConfigAlias = `
+resource "aws_route53_zone" "main" {
+	name = "notexample.com"
+}
+
+resource "aws_route53_record" "default" {
+	zone_id = "${aws_route53_zone.main.zone_id}"
+	name = "www.notexample.com"
+	type = "A"
+	ttl = "30"
+	records = ["127.0.0.1"]
+}
+
+resource "aws_route53_record" "alias" {
+	zone_id = "${aws_route53_zone.main.zone_id}"
+	name = "alias.notexample.com"
+	type = "A"
+
+	alias {
+		name = "${aws_route53_record.default.name}"
+		zone_id = "${aws_route53_zone.main.zone_id}"
+	}
+}
+`
@@ -32,7 +32,7 @@ func resourceAwsRoute53Zone() *schema.Resource {
 }
 
 func resourceAwsRoute53ZoneCreate(d *schema.ResourceData, meta interface{}) error {
-	r53 := meta.(*AWSClient).route53
+	r53 := meta.(*AWSClient).route53()
 
 	req := &route53.CreateHostedZoneRequest{
 		Name:    d.Get("name").(string),
@@ -68,7 +68,7 @@ func resourceAwsRoute53ZoneCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceAwsRoute53ZoneRead(d *schema.ResourceData, meta interface{}) error {
-	r53 := meta.(*AWSClient).route53
+	r53 := meta.(*AWSClient).route53()
 
 	_, err := r53.GetHostedZone(d.Id())
 	if err != nil {
@@ -84,7 +84,7 @@ func resourceAwsRoute53ZoneRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceAwsRoute53ZoneDelete(d *schema.ResourceData, meta interface{}) error {
-	r53 := meta.(*AWSClient).route53
+	r53 := meta.(*AWSClient).route53()
 
 	log.Printf("[DEBUG] Deleting Route53 hosted zone: %s (ID: %s)",
 		d.Get("name").(string), d.Id())
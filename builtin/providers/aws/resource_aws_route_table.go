@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/hashcode"
@@ -31,6 +32,7 @@ func resourceAwsRouteTable() *schema.Resource {
 			"route": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"cidr_block": &schema.Schema{
@@ -56,7 +58,7 @@ func resourceAwsRouteTable() *schema.Resource {
 }
 
 func resourceAwsRouteTableCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Create the routing table
 	createOpts := &ec2.CreateRouteTable{
@@ -94,7 +96,7 @@ func resourceAwsRouteTableCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceAwsRouteTableRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	rtRaw, _, err := resourceAwsRouteTableStateRefreshFunc(ec2conn, d.Id())()
 	if err != nil {
@@ -137,7 +139,7 @@ func resourceAwsRouteTableRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsRouteTableUpdate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// Check if the route set as a whole has changed
 	if d.HasChange("route") {
@@ -165,6 +167,12 @@ func resourceAwsRouteTableUpdate(d *schema.ResourceData, meta interface{}) error
 		for _, route := range nrs.List() {
 			m := route.(map[string]interface{})
 
+			err := validateRouteGateway(
+				ec2conn, d.Get("vpc_id").(string), m["gateway_id"].(string))
+			if err != nil {
+				return err
+			}
+
 			opts := ec2.CreateRoute{
 				RouteTableId:         d.Id(),
 				DestinationCidrBlock: m["cidr_block"].(string),
@@ -192,7 +200,7 @@ func resourceAwsRouteTableUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceAwsRouteTableDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	// First request the routing table since we'll have to disassociate
 	// all the subnets first.
@@ -244,6 +252,47 @@ func resourceAwsRouteTableDelete(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
+// validateRouteGateway returns an error if the gateway of a route is an
+// internet gateway that isn't attached to the VPC of the route table. EC2
+// rejects such routes with an error that doesn't say what's wrong.
+func validateRouteGateway(conn *ec2.EC2, vpcId, gatewayId string) error {
+	if !strings.HasPrefix(gatewayId, "igw-") {
+		return nil
+	}
+
+	resp, err := conn.DescribeInternetGateways([]string{gatewayId}, ec2.NewFilter())
+	if err != nil {
+		return fmt.Errorf("Error describing Internet Gateway %s: %s", gatewayId, err)
+	}
+	if len(resp.InternetGateways) == 0 {
+		return fmt.Errorf("Internet Gateway %s not found", gatewayId)
+	}
+
+	var attached []string
+	for _, a := range resp.InternetGateways[0].Attachments {
+		if a.State == "detaching" || a.State == "detached" {
+			continue
+		}
+		if a.VpcId == vpcId {
+			return nil
+		}
+
+		attached = append(attached, a.VpcId)
+	}
+
+	if len(attached) == 0 {
+		return fmt.Errorf(
+			"Internet Gateway %s isn't attached to a VPC, so routes in %s "+
+				"can't use it. Attach it to %s first",
+			gatewayId, vpcId, vpcId)
+	}
+
+	return fmt.Errorf(
+		"Internet Gateway %s is attached to %s, so routes in %s can't use it. "+
+			"Routes can only use a gateway attached to the route table's VPC",
+		gatewayId, strings.Join(attached, ", "), vpcId)
+}
+
 func resourceAwsRouteTableHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
@@ -2,10 +2,14 @@ package aws
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
 	"github.com/mitchellh/goamz/ec2"
 )
 
@@ -150,8 +154,50 @@ func TestAccAWSRouteTable_tags(t *testing.T) {
 }
 
 
+func TestValidateRouteGateway(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") != "DescribeInternetGateways" {
+			t.Errorf("bad action: %s", r.Form.Get("Action"))
+		}
+
+		fmt.Fprint(w, `<DescribeInternetGatewaysResponse>
+  <internetGatewaySet>
+    <item>
+      <internetGatewayId>igw-1234</internetGatewayId>
+      <attachmentSet>
+        <item><vpcId>vpc-1234</vpcId><state>available</state></item>
+      </attachmentSet>
+    </item>
+  </internetGatewaySet>
+</DescribeInternetGatewaysResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := ec2.New(
+		aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		aws.Region{Name: "us-east-1", EC2Endpoint: ts.URL})
+
+	if err := validateRouteGateway(conn, "vpc-1234", "igw-1234"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Other gateways aren't described at all
+	if err := validateRouteGateway(conn, "vpc-1234", "vgw-1234"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err := validateRouteGateway(conn, "vpc-5678", "igw-1234")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "igw-1234 is attached to vpc-1234, so routes in vpc-5678") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
 func testAccCheckRouteTableDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_route_table" {
@@ -193,7 +239,7 @@ func testAccCheckRouteTableExists(n string, v *ec2.RouteTable) resource.TestChec
 			return fmt.Errorf("No ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 		resp, err := conn.DescribeRouteTables(
 			[]string{rs.Primary.ID}, ec2.NewFilter())
 		if err != nil {
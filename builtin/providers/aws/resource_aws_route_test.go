@@ -0,0 +1,135 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestAccAWSRoute_basic(t *testing.T) {
+	var route ec2.Route
+
+	testCheck := func(s *terraform.State) error {
+		if route.DestinationCidrBlock != "10.3.0.0/16" {
+			return fmt.Errorf("bad destination: %#v", route)
+		}
+		if route.GatewayId == "" {
+			return fmt.Errorf("route has no gateway: %#v", route)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSRouteDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSRouteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSRouteExists("aws_route.bar", &route),
+					testCheck,
+				),
+			},
+
+			// The route table doesn't configure any routes of its own, so
+			// applying it again mustn't remove the route
+			resource.TestStep{
+				Config: testAccAWSRouteConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSRouteExists("aws_route.bar", &route),
+					testCheck,
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSRouteExists(n string, res *ec2.Route) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		r, err := testAccFindRoute(
+			rs.Primary.Attributes["route_table_id"],
+			rs.Primary.Attributes["destination_cidr_block"])
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return fmt.Errorf("Route not found")
+		}
+
+		*res = *r
+		return nil
+	}
+}
+
+func testAccCheckAWSRouteDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_route" {
+			continue
+		}
+
+		r, err := testAccFindRoute(
+			rs.Primary.Attributes["route_table_id"],
+			rs.Primary.Attributes["destination_cidr_block"])
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			return fmt.Errorf("Route still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccFindRoute(rtID, cidr string) (*ec2.Route, error) {
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
+
+	rtRaw, _, err := resourceAwsRouteTableStateRefreshFunc(conn, rtID)()
+	if err != nil {
+		return nil, err
+	}
+	if rtRaw == nil {
+		return nil, nil
+	}
+
+	for _, r := range rtRaw.(*ec2.RouteTable).Routes {
+		if r.DestinationCidrBlock == cidr {
+			return &r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+const testAccAWSRouteConfig = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_internet_gateway" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_route_table" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+}
+
+resource "aws_route" "bar" {
+	route_table_id = "${aws_route_table.foo.id}"
+	destination_cidr_block = "10.3.0.0/16"
+	gateway_id = "${aws_internet_gateway.foo.id}"
+}
+`
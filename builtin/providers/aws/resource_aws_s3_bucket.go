@@ -1,10 +1,15 @@
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/goamz/s3"
 )
 
@@ -12,13 +17,17 @@ func resourceAwsS3Bucket() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAwsS3BucketCreate,
 		Read:   resourceAwsS3BucketRead,
+		Update: resourceAwsS3BucketUpdate,
 		Delete: resourceAwsS3BucketDelete,
 
+		ValidateFunc: resourceAwsS3BucketValidate,
+
 		Schema: map[string]*schema.Schema{
 			"bucket": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateS3BucketName,
 			},
 
 			"acl": &schema.Schema{
@@ -27,47 +36,591 @@ func resourceAwsS3Bucket() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+
+			// Buckets are created in the provider's region. Setting this
+			// guards against planning a bucket for the wrong one.
+			"region": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				ForceNew:  true,
+				StateFunc: s3RegionStateFunc,
+			},
+
+			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    jsonStateFunc,
+				ValidateFunc: validateJsonString,
+			},
+
+			// Grants the log delivery group what it needs to write access
+			// logs to the bucket, on top of the grants of the acl
+			"grant_log_delivery": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"versioning": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+
+						"mfa_delete": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"lifecycle_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+
+						"prefix": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"enabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+
+						"expiration_days": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateS3LifecycleDays,
+						},
+
+						"abort_incomplete_multipart_upload_days": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateS3LifecycleDays,
+						},
+					},
+				},
+			},
+
+			// A website either serves an index document or redirects
+			// every request to another host
+			"website": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index_document": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"error_document": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+
+						"redirect_all_requests_to": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"host_name": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"protocol": &schema.Schema{
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validateS3WebsiteRedirectProtocol,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"object_lock_configuration": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						// Object lock can only be enabled when the bucket
+						// is created, and never turned off
+						"object_lock_enabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Required: true,
+							ForceNew: true,
+						},
+
+						"rule": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"mode": &schema.Schema{
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateS3ObjectLockMode,
+									},
+
+									"days": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+
+									"years": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			// Guards against acting on a bucket of the same name in another
+			// account, such as one recreated by someone else after ours was
+			// deleted
+			"expected_bucket_owner": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAwsAccountId,
+			},
+
+			// Deletes every object in the bucket, and every version of
+			// them, so that the bucket can be destroyed
+			"force_destroy": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			// How many DeleteObjects requests force_destroy makes at a
+			// time. Lower it if S3 throttles the deletes.
+			"force_destroy_concurrency": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validateS3DeleteConcurrency,
+			},
+
+			"timeouts": timeoutsSchema("create", "delete"),
 		},
 	}
 }
 
 func resourceAwsS3BucketCreate(d *schema.ResourceData, meta interface{}) error {
-	s3conn := meta.(*AWSClient).s3conn
+	s3conn := meta.(*AWSClient).s3conn()
+
+	timeout, err := resourceTimeout(d, "create", 2*time.Minute)
+	if err != nil {
+		return err
+	}
 
 	// Get the bucket and acl
 	bucket := d.Get("bucket").(string)
 	acl := d.Get("acl").(string)
 
-	log.Printf("[DEBUG] S3 bucket create: %s, ACL: %s", bucket, acl)
-	s3Bucket := s3conn.Bucket(bucket)
-	err := s3Bucket.PutBucket(s3.ACL(acl))
-	if err != nil {
-		return fmt.Errorf("Error creating S3 bucket: %s", err)
+	objectLock := d.Get("object_lock_configuration.0.object_lock_enabled").(bool)
+
+	region := normalizeS3Region(s3conn.Region.Name)
+	if v, ok := d.GetOk("region"); ok && normalizeS3Region(v.(string)) != region {
+		return fmt.Errorf(
+			"S3 bucket %s has region %s, but buckets are created in the "+
+				"provider's region %s", bucket, v, region)
+	}
+
+	// The timeout bounds the whole creation, retries of the create and
+	// the wait for the bucket to be visible alike
+	deadline := time.Now().Add(timeout)
+
+	log.Printf("[DEBUG] S3 bucket create: %s, ACL: %s, object lock: %t", bucket, acl, objectLock)
+	err = s3CreateBucketRetry(s3conn, bucket, acl, objectLock, timeout)
+	if err := s3CreateBucketError(bucket, err); err != nil {
+		return err
 	}
 
 	// Assign the bucket name as the resource ID
 	d.SetId(bucket)
 
-	return nil
+	// Buckets in newly enabled regions can take a while to become
+	// visible, so wait until we can see it before continuing. However
+	// long the create took, leave time to check for it at least once.
+	remaining := deadline.Sub(time.Now())
+	if remaining < 10*time.Second {
+		remaining = 10 * time.Second
+	}
+
+	log.Printf("[DEBUG] Waiting for S3 bucket (%s) to exist", bucket)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{""},
+		Target:     "exists",
+		Refresh:    S3BucketStateRefreshFunc(s3conn, bucket),
+		Timeout:    remaining,
+		MinTimeout: 1 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for S3 bucket (%s) to exist: %s",
+			bucket, err)
+	}
+
+	return resourceAwsS3BucketUpdate(d, meta)
+}
+
+// s3CreateBucketRetry creates the bucket, retrying for up to timeout while
+// S3 reports OperationAborted. That's what creating a bucket right after
+// one of the same name was deleted gives, until the delete has settled.
+// Any other error from S3 is returned straight away.
+func s3CreateBucketRetry(
+	conn *s3Conn, bucket, acl string, objectLock bool, timeout time.Duration) error {
+	return resource.Retry(timeout, retryOnAwsCodes([]string{"OperationAborted"}, func() error {
+		return s3CreateBucket(conn, bucket, acl, objectLock)
+	}))
+}
+
+// s3CreateBucketError returns the error to report for creating a bucket.
+// A bucket we already own is fine, as that's what a previous apply that
+// failed part way through leaves behind, but bucket names are global, so
+// it's an error if someone else owns it.
+func s3CreateBucketError(bucket string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if s3err, ok := err.(*s3.Error); ok {
+		switch s3err.Code {
+		case "BucketAlreadyOwnedByYou":
+			log.Printf("[INFO] S3 bucket %s already exists and is ours, using it", bucket)
+			return nil
+		case "BucketAlreadyExists":
+			return fmt.Errorf(
+				"Error creating S3 bucket: the name %s is taken by another "+
+					"account. Bucket names are shared by all of S3, so pick "+
+					"another", bucket)
+		}
+	}
+
+	return fmt.Errorf("Error creating S3 bucket: %s", err)
 }
 
 func resourceAwsS3BucketRead(d *schema.ResourceData, meta interface{}) error {
-	s3conn := meta.(*AWSClient).s3conn
+	s3conn := meta.(*AWSClient).s3conn()
+	owner := d.Get("expected_bucket_owner").(string)
 
-	bucket := s3conn.Bucket(d.Id())
-	resp, err := bucket.Head("/")
+	err := s3ObjectRequest(
+		s3conn, "HEAD", d.Id(), "", nil, s3ExpectedBucketOwnerHeaders(owner), nil, nil)
 	if err != nil {
-		return err
+		// A HEAD response has no body, so there's only the status code
+		// to tell that the bucket doesn't exist
+		if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode == 404 {
+			log.Printf("[WARN] S3 bucket %s not found, removing", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return s3ExpectedBucketOwnerError(err, d.Id(), owner)
+	}
+
+	// The ID is the bucket name, so a state holding only the ID reads
+	// back in full
+	d.Set("bucket", d.Id())
+
+	region, err := getS3BucketRegion(s3conn, d.Id(), owner)
+	if err != nil {
+		return fmt.Errorf("Error reading S3 bucket location: %s", err)
 	}
-	defer resp.Body.Close()
+	d.Set("region", region)
+
+	// A bucket without an inline policy may have one managed by an
+	// aws_s3_bucket_policy, which is left alone
+	if d.Get("policy").(string) != "" {
+		policy, err := getS3BucketPolicy(s3conn, d.Id(), owner)
+		if err != nil {
+			return fmt.Errorf("Error reading S3 bucket policy: %s", err)
+		}
+		d.Set("policy", jsonStateFunc(policy))
+	}
+
+	// The log-delivery-write ACL gives the grants itself, so there's
+	// nothing to tell apart
+	if d.Get("acl").(string) != "log-delivery-write" {
+		acl, err := getS3BucketACL(s3conn, d.Id(), owner)
+		if err != nil {
+			return fmt.Errorf("Error reading S3 bucket ACL: %s", err)
+		}
+		d.Set("grant_log_delivery", hasS3LogDeliveryGrants(acl))
+	}
+
+	versioning, err := getS3BucketVersioning(s3conn, d.Id(), owner)
+	if err != nil {
+		return fmt.Errorf("Error reading S3 bucket versioning: %s", err)
+	}
+	d.Set("versioning", flattenS3Versioning(versioning))
+
+	rules, err := getS3BucketLifecycle(s3conn, d.Id(), owner)
+	if err != nil {
+		return fmt.Errorf("Error reading S3 bucket lifecycle: %s", err)
+	}
+	d.Set("lifecycle_rule", flattenS3LifecycleRules(rules))
+
+	website, err := getS3BucketWebsite(s3conn, d.Id(), owner)
+	if err != nil {
+		return fmt.Errorf("Error reading S3 bucket website: %s", err)
+	}
+	d.Set("website", flattenS3Website(website))
+
+	objectLock, err := getS3BucketObjectLock(s3conn, d.Id(), owner)
+	if err != nil {
+		return fmt.Errorf("Error reading S3 bucket object lock: %s", err)
+	}
+	d.Set("object_lock_configuration", flattenS3ObjectLock(objectLock))
+
 	return nil
 }
 
+func resourceAwsS3BucketUpdate(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+	owner := d.Get("expected_bucket_owner").(string)
+
+	// The canned ACL is applied when the bucket is created, so the policy
+	// is always put on after it
+	if d.HasChange("policy") {
+		policy := d.Get("policy").(string)
+
+		log.Printf("[DEBUG] S3 bucket %s policy: %s", d.Id(), policy)
+		if err := putS3BucketPolicy(s3conn, d.Id(), owner, policy); err != nil {
+			return fmt.Errorf(
+				"Error putting S3 bucket policy: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	if d.HasChange("lifecycle_rule") {
+		rules := expandS3LifecycleRules(d.Get("lifecycle_rule").([]interface{}))
+
+		log.Printf("[DEBUG] S3 bucket %s lifecycle rules: %#v", d.Id(), rules)
+		if err := putS3BucketLifecycle(s3conn, d.Id(), owner, rules); err != nil {
+			return fmt.Errorf(
+				"Error putting S3 bucket lifecycle: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	if d.HasChange("website") {
+		website := expandS3Website(d.Get("website").([]interface{}))
+
+		log.Printf("[DEBUG] S3 bucket %s website: %#v", d.Id(), website)
+		if err := putS3BucketWebsite(s3conn, d.Id(), owner, website); err != nil {
+			return fmt.Errorf(
+				"Error putting S3 bucket website: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	if d.HasChange("object_lock_configuration.0.rule") &&
+		d.Get("object_lock_configuration.0.object_lock_enabled").(bool) {
+		rule := expandS3ObjectLockRule(
+			d.Get("object_lock_configuration.0.rule").([]interface{}))
+
+		// S3 enables versioning on a bucket created with object lock and
+		// doesn't allow suspending it, but check rather than have the
+		// retention rejected with a less helpful error
+		versioning, err := getS3BucketVersioning(s3conn, d.Id(), owner)
+		if err != nil {
+			return fmt.Errorf(
+				"Error reading S3 bucket versioning: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+		if versioning.Status != "Enabled" {
+			return fmt.Errorf(
+				"S3 bucket %s has object lock but versioning isn't enabled, "+
+					"which object lock requires", d.Id())
+		}
+
+		log.Printf("[DEBUG] S3 bucket %s object lock rule: %#v", d.Id(), rule)
+		if err := putS3BucketObjectLock(s3conn, d.Id(), owner, rule); err != nil {
+			return fmt.Errorf(
+				"Error putting S3 bucket object lock: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	if d.HasChange("grant_log_delivery") {
+		enabled := d.Get("grant_log_delivery").(bool)
+
+		log.Printf("[DEBUG] S3 bucket %s log delivery grants: %t", d.Id(), enabled)
+		if err := updateS3BucketLogDelivery(s3conn, d.Id(), owner, enabled); err != nil {
+			return fmt.Errorf(
+				"Error updating S3 bucket log delivery grants: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	return resourceAwsS3BucketRead(d, meta)
+}
+
 func resourceAwsS3BucketDelete(d *schema.ResourceData, meta interface{}) error {
-	s3conn := meta.(*AWSClient).s3conn
+	s3conn := meta.(*AWSClient).s3conn()
+	owner := d.Get("expected_bucket_owner").(string)
+
+	timeout, err := resourceTimeout(d, "delete", 2*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	// A managed policy may deny the deletes below, even to the bucket's
+	// owner, so remove it first. Buckets without one are left alone.
+	if d.Get("policy").(string) != "" {
+		log.Printf("[DEBUG] S3 bucket %s delete policy", d.Id())
+		err := putS3BucketPolicy(s3conn, d.Id(), owner, "")
+		if s3err, ok := err.(*s3.Error); ok && s3err.Code == "NoSuchBucket" {
+			err = nil
+		}
+		if err != nil {
+			return fmt.Errorf(
+				"Error deleting S3 bucket policy: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d.Get("force_destroy").(bool) {
+		// A bucket that has ever had versioning keeps the old versions
+		// even once it's suspended, so those have to go too
+		versioning, err := getS3BucketVersioning(s3conn, d.Id(), owner)
+		if err != nil {
+			return fmt.Errorf(
+				"Error reading S3 bucket versioning: %s",
+				s3ExpectedBucketOwnerError(err, d.Id(), owner))
+		}
+
+		log.Printf("[DEBUG] S3 bucket %s force destroy, emptying it", d.Id())
+		err = emptyS3Bucket(
+			s3conn, d.Id(), owner, versioning.Status != "",
+			d.Get("force_destroy_concurrency").(int), deadline)
+		if err != nil {
+			return s3ExpectedBucketOwnerError(err, d.Id(), owner)
+		}
+	}
+
+	// However long emptying the bucket took, leave time to try the
+	// delete itself
+	remaining := deadline.Sub(time.Now())
+	if remaining < 10*time.Second {
+		remaining = 10 * time.Second
+	}
 
 	log.Printf("[DEBUG] S3 Delete Bucket: %s", d.Id())
-	bucket := s3conn.Bucket(d.Id())
 
-	return bucket.DelBucket()
+	err = resource.Retry(remaining, func() error {
+		err := s3ObjectRequest(
+			s3conn, "DELETE", d.Id(), "", nil, s3ExpectedBucketOwnerHeaders(owner), nil, nil)
+		if err == nil {
+			return nil
+		}
+
+		s3err, ok := err.(*s3.Error)
+		if ok && s3err.Code == "NoSuchBucket" {
+			// The bucket was already deleted outside of Terraform
+			log.Printf("[WARN] S3 bucket %s already deleted", d.Id())
+			return nil
+		}
+		if ok && s3err.Code == "OperationAborted" {
+			// Another operation on the bucket is still in flight,
+			// retry once it has settled
+			return err
+		}
+
+		return resource.RetryError{err}
+	})
+
+	return s3ExpectedBucketOwnerError(err, d.Id(), owner)
+}
+
+// resourceAwsS3BucketValidate warns about a public canned ACL combined
+// with a policy that denies access. The policy wins, so the bucket isn't
+// as public as the ACL suggests. It also checks the object lock and
+// website configurations, which S3 would otherwise only reject once the
+// bucket has been created.
+func resourceAwsS3BucketValidate(c *terraform.ResourceConfig) ([]string, []error) {
+	var ws []string
+	if !c.IsComputed("acl") && !c.IsComputed("policy") {
+		acl, _ := c.Get("acl")
+		policy, _ := c.Get("policy")
+		aclStr, _ := acl.(string)
+		policyStr, _ := policy.(string)
+
+		ws = s3BucketAclPolicyWarnings(aclStr, policyStr)
+	}
+
+	es := validateS3ObjectLockConfig(c)
+	es = append(es, validateS3WebsiteConfig(c)...)
+
+	return ws, es
+}
+
+func s3BucketAclPolicyWarnings(acl, policy string) []string {
+	if !strings.HasPrefix(acl, "public-") || policy == "" {
+		return nil
+	}
+
+	var doc struct {
+		Statement []struct {
+			Effect string
+		}
+	}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		// Invalid JSON is reported by the policy's own validation
+		return nil
+	}
+
+	for _, st := range doc.Statement {
+		if st.Effect == "Deny" {
+			return []string{fmt.Sprintf(
+				"acl is %q but policy denies some access. The policy is "+
+					"evaluated as well as the ACL, so objects may not be as "+
+					"public as the ACL suggests.", acl)}
+		}
+	}
+
+	return nil
+}
+
+// S3BucketStateRefreshFunc returns a resource.StateRefreshFunc that is used
+// to watch for an S3 bucket to exist.
+func S3BucketStateRefreshFunc(conn *s3Conn, name string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		bucket := conn.Bucket(name)
+		resp, err := bucket.Head("/")
+		if err != nil {
+			if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode == 404 {
+				// Not visible yet
+				return nil, "", nil
+			}
+
+			log.Printf("[ERROR] Error on S3BucketStateRefresh: %s", err)
+			return nil, "", err
+		}
+		resp.Body.Close()
+
+		return bucket, "exists", nil
+	}
 }
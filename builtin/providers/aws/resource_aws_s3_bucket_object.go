@@ -0,0 +1,491 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func resourceAwsS3BucketObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsS3BucketObjectPut,
+		Read:   resourceAwsS3BucketObjectRead,
+		Update: resourceAwsS3BucketObjectUpdate,
+		Delete: resourceAwsS3BucketObjectDelete,
+
+		ValidateFunc: resourceAwsS3BucketObjectValidate,
+
+		Schema: map[string]*schema.Schema{
+			"bucket": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"key": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"source": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"content": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// Binary content can't be written as a string, so it can be
+			// given base64 encoded instead
+			"content_base64": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBase64,
+			},
+
+			"content_type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"metadata": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+
+			"acl": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "private",
+				ValidateFunc: validateS3CannedACL,
+			},
+
+			"server_side_encryption": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateS3ServerSideEncryption,
+			},
+
+			// S3 uses the account's default key for aws:kms when none is
+			// given, and reports that key back
+			"kms_key_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"storage_class": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "STANDARD",
+				ValidateFunc: validateS3StorageClass,
+			},
+
+			// Sources bigger than s3MultipartThreshold are uploaded in
+			// parts of this many bytes, this many at a time
+			"multipart_part_size": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      16 * 1024 * 1024,
+				ValidateFunc: validateS3MultipartPartSize,
+			},
+
+			"multipart_concurrency": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validateS3MultipartConcurrency,
+			},
+
+			// Tags are kept apart from the content, so changing them
+			// doesn't upload the object again
+			"tags": tagsSchema(),
+
+			"etag": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsS3BucketObjectPut(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+
+	headers := map[string][]string{}
+	if v := d.Get("content_type").(string); v != "" {
+		headers["Content-Type"] = []string{v}
+	}
+	for k, v := range d.Get("metadata").(map[string]interface{}) {
+		headers["X-Amz-Meta-"+k] = []string{v.(string)}
+	}
+	if v := d.Get("server_side_encryption").(string); v != "" {
+		headers["X-Amz-Server-Side-Encryption"] = []string{v}
+
+		if key := d.Get("kms_key_id").(string); key != "" && v == "aws:kms" {
+			headers["X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"] = []string{key}
+		}
+	}
+	if v := d.Get("storage_class").(string); v != "" {
+		headers["X-Amz-Storage-Class"] = []string{v}
+	}
+
+	// Large files are uploaded in parts, which is both faster and more
+	// reliable than a single request
+	if source := d.Get("source").(string); source != "" {
+		f, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("Error reading S3 object source %s: %s", source, err)
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("Error reading S3 object source %s: %s", source, err)
+		}
+
+		if fi.Size() > s3MultipartThreshold {
+			if d.Get("content").(string) != "" || d.Get("content_base64").(string) != "" {
+				return fmt.Errorf("Only one of source, content or content_base64 can be set")
+			}
+
+			headers["X-Amz-Acl"] = []string{d.Get("acl").(string)}
+			if _, ok := headers["Content-Type"]; !ok {
+				headers["Content-Type"] = []string{"binary/octet-stream"}
+			}
+
+			log.Printf(
+				"[DEBUG] S3 multipart put object %s in bucket %s (%d bytes)",
+				key, bucket, fi.Size())
+			etag, err := putS3ObjectMultipart(
+				s3conn, bucket, key, f, fi.Size(),
+				int64(d.Get("multipart_part_size").(int)),
+				d.Get("multipart_concurrency").(int),
+				headers)
+			if err != nil {
+				return fmt.Errorf("Error putting object %s in S3 bucket %s: %s", key, bucket, err)
+			}
+
+			d.SetId(key)
+			resourceAwsS3BucketObjectSetUploaded(d, etag, fi.Size())
+
+			if err := resourceAwsS3BucketObjectPutTags(d, s3conn, false); err != nil {
+				return err
+			}
+
+			return resourceAwsS3BucketObjectRead(d, meta)
+		}
+	}
+
+	body, err := resourceAwsS3BucketObjectBody(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] S3 put object %s in bucket %s (%d bytes)", key, bucket, len(body))
+	err = s3conn.Bucket(bucket).PutReaderHeader(
+		key, bytes.NewReader(body), int64(len(body)), headers, s3.ACL(d.Get("acl").(string)))
+	if err != nil {
+		return fmt.Errorf("Error putting object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+
+	d.SetId(key)
+
+	sum := md5.Sum(body)
+	resourceAwsS3BucketObjectSetUploaded(d, hex.EncodeToString(sum[:]), int64(len(body)))
+
+	if err := resourceAwsS3BucketObjectPutTags(d, s3conn, false); err != nil {
+		return err
+	}
+
+	return resourceAwsS3BucketObjectRead(d, meta)
+}
+
+func resourceAwsS3BucketObjectRead(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+
+	resp, err := s3conn.Bucket(bucket).Head(key)
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); ok && s3err.StatusCode == 404 {
+			// The object was deleted outside of Terraform
+			log.Printf("[WARN] S3 object %s in bucket %s not found, removing", key, bucket)
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error reading object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+	resp.Body.Close()
+
+	etag := strings.Trim(resp.Header.Get("ETag"), `"`)
+	size, _ := strconv.Atoi(resp.Header.Get("Content-Length"))
+
+	// If the object no longer matches what was uploaded, someone else
+	// has overwritten it. Forgetting the content in the state makes the
+	// next plan upload it again.
+	if old := d.Get("etag").(string); old != "" && (old != etag || d.Get("size").(int) != size) {
+		log.Printf(
+			"[INFO] S3 object %s in bucket %s changed outside of Terraform "+
+				"(etag %s, expected %s)", key, bucket, etag, old)
+		d.Set("content", "")
+		d.Set("content_base64", "")
+		d.Set("source", "")
+	}
+
+	d.Set("etag", etag)
+	d.Set("size", size)
+	d.Set("content_type", resp.Header.Get("Content-Type"))
+	d.Set("metadata", flattenS3ObjectMetadata(resp.Header))
+	d.Set("server_side_encryption", resp.Header.Get("X-Amz-Server-Side-Encryption"))
+	d.Set("kms_key_id", resp.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+
+	// Objects in the standard storage class don't report it
+	storageClass := resp.Header.Get("X-Amz-Storage-Class")
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+	d.Set("storage_class", storageClass)
+
+	tags, err := getS3ObjectTags(s3conn, bucket, key)
+	if err != nil {
+		return fmt.Errorf("Error reading tags of object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+	d.Set("tags", tags)
+
+	policy, err := getS3ObjectACL(s3conn, bucket, key)
+	if err != nil {
+		return fmt.Errorf("Error reading ACL of object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+	if _, ok := cannedACLToGrants(d.Get("acl").(string)); !ok {
+		// The grants of the bucket-owner ACLs depend on who owns the
+		// bucket, so there is nothing to compare them to
+		log.Printf(
+			"[DEBUG] Not reading back ACL %s of S3 object %s in bucket %s",
+			d.Get("acl"), key, bucket)
+	} else if acl := grantsToCannedACL(policy); acl != s3CustomACL {
+		d.Set("acl", acl)
+	} else {
+		// Leave the configured ACL alone rather than guess
+		log.Printf(
+			"[WARN] ACL of S3 object %s in bucket %s doesn't match a canned ACL",
+			key, bucket)
+	}
+
+	return nil
+}
+
+func resourceAwsS3BucketObjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Anything but the ACL and tags changes the object itself, which has
+	// to be put again. That applies the ACL and tags too.
+	for _, k := range []string{
+		"source", "content", "content_base64", "content_type", "metadata",
+		"server_side_encryption", "kms_key_id", "storage_class",
+	} {
+		if d.HasChange(k) {
+			return resourceAwsS3BucketObjectPut(d, meta)
+		}
+	}
+
+	if d.HasChange("acl") {
+		s3conn := meta.(*AWSClient).s3conn()
+		bucket := d.Get("bucket").(string)
+		key := d.Get("key").(string)
+		acl := d.Get("acl").(string)
+
+		log.Printf("[DEBUG] S3 put object ACL %s on %s in bucket %s", acl, key, bucket)
+		if err := putS3ObjectACL(s3conn, bucket, key, acl); err != nil {
+			return fmt.Errorf("Error putting ACL of object %s in S3 bucket %s: %s", key, bucket, err)
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := resourceAwsS3BucketObjectPutTags(d, meta.(*AWSClient).s3conn(), true); err != nil {
+			return err
+		}
+	}
+
+	return resourceAwsS3BucketObjectRead(d, meta)
+}
+
+func resourceAwsS3BucketObjectDelete(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+
+	log.Printf("[DEBUG] S3 delete object %s in bucket %s", key, bucket)
+	if err := s3conn.Bucket(bucket).Del(key); err != nil {
+		return fmt.Errorf("Error deleting object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+
+	return nil
+}
+
+func resourceAwsS3BucketObjectValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	set := 0
+	for _, k := range []string{"source", "content", "content_base64"} {
+		if c.IsSet(k) {
+			set++
+		}
+	}
+	if set != 1 {
+		es = append(es, fmt.Errorf(
+			"exactly one of source, content or content_base64 must be set"))
+	}
+
+	if c.IsComputed("kms_key_id") || c.IsComputed("server_side_encryption") {
+		return
+	}
+
+	key, _ := c.Get("kms_key_id")
+	sse, _ := c.Get("server_side_encryption")
+	if k, _ := key.(string); k != "" && sse != "aws:kms" {
+		es = append(es, fmt.Errorf(
+			"kms_key_id can only be set with server_side_encryption \"aws:kms\""))
+	}
+
+	return
+}
+
+func validateS3ServerSideEncryption(v interface{}, k string) (ws []string, es []error) {
+	if v.(string) != "AES256" && v.(string) != "aws:kms" {
+		es = append(es, fmt.Errorf(
+			"%s must be AES256 or aws:kms, got %q", k, v))
+	}
+
+	return
+}
+
+func validateS3StorageClass(v interface{}, k string) (ws []string, es []error) {
+	for _, c := range []string{"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA"} {
+		if v.(string) == c {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of STANDARD, REDUCED_REDUNDANCY or STANDARD_IA, got %q", k, v))
+	return
+}
+
+// resourceAwsS3BucketObjectSetUploaded records what was uploaded, so that
+// the read after it can tell it apart from the object being changed
+// outside of Terraform. The ETag of an object encrypted with KMS isn't
+// derived from its content, so for those the read records whatever S3
+// returns.
+func resourceAwsS3BucketObjectSetUploaded(d *schema.ResourceData, etag string, size int64) {
+	if d.Get("server_side_encryption").(string) == "aws:kms" {
+		etag = ""
+	}
+
+	d.Set("etag", etag)
+	d.Set("size", int(size))
+}
+
+// resourceAwsS3BucketObjectPutTags puts the configured tags on the object.
+// A freshly uploaded object has no tags, so unless removing is set an
+// empty map is left alone rather than removed.
+func resourceAwsS3BucketObjectPutTags(d *schema.ResourceData, conn *s3Conn, removing bool) error {
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+	tags := d.Get("tags").(map[string]interface{})
+	if len(tags) == 0 && !removing {
+		return nil
+	}
+
+	log.Printf("[DEBUG] S3 put object tags on %s in bucket %s: %#v", key, bucket, tags)
+	if err := putS3ObjectTags(conn, bucket, key, tags); err != nil {
+		return fmt.Errorf("Error putting tags of object %s in S3 bucket %s: %s", key, bucket, err)
+	}
+
+	return nil
+}
+
+func validateBase64(v interface{}, k string) (ws []string, es []error) {
+	if _, err := base64.StdEncoding.DecodeString(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be base64 encoded: %s", k, err))
+	}
+
+	return
+}
+
+// resourceAwsS3BucketObjectBody returns the configured object content,
+// read from the source file or decoded from content_base64 if one of
+// those is given instead.
+func resourceAwsS3BucketObjectBody(d *schema.ResourceData) ([]byte, error) {
+	source := d.Get("source").(string)
+	content := d.Get("content").(string)
+	contentBase64 := d.Get("content_base64").(string)
+
+	set := 0
+	for _, v := range []string{source, content, contentBase64} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("Only one of source, content or content_base64 can be set")
+	}
+
+	if contentBase64 != "" {
+		body, err := base64.StdEncoding.DecodeString(contentBase64)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding S3 object content_base64: %s", err)
+		}
+
+		return body, nil
+	}
+
+	if source != "" {
+		body, err := ioutil.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading S3 object source %s: %s", source, err)
+		}
+
+		return body, nil
+	}
+
+	return []byte(content), nil
+}
+
+// Takes the response headers of an S3 object and returns its user
+// metadata, keyed by the name given when the object was put
+func flattenS3ObjectMetadata(h http.Header) map[string]string {
+	metadata := make(map[string]string)
+	for k, v := range h {
+		if len(v) == 0 || !strings.HasPrefix(k, "X-Amz-Meta-") {
+			continue
+		}
+
+		metadata[strings.ToLower(k[len("X-Amz-Meta-"):])] = v[0]
+	}
+
+	return metadata
+}
@@ -0,0 +1,481 @@
+package aws
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func TestAccAWSS3BucketObject_basic(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketObjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "content_type", "text/plain"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "metadata.owner", "terraform"),
+					// md5 of "some content"
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "etag", "9893532233caff98cd083a116b013c0b"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "size", "12"),
+
+					// Overwrite the object behind Terraform's back, so the
+					// next step has to upload it again
+					testAccAWSS3BucketObjectOverwrite("aws_s3_bucket_object.object"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "content", "some content"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "etag", "9893532233caff98cd083a116b013c0b"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3BucketObject_acl(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketObjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigACL("private"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "acl", "private"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigACL("public-read"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "acl", "public-read"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3BucketObject_encryption(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketObjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigEncryption,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "server_side_encryption", "AES256"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "storage_class", "REDUCED_REDUNDANCY"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3BucketObject_contentBase64(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketObjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigContentBase64,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					// md5 of the bytes 00 01 02 ff
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "etag", "0416dab819887333af831f8c765ac2ae"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "size", "4"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3BucketObject_tags(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+	var lastModified string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketObjectDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigTags(`Name = "foo"
+		CostCenter = "1234"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketObjectExists("aws_s3_bucket_object.object"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "tags.#", "2"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "tags.Name", "foo"),
+					testAccCheckAWSS3BucketObjectNotUploaded(
+						"aws_s3_bucket_object.object", &lastModified),
+				),
+			},
+
+			// Changing only the tags mustn't upload the object again
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigTags(`Name = "bar"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "tags.#", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "tags.Name", "bar"),
+					testAccCheckAWSS3BucketObjectNotUploaded(
+						"aws_s3_bucket_object.object", &lastModified),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketObjectConfigTags(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_object.object", "tags.#", "0"),
+					testAccCheckAWSS3BucketObjectNotUploaded(
+						"aws_s3_bucket_object.object", &lastModified),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAwsS3BucketObjectValidate(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{
+				"content":                "foo",
+				"server_side_encryption": "AES256",
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"content":                "foo",
+				"server_side_encryption": "aws:kms",
+				"kms_key_id":             "arn:aws:kms:us-east-1:123456789012:key/abcd",
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"content":                "foo",
+				"server_side_encryption": "AES256",
+				"kms_key_id":             "arn:aws:kms:us-east-1:123456789012:key/abcd",
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"content":    "foo",
+				"kms_key_id": "arn:aws:kms:us-east-1:123456789012:key/abcd",
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"content_base64": "AAEC/w==",
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"source": "foo.txt",
+			},
+			Err: false,
+		},
+
+		// Exactly one of the three
+		{
+			Config: map[string]interface{}{},
+			Err:    true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"content":        "foo",
+				"content_base64": "AAEC/w==",
+			},
+			Err: true,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"source":  "foo.txt",
+				"content": "foo",
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := resourceAwsS3BucketObjectValidate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
+
+func TestValidateS3StorageClass(t *testing.T) {
+	for _, v := range []string{"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA"} {
+		if _, es := validateS3StorageClass(v, "storage_class"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "standard", "GLACIER"} {
+		if _, es := validateS3StorageClass(v, "storage_class"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+
+	if _, es := validateS3ServerSideEncryption("aes256", "server_side_encryption"); len(es) == 0 {
+		t.Fatal("expected an error for aes256")
+	}
+
+	if _, es := validateBase64("AAEC/w==", "content_base64"); len(es) > 0 {
+		t.Fatalf("unexpected errors: %#v", es)
+	}
+	if _, es := validateBase64("not base64!", "content_base64"); len(es) == 0 {
+		t.Fatal("expected an error for invalid base64")
+	}
+}
+
+func TestFlattenS3ObjectMetadata(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "text/plain")
+	h.Set("X-Amz-Meta-Owner", "terraform")
+	h.Set("x-amz-meta-build-id", "42")
+
+	expected := map[string]string{
+		"owner":    "terraform",
+		"build-id": "42",
+	}
+
+	metadata := flattenS3ObjectMetadata(h)
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Fatalf("bad: %#v", metadata)
+	}
+}
+
+func testAccCheckAWSS3BucketObjectDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).s3conn()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_s3_bucket_object" {
+			continue
+		}
+
+		bucket := conn.Bucket(rs.Primary.Attributes["bucket"])
+		resp, err := bucket.Head(rs.Primary.Attributes["key"])
+		if err == nil {
+			resp.Body.Close()
+			return fmt.Errorf("S3 object still exists: %s", rs.Primary.ID)
+		}
+
+		s3err, ok := err.(*s3.Error)
+		if !ok || s3err.StatusCode != 404 {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSS3BucketObjectExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No S3 object ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		bucket := conn.Bucket(rs.Primary.Attributes["bucket"])
+		resp, err := bucket.Head(rs.Primary.Attributes["key"])
+		if err != nil {
+			return fmt.Errorf("S3 object error: %s", err)
+		}
+		resp.Body.Close()
+
+		return nil
+	}
+}
+
+// testAccCheckAWSS3BucketObjectNotUploaded records when the object was
+// last modified the first time it's called, and checks that it hasn't
+// been uploaded again since every time after that.
+func testAccCheckAWSS3BucketObjectNotUploaded(n string, lastModified *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		bucket := conn.Bucket(rs.Primary.Attributes["bucket"])
+		resp, err := bucket.Head(rs.Primary.Attributes["key"])
+		if err != nil {
+			return fmt.Errorf("S3 object error: %s", err)
+		}
+		resp.Body.Close()
+
+		v := resp.Header.Get("Last-Modified")
+		if *lastModified == "" {
+			*lastModified = v
+		} else if v != *lastModified {
+			return fmt.Errorf("S3 object was uploaded again at %s", v)
+		}
+
+		return nil
+	}
+}
+
+func testAccAWSS3BucketObjectOverwrite(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		bucket := conn.Bucket(rs.Primary.Attributes["bucket"])
+		return bucket.Put(
+			rs.Primary.Attributes["key"], []byte("changed"), "text/plain", s3.Private)
+	}
+}
+
+var testAccAWSS3BucketObjectConfig = fmt.Sprintf(`
+resource "aws_s3_bucket" "object_bucket" {
+	bucket = "tf-object-test-bucket-%d"
+}
+
+resource "aws_s3_bucket_object" "object" {
+	bucket = "${aws_s3_bucket.object_bucket.bucket}"
+	key = "test-key"
+	content = "some content"
+	content_type = "text/plain"
+
+	metadata {
+		owner = "terraform"
+	}
+}
+`, rand.Int())
+
+var testAccAWSS3BucketObjectACLBucket = fmt.Sprintf("tf-object-test-bucket-%d", rand.Int())
+
+func testAccAWSS3BucketObjectConfigACL(acl string) string {
+	return fmt.Sprintf(`
+resource "aws_s3_bucket" "object_bucket" {
+	bucket = "%s"
+}
+
+resource "aws_s3_bucket_object" "object" {
+	bucket = "${aws_s3_bucket.object_bucket.bucket}"
+	key = "test-key"
+	content = "some content"
+	acl = "%s"
+}
+`, testAccAWSS3BucketObjectACLBucket, acl)
+}
+
+var testAccAWSS3BucketObjectConfigContentBase64 = fmt.Sprintf(`
+resource "aws_s3_bucket" "object_bucket" {
+	bucket = "tf-object-test-bucket-%d"
+}
+
+resource "aws_s3_bucket_object" "object" {
+	bucket = "${aws_s3_bucket.object_bucket.bucket}"
+	key = "test-key"
+	content_base64 = "AAEC/w=="
+}
+`, rand.Int())
+
+var testAccAWSS3BucketObjectConfigEncryption = fmt.Sprintf(`
+resource "aws_s3_bucket" "object_bucket" {
+	bucket = "tf-object-test-bucket-%d"
+}
+
+resource "aws_s3_bucket_object" "object" {
+	bucket = "${aws_s3_bucket.object_bucket.bucket}"
+	key = "test-key"
+	content = "some content"
+	server_side_encryption = "AES256"
+	storage_class = "REDUCED_REDUNDANCY"
+}
+`, rand.Int())
+
+var testAccAWSS3BucketObjectTagsBucket = fmt.Sprintf("tf-object-test-bucket-%d", rand.Int())
+
+func testAccAWSS3BucketObjectConfigTags(tags string) string {
+	return fmt.Sprintf(`
+resource "aws_s3_bucket" "object_bucket" {
+	bucket = "%s"
+}
+
+resource "aws_s3_bucket_object" "object" {
+	bucket = "${aws_s3_bucket.object_bucket.bucket}"
+	key = "test-key"
+	content = "some content"
+
+	tags {
+		%s
+	}
+}
+`, testAccAWSS3BucketObjectTagsBucket, tags)
+}
@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsS3BucketPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsS3BucketPolicyPut,
+		Read:   resourceAwsS3BucketPolicyRead,
+		Update: resourceAwsS3BucketPolicyPut,
+		Delete: resourceAwsS3BucketPolicyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"bucket": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    jsonStateFunc,
+				ValidateFunc: validateJsonString,
+			},
+		},
+	}
+}
+
+// resourceAwsS3BucketPolicyPut creates the policy or replaces its document,
+// which PutBucketPolicy does alike.
+func resourceAwsS3BucketPolicyPut(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	bucket := d.Get("bucket").(string)
+	policy := d.Get("policy").(string)
+
+	log.Printf("[DEBUG] S3 bucket %s policy: %s", bucket, policy)
+	if err := putS3BucketPolicy(s3conn, bucket, "", policy); err != nil {
+		return fmt.Errorf("Error putting S3 bucket %s policy: %s", bucket, err)
+	}
+
+	d.SetId(bucket)
+	return resourceAwsS3BucketPolicyRead(d, meta)
+}
+
+func resourceAwsS3BucketPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	policy, err := getS3BucketPolicy(s3conn, d.Id(), "")
+	if err != nil {
+		if isAWSErr(err, "NoSuchBucket") {
+			log.Printf("[WARN] S3 bucket %s not found, removing policy", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error reading S3 bucket %s policy: %s", d.Id(), err)
+	}
+
+	if policy == "" {
+		// Deleted outside of Terraform
+		log.Printf("[WARN] S3 bucket %s policy not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("bucket", d.Id())
+	d.Set("policy", jsonStateFunc(policy))
+
+	return nil
+}
+
+func resourceAwsS3BucketPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	s3conn := meta.(*AWSClient).s3conn()
+
+	log.Printf("[DEBUG] S3 bucket %s delete policy", d.Id())
+	if err := putS3BucketPolicy(s3conn, d.Id(), "", ""); err != nil {
+		if isAWSErr(err, "NoSuchBucket") {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting S3 bucket %s policy: %s", d.Id(), err)
+	}
+
+	return nil
+}
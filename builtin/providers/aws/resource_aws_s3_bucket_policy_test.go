@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSS3BucketPolicy_basic(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	// The configured policies list their keys out of order, so the state
+	// should hold the normalized documents
+	expected := fmt.Sprintf(
+		`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow",`+
+			`"Principal":"*","Resource":"arn:aws:s3:::%s/*","Sid":"PublicRead"}],`+
+			`"Version":"2012-10-17"}`,
+		testAccAWSS3BucketPolicyResourceName)
+	expectedUpdate := fmt.Sprintf(
+		`{"Statement":[{"Action":["s3:GetObject","s3:GetObjectVersion"],"Effect":"Allow",`+
+			`"Principal":"*","Resource":"arn:aws:s3:::%s/*","Sid":"PublicRead"}],`+
+			`"Version":"2012-10-17"}`,
+		testAccAWSS3BucketPolicyResourceName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketPolicyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketPolicyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketPolicy("aws_s3_bucket_policy.bar", expected),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_policy.bar", "policy", expected),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketPolicyConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketPolicy("aws_s3_bucket_policy.bar", expectedUpdate),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket_policy.bar", "policy", expectedUpdate),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSS3BucketPolicyDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).s3conn()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_s3_bucket_policy" {
+			continue
+		}
+
+		policy, err := getS3BucketPolicy(conn, rs.Primary.ID, "")
+		if err != nil {
+			if isAWSErr(err, "NoSuchBucket") {
+				continue
+			}
+			return err
+		}
+		if policy != "" {
+			return fmt.Errorf("S3 bucket %s policy still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSS3BucketPolicy(n, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No S3 bucket policy ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		policy, err := getS3BucketPolicy(conn, rs.Primary.ID, "")
+		if err != nil {
+			return err
+		}
+		if jsonStateFunc(policy) != expected {
+			return fmt.Errorf("bad policy: %s", policy)
+		}
+
+		return nil
+	}
+}
+
+var testAccAWSS3BucketPolicyResourceName = fmt.Sprintf("tf-test-bucket-%d", rand.Int())
+
+var testAccAWSS3BucketPolicyConfig = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%[1]s"
+}
+
+resource "aws_s3_bucket_policy" "bar" {
+	bucket = "${aws_s3_bucket.bar.id}"
+	policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Sid\": \"PublicRead\", \"Effect\": \"Allow\", \"Principal\": \"*\", \"Action\": \"s3:GetObject\", \"Resource\": \"arn:aws:s3:::%[1]s/*\"}]}"
+}
+`, testAccAWSS3BucketPolicyResourceName)
+
+var testAccAWSS3BucketPolicyConfigUpdate = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%[1]s"
+}
+
+resource "aws_s3_bucket_policy" "bar" {
+	bucket = "${aws_s3_bucket.bar.id}"
+	policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Sid\": \"PublicRead\", \"Effect\": \"Allow\", \"Principal\": \"*\", \"Action\": [\"s3:GetObject\", \"s3:GetObjectVersion\"], \"Resource\": \"arn:aws:s3:::%[1]s/*\"}]}"
+}
+`, testAccAWSS3BucketPolicyResourceName)
@@ -3,11 +3,16 @@ package aws
 import (
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
 )
 
 func TestAccAWSS3Bucket(t *testing.T) {
@@ -22,14 +27,312 @@ func TestAccAWSS3Bucket(t *testing.T) {
 				Config: testAccAWSS3BucketConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "versioning.0.enabled", "false"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "versioning.0.mfa_delete", "false"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccAWSS3Bucket_Lifecycle(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithLifecycle,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "lifecycle_rule.#", "1"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "lifecycle_rule.0.id", "uploads"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "lifecycle_rule.0.expiration_days", "30"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "lifecycle_rule.0.abort_incomplete_multipart_upload_days", "7"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithoutLifecycle,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "lifecycle_rule.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3Bucket_Website(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithWebsite,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.0.index_document", "index.html"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.0.error_document", "error.html"),
+				),
+			},
+
+			// Switching to redirecting replaces the whole configuration
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithWebsiteRedirect,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.0.index_document", ""),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.0.redirect_all_requests_to.0.host_name", "www.example.com"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.0.redirect_all_requests_to.0.protocol", "https"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithoutWebsite,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "website.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3Bucket_Policy(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	// The configured policy lists its keys out of order, so the state
+	// should hold the normalized document
+	expected := fmt.Sprintf(
+		`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow",`+
+			`"Principal":"*","Resource":"arn:aws:s3:::%s/*","Sid":"PublicRead"}],`+
+			`"Version":"2012-10-17"}`,
+		testAccAWSS3BucketPolicyName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "policy", expected),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithoutPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "policy", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3Bucket_grantLogDelivery(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithLogDelivery,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					testAccCheckAWSS3BucketLogDelivery("aws_s3_bucket.bar", true),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "grant_log_delivery", "true"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithoutLogDelivery,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketLogDelivery("aws_s3_bucket.bar", false),
+					resource.TestCheckResourceAttr(
+						"aws_s3_bucket.bar", "grant_log_delivery", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3Bucket_denyDeletePolicy(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	// The policy denies deleting the bucket, so destroying it only works
+	// if the policy is removed first
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfigWithDenyDeletePolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSS3Bucket_disappears(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSS3BucketDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSS3BucketConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSS3BucketExists("aws_s3_bucket.bar"),
+					// Destroying the bucket afterwards must not fail
+					testAccCheckAWSS3BucketDisappears("aws_s3_bucket.bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestS3BucketAclPolicyWarnings(t *testing.T) {
+	deny := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny",` +
+		`"Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/*"}]}`
+	allow := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+		`"Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/*"}]}`
+
+	cases := []struct {
+		ACL, Policy string
+		Warn        bool
+	}{
+		{"public-read", deny, true},
+		{"public-read-write", deny, true},
+		{"public-read", allow, false},
+		{"private", deny, false},
+		{"", deny, false},
+		{"public-read", "", false},
+		{"public-read", "{", false},
+	}
+
+	for i, tc := range cases {
+		ws := s3BucketAclPolicyWarnings(tc.ACL, tc.Policy)
+		if (len(ws) > 0) != tc.Warn {
+			t.Fatalf("%d: bad warnings: %#v", i, ws)
+		}
+	}
+}
+
+func TestS3CreateBucketError(t *testing.T) {
+	if err := s3CreateBucketError("foo", nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Left behind by an earlier apply
+	err := s3CreateBucketError("foo", &s3.Error{StatusCode: 409, Code: "BucketAlreadyOwnedByYou"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = s3CreateBucketError("foo", &s3.Error{StatusCode: 409, Code: "BucketAlreadyExists"})
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "the name foo is taken by another account") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	err = s3CreateBucketError("foo", &s3.Error{StatusCode: 403, Code: "AccessDenied"})
+	if err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestS3CreateBucketRetry(t *testing.T) {
+	creates := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creates++
+
+		// The delete of a bucket of the same name settles after a couple
+		// of attempts
+		if creates < 3 {
+			w.WriteHeader(409)
+			fmt.Fprint(w, `<Error><Code>OperationAborted</Code>`+
+				`<Message>A conflicting conditional operation is currently in progress</Message></Error>`)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	if err := s3CreateBucketRetry(conn, "foo", "private", false, time.Minute); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if creates != 3 {
+		t.Fatalf("expected 3 creates, got %d", creates)
+	}
+}
+
+func TestS3CreateBucketRetry_otherError(t *testing.T) {
+	creates := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creates++
+		w.WriteHeader(409)
+		fmt.Fprint(w, `<Error><Code>BucketAlreadyExists</Code><Message>Taken</Message></Error>`)
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	err := s3CreateBucketRetry(conn, "foo", "private", false, time.Minute)
+	if !isAWSErr(err, "BucketAlreadyExists") {
+		t.Fatalf("bad: %#v", err)
+	}
+	if creates != 1 {
+		t.Fatalf("expected a single create, got %d", creates)
+	}
+}
+
 func testAccCheckAWSS3BucketDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).s3conn
+	conn := testAccProvider.Meta().(*AWSClient).s3conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_s3_bucket" {
@@ -46,6 +349,35 @@ func testAccCheckAWSS3BucketDestroy(s *terraform.State) error {
 	return nil
 }
 
+// testAccCheckAWSS3BucketLogDelivery checks that the bucket's ACL has the
+// log delivery grants, or doesn't, and keeps the public-read grant of its
+// acl either way.
+func testAccCheckAWSS3BucketLogDelivery(n string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		acl, err := getS3BucketACL(conn, rs.Primary.ID, "")
+		if err != nil {
+			return err
+		}
+
+		if hasS3LogDeliveryGrants(acl) != expected {
+			return fmt.Errorf("log delivery grants should be %t: %#v", expected, acl.Grants)
+		}
+		for _, g := range acl.Grants {
+			if g.GranteeURI == s3AllUsersURI && g.Permission == "READ" {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("public-read grant is missing: %#v", acl.Grants)
+	}
+}
+
 func testAccCheckAWSS3BucketExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -57,7 +389,7 @@ func testAccCheckAWSS3BucketExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No S3 Bucket ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).s3conn
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
 		bucket := conn.Bucket(rs.Primary.ID)
 		resp, err := bucket.Head("/")
 		if err != nil {
@@ -68,6 +400,18 @@ func testAccCheckAWSS3BucketExists(n string) resource.TestCheckFunc {
 	}
 }
 
+func testAccCheckAWSS3BucketDisappears(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).s3conn()
+		return conn.Bucket(rs.Primary.ID).DelBucket()
+	}
+}
+
 // This needs a bit of randoness as the name can only be
 // used once globally within AWS
 var testAccAWSS3BucketConfig = fmt.Sprintf(`
@@ -76,3 +420,107 @@ resource "aws_s3_bucket" "bar" {
 	acl = "public-read"
 }
 `, rand.Int())
+
+// The lifecycle configs share a name so that removing the rules updates
+// the same bucket
+var testAccAWSS3BucketLifecycleName = fmt.Sprintf("tf-test-bucket-%d", rand.Int())
+
+var testAccAWSS3BucketConfigWithLifecycle = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+
+	lifecycle_rule {
+		id = "uploads"
+		prefix = "uploads/"
+		enabled = true
+		expiration_days = 30
+		abort_incomplete_multipart_upload_days = 7
+	}
+}
+`, testAccAWSS3BucketLifecycleName)
+
+var testAccAWSS3BucketConfigWithoutLifecycle = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+}
+`, testAccAWSS3BucketLifecycleName)
+
+// The website configs share a name so that switching between them
+// updates the same bucket
+var testAccAWSS3BucketWebsiteName = fmt.Sprintf("tf-test-bucket-%d", rand.Int())
+
+var testAccAWSS3BucketConfigWithWebsite = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+
+	website {
+		index_document = "index.html"
+		error_document = "error.html"
+	}
+}
+`, testAccAWSS3BucketWebsiteName)
+
+var testAccAWSS3BucketConfigWithWebsiteRedirect = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+
+	website {
+		redirect_all_requests_to {
+			host_name = "www.example.com"
+			protocol = "https"
+		}
+	}
+}
+`, testAccAWSS3BucketWebsiteName)
+
+var testAccAWSS3BucketConfigWithoutWebsite = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+}
+`, testAccAWSS3BucketWebsiteName)
+
+var testAccAWSS3BucketPolicyName = fmt.Sprintf("tf-test-bucket-%d", rand.Int())
+
+var testAccAWSS3BucketConfigWithPolicy = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%[1]s"
+	acl = "public-read"
+	policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Sid\": \"PublicRead\", \"Effect\": \"Allow\", \"Principal\": \"*\", \"Action\": \"s3:GetObject\", \"Resource\": \"arn:aws:s3:::%[1]s/*\"}]}"
+}
+`, testAccAWSS3BucketPolicyName)
+
+var testAccAWSS3BucketConfigWithDenyDeletePolicy = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%[1]s"
+	policy = "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Sid\": \"DenyDelete\", \"Effect\": \"Deny\", \"Principal\": \"*\", \"Action\": [\"s3:DeleteBucket\", \"s3:DeleteObject\"], \"Resource\": [\"arn:aws:s3:::%[1]s\", \"arn:aws:s3:::%[1]s/*\"]}]}"
+}
+`, testAccAWSS3BucketPolicyName)
+
+var testAccAWSS3BucketConfigWithoutPolicy = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+}
+`, testAccAWSS3BucketPolicyName)
+
+var testAccAWSS3BucketLogDeliveryName = fmt.Sprintf("tf-test-bucket-%d", rand.Int())
+
+var testAccAWSS3BucketConfigWithLogDelivery = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+	grant_log_delivery = true
+}
+`, testAccAWSS3BucketLogDeliveryName)
+
+var testAccAWSS3BucketConfigWithoutLogDelivery = fmt.Sprintf(`
+resource "aws_s3_bucket" "bar" {
+	bucket = "%s"
+	acl = "public-read"
+}
+`, testAccAWSS3BucketLogDeliveryName)
@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsSnsTopic() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsSnsTopicCreate,
+		Read:   resourceAwsSnsTopicRead,
+		Update: resourceAwsSnsTopicUpdate,
+		Delete: resourceAwsSnsTopicDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"display_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// Topics get a default policy allowing only their owner, so
+			// an unset policy is whatever SNS gave the topic
+			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				StateFunc:    jsonStateFunc,
+				ValidateFunc: validateJsonString,
+			},
+
+			"arn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// snsTopicAttributes maps the arguments of a topic that can be changed in
+// place to the names of their attributes.
+var snsTopicAttributes = map[string]string{
+	"display_name": "DisplayName",
+	"policy":       "Policy",
+}
+
+func resourceAwsSnsTopicCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).snsconn
+
+	params := map[string]string{
+		"Name": d.Get("name").(string),
+	}
+
+	var resp struct {
+		TopicArn string `xml:"CreateTopicResult>TopicArn"`
+	}
+
+	log.Printf("[DEBUG] SNS create topic: %#v", params)
+	if err := conn.Request("CreateTopic", params, &resp); err != nil {
+		return fmt.Errorf("Error creating SNS topic: %s", err)
+	}
+
+	d.SetId(resp.TopicArn)
+	log.Printf("[INFO] SNS topic ID: %s", d.Id())
+
+	return resourceAwsSnsTopicUpdate(d, meta)
+}
+
+func resourceAwsSnsTopicRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).snsconn
+
+	attrs, err := getSnsTopicAttributes(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		log.Printf("[WARN] SNS topic %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// The name is the last part of the ARN
+	d.Set("name", d.Id()[strings.LastIndex(d.Id(), ":")+1:])
+	d.Set("display_name", attrs["DisplayName"])
+	d.Set("policy", jsonStateFunc(attrs["Policy"]))
+	d.Set("arn", attrs["TopicArn"])
+
+	return nil
+}
+
+func resourceAwsSnsTopicUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).snsconn
+
+	d.Partial(true)
+
+	for k, name := range snsTopicAttributes {
+		// An unset policy is left as SNS has it
+		v, ok := d.GetOk(k)
+		if !d.HasChange(k) || (k == "policy" && !ok) {
+			continue
+		}
+
+		value, _ := v.(string)
+		params := map[string]string{
+			"TopicArn":       d.Id(),
+			"AttributeName":  name,
+			"AttributeValue": value,
+		}
+
+		log.Printf("[DEBUG] SNS set topic attributes: %#v", params)
+		if err := conn.Request("SetTopicAttributes", params, nil); err != nil {
+			return fmt.Errorf("Error setting %s of SNS topic: %s", name, err)
+		}
+
+		d.SetPartial(k)
+	}
+
+	d.Partial(false)
+
+	return resourceAwsSnsTopicRead(d, meta)
+}
+
+func resourceAwsSnsTopicDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).snsconn
+
+	params := map[string]string{
+		"TopicArn": d.Id(),
+	}
+
+	log.Printf("[DEBUG] SNS delete topic: %s", d.Id())
+	if err := conn.Request("DeleteTopic", params, nil); err != nil {
+		if isSnsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting SNS topic: %s", err)
+	}
+
+	return nil
+}
+
+// getSnsTopicAttributes returns the attributes of the topic, or nil if it
+// doesn't exist.
+func getSnsTopicAttributes(conn *queryConn, arn string) (map[string]string, error) {
+	params := map[string]string{
+		"TopicArn": arn,
+	}
+
+	var resp struct {
+		Entries []struct {
+			Key   string `xml:"key"`
+			Value string `xml:"value"`
+		} `xml:"GetTopicAttributesResult>Attributes>entry"`
+	}
+
+	log.Printf("[DEBUG] SNS get topic attributes: %s", arn)
+	if err := conn.Request("GetTopicAttributes", params, &resp); err != nil {
+		if isSnsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving SNS topic: %s", err)
+	}
+
+	attrs := make(map[string]string, len(resp.Entries))
+	for _, e := range resp.Entries {
+		attrs[e.Key] = e.Value
+	}
+
+	return attrs, nil
+}
+
+// isSnsNotFound returns true if the error is SNS reporting that the topic
+// doesn't exist.
+func isSnsNotFound(err error) bool {
+	qerr, ok := err.(*queryError)
+	return ok && qerr.Code == "NotFound"
+}
@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSSNSTopic_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSSNSTopicDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSSNSTopicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSSNSTopicExists("aws_sns_topic.foo"),
+					resource.TestCheckResourceAttr(
+						"aws_sns_topic.foo", "name", "terraform-test-topic"),
+					resource.TestCheckResourceAttr(
+						"aws_sns_topic.foo", "display_name", "foo"),
+				),
+			},
+
+			resource.TestStep{
+				Config: testAccAWSSNSTopicConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSSNSTopicExists("aws_sns_topic.foo"),
+					resource.TestCheckResourceAttr(
+						"aws_sns_topic.foo", "display_name", "bar"),
+					testAccCheckAWSSNSTopicAttribute("aws_sns_topic.foo", "DisplayName", "bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestGetSnsTopicAttributes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "GetTopicAttributes" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		if q.Get("TopicArn") != "arn:aws:sns:us-east-1:123456789012:foo" {
+			w.WriteHeader(404)
+			fmt.Fprint(w, `<ErrorResponse>
+  <Error><Type>Sender</Type><Code>NotFound</Code><Message>Topic does not exist</Message></Error>
+  <RequestId>abc</RequestId>
+</ErrorResponse>`)
+			return
+		}
+
+		fmt.Fprint(w, `<GetTopicAttributesResponse>
+  <GetTopicAttributesResult>
+    <Attributes>
+      <entry><key>TopicArn</key><value>arn:aws:sns:us-east-1:123456789012:foo</value></entry>
+      <entry><key>DisplayName</key><value>Foo</value></entry>
+    </Attributes>
+  </GetTopicAttributesResult>
+</GetTopicAttributesResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2010-03-31",
+		httpClient: http.DefaultClient,
+	}
+
+	attrs, err := getSnsTopicAttributes(conn, "arn:aws:sns:us-east-1:123456789012:foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if attrs["DisplayName"] != "Foo" || attrs["TopicArn"] != "arn:aws:sns:us-east-1:123456789012:foo" {
+		t.Fatalf("bad: %#v", attrs)
+	}
+
+	attrs, err = getSnsTopicAttributes(conn, "arn:aws:sns:us-east-1:123456789012:bar")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if attrs != nil {
+		t.Fatalf("expected no topic: %#v", attrs)
+	}
+}
+
+func testAccCheckAWSSNSTopicDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).snsconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_sns_topic" {
+			continue
+		}
+
+		attrs, err := getSnsTopicAttributes(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if attrs != nil {
+			return fmt.Errorf("SNS topic still exists: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSSNSTopicExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No SNS topic ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).snsconn
+		attrs, err := getSnsTopicAttributes(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if attrs == nil {
+			return fmt.Errorf("SNS topic not found")
+		}
+
+		if attrs["TopicArn"] != rs.Primary.Attributes["arn"] {
+			return fmt.Errorf("Bad ARN: %s", rs.Primary.Attributes["arn"])
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSSNSTopicAttribute(n, name, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs := s.RootModule().Resources[n]
+
+		conn := testAccProvider.Meta().(*AWSClient).snsconn
+		attrs, err := getSnsTopicAttributes(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if attrs[name] != value {
+			return fmt.Errorf("Bad %s: %s", name, attrs[name])
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSSNSTopicConfig = `
+resource "aws_sns_topic" "foo" {
+  name = "terraform-test-topic"
+  display_name = "foo"
+}
+`
+
+const testAccAWSSNSTopicConfigUpdate = `
+resource "aws_sns_topic" "foo" {
+  name = "terraform-test-topic"
+  display_name = "bar"
+}
+`
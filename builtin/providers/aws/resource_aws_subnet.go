@@ -3,6 +3,8 @@ package aws
 import (
 	"fmt"
 	"log"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -43,41 +45,75 @@ func resourceAwsSubnet() *schema.Resource {
 				Optional: true,
 			},
 
+			"ipv6_cidr_block": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSubnetIpv6CidrBlock,
+			},
+
+			"ipv6_cidr_block_association_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"assign_ipv6_address_on_creation": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
 			"tags": tagsSchema(),
+
+			"timeouts": timeoutsSchema("delete"),
 		},
 	}
 }
 
 func resourceAwsSubnetCreate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
-	createOpts := &ec2.CreateSubnet{
-		AvailabilityZone: d.Get("availability_zone").(string),
-		CidrBlock:        d.Get("cidr_block").(string),
-		VpcId:            d.Get("vpc_id").(string),
+	// goamz can't create subnets with an IPv6 CIDR block
+	params := map[string]string{
+		"CidrBlock": d.Get("cidr_block").(string),
+		"VpcId":     d.Get("vpc_id").(string),
+	}
+	if v := d.Get("availability_zone").(string); v != "" {
+		err := validateAvailabilityZones(meta.(*AWSClient), []string{v})
+		if err != nil {
+			return err
+		}
+
+		params["AvailabilityZone"] = v
+	}
+	if v := d.Get("ipv6_cidr_block").(string); v != "" {
+		params["Ipv6CidrBlock"] = v
 	}
 
-	resp, err := ec2conn.CreateSubnet(createOpts)
+	var resp struct {
+		SubnetId string `xml:"subnet>subnetId"`
+	}
 
-	if err != nil {
+	log.Printf("[DEBUG] Subnet create config: %#v", params)
+	if err := meta.(*AWSClient).ec2query.Request("CreateSubnet", params, &resp); err != nil {
 		return fmt.Errorf("Error creating subnet: %s", err)
 	}
 
 	// Get the ID and store it
-	subnet := &resp.Subnet
-	d.SetId(subnet.SubnetId)
-	log.Printf("[INFO] Subnet ID: %s", subnet.SubnetId)
+	d.SetId(resp.SubnetId)
+	log.Printf("[INFO] Subnet ID: %s", d.Id())
 
 	// Wait for the Subnet to become available
-	log.Printf("[DEBUG] Waiting for subnet (%s) to become available", subnet.SubnetId)
+	log.Printf("[DEBUG] Waiting for subnet (%s) to become available", d.Id())
 	stateConf := &resource.StateChangeConf{
 		Pending: []string{"pending"},
 		Target:  "available",
-		Refresh: SubnetStateRefreshFunc(ec2conn, subnet.SubnetId),
+		Refresh: SubnetStateRefreshFunc(ec2conn, d.Id()),
 		Timeout: 10 * time.Minute,
 	}
 
-	_, err = stateConf.WaitForState()
+	_, err := stateConf.WaitForState()
 
 	if err != nil {
 		return fmt.Errorf(
@@ -85,11 +121,29 @@ func resourceAwsSubnetCreate(d *schema.ResourceData, meta interface{}) error {
 			d.Id(), err)
 	}
 
+	// IPv6 addresses can only be assigned on creation once the block
+	// is associated
+	if _, ok := params["Ipv6CidrBlock"]; ok {
+		log.Printf("[DEBUG] Waiting for the IPv6 CIDR block of subnet (%s) to be associated", d.Id())
+		stateConf := &resource.StateChangeConf{
+			Pending: []string{"associating"},
+			Target:  "associated",
+			Refresh: subnetIpv6StateRefreshFunc(meta.(*AWSClient).ec2query, d.Id()),
+			Timeout: 5 * time.Minute,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf(
+				"Error waiting for the IPv6 CIDR block of subnet (%s) to be associated: %s",
+				d.Id(), err)
+		}
+	}
+
 	return resourceAwsSubnetUpdate(d, meta)
 }
 
 func resourceAwsSubnetRead(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	resp, err := ec2conn.DescribeSubnets([]string{d.Id()}, ec2.NewFilter())
 
@@ -108,11 +162,24 @@ func resourceAwsSubnetRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("map_public_ip_on_launch", subnet.MapPublicIpOnLaunch)
 	d.Set("tags", tagsToMap(subnet.Tags))
 
+	ipv6, err := describeSubnetIpv6(meta.(*AWSClient).ec2query, d.Id())
+	if err != nil {
+		return err
+	}
+	if ipv6 == nil {
+		return nil
+	}
+
+	d.Set("assign_ipv6_address_on_creation", ipv6.AssignIpv6AddressOnCreation)
+	block := ipv6.associatedBlock()
+	d.Set("ipv6_cidr_block", block.Ipv6CidrBlock)
+	d.Set("ipv6_cidr_block_association_id", block.AssociationId)
+
 	return nil
 }
 
 func resourceAwsSubnetUpdate(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
 	d.Partial(true)
 
@@ -139,21 +206,41 @@ func resourceAwsSubnetUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("assign_ipv6_address_on_creation") {
+		params := map[string]string{
+			"SubnetId": d.Id(),
+			"AssignIpv6AddressOnCreation.Value": strconv.FormatBool(
+				d.Get("assign_ipv6_address_on_creation").(bool)),
+		}
+
+		log.Printf("[DEBUG] Subnet modify attributes: %#v", params)
+		err := meta.(*AWSClient).ec2query.Request("ModifySubnetAttribute", params, nil)
+		if err != nil {
+			return fmt.Errorf("Error modifying subnet %s: %s", d.Id(), err)
+		}
+
+		d.SetPartial("assign_ipv6_address_on_creation")
+	}
+
 	d.Partial(false)
 
 	return resourceAwsSubnetRead(d, meta)
 }
 
 func resourceAwsSubnetDelete(d *schema.ResourceData, meta interface{}) error {
-	ec2conn := meta.(*AWSClient).ec2conn
+	ec2conn := meta.(*AWSClient).ec2conn()
 
-	log.Printf("[INFO] Deleting subnet: %s", d.Id())
-	if _, err := ec2conn.DeleteSubnet(d.Id()); err != nil {
-		ec2err, ok := err.(*ec2.Error)
-		if ok && ec2err.Code == "InvalidSubnetID.NotFound" {
-			return nil
-		}
+	timeout, err := resourceTimeout(d, "delete", 5*time.Minute)
+	if err != nil {
+		return err
+	}
 
+	log.Printf("[INFO] Deleting subnet: %s", d.Id())
+	err = retryDependencyViolation(timeout, "InvalidSubnetID.NotFound", func() error {
+		_, err := ec2conn.DeleteSubnet(d.Id())
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("Error deleting subnet: %s", err)
 	}
 
@@ -183,3 +270,91 @@ func SubnetStateRefreshFunc(conn *ec2.EC2, id string) resource.StateRefreshFunc
 		return subnet, subnet.State, nil
 	}
 }
+
+// ec2SubnetIpv6 are the IPv6 settings of a subnet, which goamz doesn't
+// know about.
+type ec2SubnetIpv6 struct {
+	AssignIpv6AddressOnCreation bool                           `xml:"assignIpv6AddressOnCreation"`
+	Associations                []ec2SubnetIpv6CidrAssociation `xml:"ipv6CidrBlockAssociationSet>item"`
+}
+
+type ec2SubnetIpv6CidrAssociation struct {
+	AssociationId string `xml:"associationId"`
+	Ipv6CidrBlock string `xml:"ipv6CidrBlock"`
+	State         string `xml:"ipv6CidrBlockState>state"`
+}
+
+// associatedBlock returns the IPv6 CIDR block of the subnet. Blocks that
+// were disassociated stay listed for a while, so those are skipped.
+func (s *ec2SubnetIpv6) associatedBlock() ec2SubnetIpv6CidrAssociation {
+	for _, a := range s.Associations {
+		if a.State == "associating" || a.State == "associated" {
+			return a
+		}
+	}
+
+	return ec2SubnetIpv6CidrAssociation{}
+}
+
+// describeSubnetIpv6 returns the IPv6 settings of the subnet, or nil if
+// the subnet doesn't exist.
+func describeSubnetIpv6(conn *queryConn, id string) (*ec2SubnetIpv6, error) {
+	params := map[string]string{
+		"SubnetId.1": id,
+	}
+
+	var resp struct {
+		Subnets []ec2SubnetIpv6 `xml:"subnetSet>item"`
+	}
+
+	if err := conn.Request("DescribeSubnets", params, &resp); err != nil {
+		if isAWSErr(err, "InvalidSubnetID.NotFound") {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving subnet %s: %s", id, err)
+	}
+	if len(resp.Subnets) == 0 {
+		return nil, nil
+	}
+
+	return &resp.Subnets[0], nil
+}
+
+// subnetIpv6StateRefreshFunc returns a resource.StateRefreshFunc that
+// watches the association of the IPv6 CIDR block of a subnet.
+func subnetIpv6StateRefreshFunc(conn *queryConn, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		ipv6, err := describeSubnetIpv6(conn, id)
+		if err != nil {
+			return nil, "", err
+		}
+		if ipv6 == nil || len(ipv6.Associations) == 0 {
+			return nil, "", nil
+		}
+
+		a := ipv6.Associations[0]
+		return a, a.State, nil
+	}
+}
+
+// validateSubnetIpv6CidrBlock checks that the block is an IPv6 /64, the
+// only size subnets take, given by its network address.
+func validateSubnetIpv6CidrBlock(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	ip, ipnet, err := net.ParseCIDR(value)
+	if err != nil || ip.To4() != nil {
+		es = append(es, fmt.Errorf("%s must be an IPv6 CIDR block, got %q", k, value))
+		return
+	}
+
+	if ones, _ := ipnet.Mask.Size(); ones != 64 {
+		es = append(es, fmt.Errorf("%s must be a /64, got %q", k, value))
+	}
+	if !ip.Equal(ipnet.IP) {
+		es = append(es, fmt.Errorf(
+			"%s must be given by its network address %s, got %q", k, ipnet.IP, value))
+	}
+
+	return
+}
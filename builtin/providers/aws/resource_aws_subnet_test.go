@@ -2,10 +2,13 @@ package aws
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
 	"github.com/mitchellh/goamz/ec2"
 )
 
@@ -41,8 +44,133 @@ func TestAccAWSSubnet(t *testing.T) {
 	})
 }
 
+func TestAccAWSSubnet_tags(t *testing.T) {
+	var v ec2.Subnet
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSubnetDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccSubnetConfigTags,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSubnetExists("aws_subnet.foo", &v),
+					testAccCheckTags(&v.Tags, "foo", "bar"),
+					testAccCheckTags(&v.Tags, "env", "staging"),
+					resource.TestCheckResourceAttr(
+						"aws_subnet.foo", "tags.#", "2"),
+				),
+			},
+
+			// Adds, removes and changes tags in a single apply
+			resource.TestStep{
+				Config: testAccSubnetConfigTagsUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSubnetExists("aws_subnet.foo", &v),
+					testAccCheckTags(&v.Tags, "foo", ""),
+					testAccCheckTags(&v.Tags, "env", "production"),
+					testAccCheckTags(&v.Tags, "bar", "baz"),
+					resource.TestCheckResourceAttr(
+						"aws_subnet.foo", "tags.#", "2"),
+					resource.TestCheckResourceAttr(
+						"aws_subnet.foo", "tags.env", "production"),
+				),
+			},
+		},
+	})
+}
+
+func TestDescribeSubnetIpv6(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("SubnetId.1") != "subnet-1234" {
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidSubnetID.NotFound</Code>`+
+				`<Message>not found</Message></Error></Errors></Response>`)
+			return
+		}
+
+		fmt.Fprint(w, `<DescribeSubnetsResponse>
+  <subnetSet>
+    <item>
+      <subnetId>subnet-1234</subnetId>
+      <assignIpv6AddressOnCreation>true</assignIpv6AddressOnCreation>
+      <ipv6CidrBlockAssociationSet>
+        <item>
+          <associationId>subnet-cidr-assoc-1</associationId>
+          <ipv6CidrBlock>2600:1f14:abc:de00::/64</ipv6CidrBlock>
+          <ipv6CidrBlockState><state>disassociated</state></ipv6CidrBlockState>
+        </item>
+        <item>
+          <associationId>subnet-cidr-assoc-2</associationId>
+          <ipv6CidrBlock>2600:1f14:abc:de01::/64</ipv6CidrBlock>
+          <ipv6CidrBlockState><state>associated</state></ipv6CidrBlockState>
+        </item>
+      </ipv6CidrBlockAssociationSet>
+    </item>
+  </subnetSet>
+</DescribeSubnetsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2016-11-15",
+		httpClient: http.DefaultClient,
+	}
+
+	ipv6, err := describeSubnetIpv6(conn, "subnet-1234")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ipv6 == nil || !ipv6.AssignIpv6AddressOnCreation {
+		t.Fatalf("bad: %#v", ipv6)
+	}
+
+	// The disassociated block is skipped
+	block := ipv6.associatedBlock()
+	if block.AssociationId != "subnet-cidr-assoc-2" || block.Ipv6CidrBlock != "2600:1f14:abc:de01::/64" {
+		t.Fatalf("bad: %#v", block)
+	}
+
+	// Subnets that are gone are nothing rather than an error
+	ipv6, err = describeSubnetIpv6(conn, "subnet-5678")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ipv6 != nil {
+		t.Fatalf("bad: %#v", ipv6)
+	}
+}
+
+func TestValidateSubnetIpv6CidrBlock(t *testing.T) {
+	valid := []string{
+		"2600:1f14:abc:de00::/64",
+		"2001:db8::/64",
+	}
+	for _, v := range valid {
+		if _, es := validateSubnetIpv6CidrBlock(v, "ipv6_cidr_block"); len(es) > 0 {
+			t.Fatalf("%q should be valid: %v", v, es)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"10.1.1.0/24",
+		"2600:1f14:abc:de00::/56",
+		"2600:1f14:abc:de00::1/64",
+		"2600:1f14:abc:de00::",
+	}
+	for _, v := range invalid {
+		if _, es := validateSubnetIpv6CidrBlock(v, "ipv6_cidr_block"); len(es) == 0 {
+			t.Fatalf("%q should be invalid", v)
+		}
+	}
+}
+
 func testAccCheckSubnetDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_subnet" {
@@ -84,7 +212,7 @@ func testAccCheckSubnetExists(n string, v *ec2.Subnet) resource.TestCheckFunc {
 			return fmt.Errorf("No ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 		resp, err := conn.DescribeSubnets(
 			[]string{rs.Primary.ID}, ec2.NewFilter())
 		if err != nil {
@@ -111,3 +239,35 @@ resource "aws_subnet" "foo" {
 	map_public_ip_on_launch = true
 }
 `
+
+const testAccSubnetConfigTags = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_subnet" "foo" {
+	cidr_block = "10.1.1.0/24"
+	vpc_id = "${aws_vpc.foo.id}"
+
+	tags {
+		foo = "bar"
+		env = "staging"
+	}
+}
+`
+
+const testAccSubnetConfigTagsUpdate = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_subnet" "foo" {
+	cidr_block = "10.1.1.0/24"
+	vpc_id = "${aws_vpc.foo.id}"
+
+	tags {
+		bar = "baz"
+		env = "production"
+	}
+}
+`
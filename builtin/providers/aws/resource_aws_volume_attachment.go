@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func resourceAwsVolumeAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsVolumeAttachmentCreate,
+		Read:   resourceAwsVolumeAttachmentRead,
+		Update: resourceAwsVolumeAttachmentUpdate,
+		Delete: resourceAwsVolumeAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"device_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"instance_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"volume_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"force_detach": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceAwsVolumeAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	name := d.Get("device_name").(string)
+	iID := d.Get("instance_id").(string)
+	vID := d.Get("volume_id").(string)
+
+	log.Printf(
+		"[INFO] Attaching EBS volume (%s) to instance (%s) as %s", vID, iID, name)
+	_, err := ec2conn.AttachVolume(vID, iID, name)
+	if err != nil {
+		return fmt.Errorf(
+			"Error attaching EBS volume (%s) to instance (%s): %s", vID, iID, err)
+	}
+
+	d.SetId(volumeAttachmentID(name, vID, iID))
+
+	log.Printf("[DEBUG] Waiting for EBS volume (%s) to attach", vID)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"detached", "attaching"},
+		Target:     "attached",
+		Refresh:    VolumeAttachmentStateRefreshFunc(ec2conn, vID, iID),
+		Timeout:    5 * time.Minute,
+		Delay:      10 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for EBS volume (%s) to attach to instance (%s): %s",
+			vID, iID, err)
+	}
+
+	return resourceAwsVolumeAttachmentRead(d, meta)
+}
+
+func resourceAwsVolumeAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	vID := d.Get("volume_id").(string)
+	iID := d.Get("instance_id").(string)
+
+	_, state, err := VolumeAttachmentStateRefreshFunc(ec2conn, vID, iID)()
+	if err != nil {
+		return err
+	}
+	if state == "detached" {
+		// The volume or the attachment is gone
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceAwsVolumeAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Only force_detach can change in place, and it's only used on delete
+	return resourceAwsVolumeAttachmentRead(d, meta)
+}
+
+func resourceAwsVolumeAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	vID := d.Get("volume_id").(string)
+	iID := d.Get("instance_id").(string)
+	force := d.Get("force_detach").(bool)
+
+	log.Printf(
+		"[INFO] Detaching EBS volume (%s) from instance (%s), force: %t", vID, iID, force)
+	err := detachVolume(meta.(*AWSClient).ec2query, vID, iID, force)
+	if err != nil {
+		if isAWSErr(err, "InvalidVolume.NotFound") || isAWSErr(err, "IncorrectState") {
+			// Already gone or already detached
+			return nil
+		}
+
+		return fmt.Errorf(
+			"Error detaching EBS volume (%s) from instance (%s): %s", vID, iID, err)
+	}
+
+	log.Printf("[DEBUG] Waiting for EBS volume (%s) to detach", vID)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"attached", "detaching"},
+		Target:     "detached",
+		Refresh:    VolumeAttachmentStateRefreshFunc(ec2conn, vID, iID),
+		Timeout:    5 * time.Minute,
+		Delay:      10 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for EBS volume (%s) to detach from instance (%s): %s",
+			vID, iID, err)
+	}
+
+	return nil
+}
+
+// detachVolume detaches the volume from the instance. goamz can't force a
+// detach, which skips waiting for the instance to release the volume, so
+// this uses the query API.
+func detachVolume(conn *queryConn, volumeID, instanceID string, force bool) error {
+	params := map[string]string{
+		"VolumeId":   volumeID,
+		"InstanceId": instanceID,
+	}
+	if force {
+		params["Force"] = "true"
+	}
+
+	return conn.Request("DetachVolume", params, nil)
+}
+
+// VolumeAttachmentStateRefreshFunc returns a resource.StateRefreshFunc that
+// is used to watch the attachment of an EBS volume to an instance. The
+// state is "detached" if the volume isn't attached to the instance.
+func VolumeAttachmentStateRefreshFunc(
+	conn *ec2.EC2, volumeID, instanceID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		volRaw, _, err := VolumeStateRefreshFunc(conn, volumeID)()
+		if err != nil {
+			return nil, "", err
+		}
+		if volRaw == nil {
+			return nil, "detached", nil
+		}
+
+		vol := volRaw.(*ec2.Volume)
+		for i, a := range vol.Attachments {
+			if a.InstanceId == instanceID {
+				return &vol.Attachments[i], a.Status, nil
+			}
+		}
+
+		return vol, "detached", nil
+	}
+}
+
+func volumeAttachmentID(name, volumeID, instanceID string) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s-", name))
+	buf.WriteString(fmt.Sprintf("%s-", instanceID))
+	buf.WriteString(fmt.Sprintf("%s-", volumeID))
+
+	return fmt.Sprintf("vai-%d", hashcode.String(buf.String()))
+}
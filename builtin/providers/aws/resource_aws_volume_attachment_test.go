@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func TestAccAWSVolumeAttachment_basic(t *testing.T) {
+	var i ec2.Instance
+	var v ec2.Volume
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVolumeAttachmentDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccVolumeAttachmentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"aws_volume_attachment.ebs_att", "device_name", "/dev/sdh"),
+					testAccCheckInstanceExists(
+						"aws_instance.web", &i),
+					testAccCheckVolumeExists(
+						"aws_ebs_volume.example", &v),
+					testAccCheckVolumeAttachmentExists(
+						"aws_volume_attachment.ebs_att", &i, &v),
+				),
+			},
+		},
+	})
+}
+
+func TestDetachVolume(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("Action") != "DetachVolume" ||
+				q.Get("VolumeId") != "vol-1234" || q.Get("InstanceId") != "i-1234" {
+				t.Errorf("bad query: %s", r.URL.RawQuery)
+			}
+			if force != (q.Get("Force") == "true") {
+				t.Errorf("%t: bad query: %s", force, r.URL.RawQuery)
+			}
+
+			fmt.Fprint(w, `<DetachVolumeResponse>
+  <volumeId>vol-1234</volumeId>
+  <instanceId>i-1234</instanceId>
+  <status>detaching</status>
+</DetachVolumeResponse>`)
+		}))
+
+		conn := &queryConn{
+			auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			endpoint:   ts.URL,
+			version:    "2016-11-15",
+			httpClient: http.DefaultClient,
+		}
+
+		err := detachVolume(conn, "vol-1234", "i-1234", force)
+		ts.Close()
+		if err != nil {
+			t.Fatalf("%t: err: %s", force, err)
+		}
+	}
+}
+
+func testAccCheckVolumeAttachmentExists(n string, i *ec2.Instance, v *ec2.Volume) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		for _, b := range i.BlockDevices {
+			if rs.Primary.Attributes["device_name"] == b.DeviceName {
+				if b.VolumeId == v.VolumeId {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("Error finding instance/volume")
+	}
+}
+
+func testAccCheckVolumeAttachmentDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_volume_attachment" {
+			continue
+		}
+
+		_, state, err := VolumeAttachmentStateRefreshFunc(
+			conn,
+			rs.Primary.Attributes["volume_id"],
+			rs.Primary.Attributes["instance_id"])()
+		if err != nil {
+			return err
+		}
+		if state != "detached" {
+			return fmt.Errorf("Volume attachment still exists: %s", state)
+		}
+	}
+
+	return nil
+}
+
+const testAccVolumeAttachmentConfig = `
+resource "aws_instance" "web" {
+	# us-west-2
+	ami = "ami-4fccb37f"
+	availability_zone = "us-west-2a"
+	instance_type = "m1.small"
+}
+
+resource "aws_ebs_volume" "example" {
+	availability_zone = "us-west-2a"
+	size = 1
+}
+
+resource "aws_volume_attachment" "ebs_att" {
+	device_name = "/dev/sdh"
+	volume_id = "${aws_ebs_volume.example.id}"
+	instance_id = "${aws_instance.web.id}"
+}
+`
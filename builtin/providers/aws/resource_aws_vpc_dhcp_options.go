@@ -0,0 +1,274 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/ec2"
+)
+
+func resourceAwsVpcDhcpOptions() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsVpcDhcpOptionsCreate,
+		Read:   resourceAwsVpcDhcpOptionsRead,
+		Update: resourceAwsVpcDhcpOptionsUpdate,
+		Delete: resourceAwsVpcDhcpOptionsDelete,
+
+		ValidateFunc: resourceAwsVpcDhcpOptionsValidate,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			// The servers are tried in order, so these are lists
+			"domain_name_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"ntp_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"netbios_name_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"netbios_node_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNetbiosNodeType,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+// ec2DhcpOptionNames are the DHCP options, by the attribute that sets
+// them, in the order they're sent.
+var ec2DhcpOptionNames = []struct {
+	Attr string
+	Key  string
+}{
+	{"domain_name", "domain-name"},
+	{"domain_name_servers", "domain-name-servers"},
+	{"ntp_servers", "ntp-servers"},
+	{"netbios_name_servers", "netbios-name-servers"},
+	{"netbios_node_type", "netbios-node-type"},
+}
+
+// ec2DhcpOptions is a DHCP options set as returned by DescribeDhcpOptions.
+type ec2DhcpOptions struct {
+	DhcpOptionsId  string                 `xml:"dhcpOptionsId"`
+	Configurations []ec2DhcpConfiguration `xml:"dhcpConfigurationSet>item"`
+	Tags           []ec2.Tag              `xml:"tagSet>item"`
+}
+
+type ec2DhcpConfiguration struct {
+	Key    string   `xml:"key"`
+	Values []string `xml:"valueSet>item>value"`
+}
+
+func resourceAwsVpcDhcpOptionsCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := make(map[string]string)
+	n := 0
+	for _, o := range ec2DhcpOptionNames {
+		var values []string
+		switch v := d.Get(o.Attr).(type) {
+		case string:
+			if v != "" {
+				values = []string{v}
+			}
+		case []interface{}:
+			values = expandStringList(v)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		n++
+		prefix := fmt.Sprintf("DhcpConfiguration.%d.", n)
+		params[prefix+"Key"] = o.Key
+		for i, v := range values {
+			params[fmt.Sprintf("%sValue.%d", prefix, i+1)] = v
+		}
+	}
+
+	var resp struct {
+		DhcpOptionsId string `xml:"dhcpOptions>dhcpOptionsId"`
+	}
+
+	log.Printf("[DEBUG] DHCP options create: %#v", params)
+	if err := conn.Request("CreateDhcpOptions", params, &resp); err != nil {
+		return fmt.Errorf("Error creating DHCP options: %s", err)
+	}
+
+	d.SetId(resp.DhcpOptionsId)
+	log.Printf("[INFO] DHCP options ID: %s", d.Id())
+
+	if err := setTags(meta.(*AWSClient).ec2conn(), d); err != nil {
+		return err
+	}
+
+	return resourceAwsVpcDhcpOptionsRead(d, meta)
+}
+
+func resourceAwsVpcDhcpOptionsRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	options, err := describeDhcpOptions(conn, d.Id())
+	if err != nil {
+		return err
+	}
+	if options == nil {
+		log.Printf("[WARN] DHCP options %s not found, removing", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	for k, v := range flattenDhcpConfigurations(options.Configurations) {
+		d.Set(k, v)
+	}
+	d.Set("tags", tagsToMap(options.Tags))
+
+	return nil
+}
+
+func resourceAwsVpcDhcpOptionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	ec2conn := meta.(*AWSClient).ec2conn()
+
+	if err := setTags(ec2conn, d); err != nil {
+		return err
+	}
+
+	return resourceAwsVpcDhcpOptionsRead(d, meta)
+}
+
+func resourceAwsVpcDhcpOptionsDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{
+		"DhcpOptionsId": d.Id(),
+	}
+
+	// A VPC that was just moved to other options can keep these in use
+	// for a little while
+	log.Printf("[INFO] Deleting DHCP options: %s", d.Id())
+	err := resource.Retry(1*time.Minute, retryOnAwsCodes([]string{"DependencyViolation"}, func() error {
+		return conn.Request("DeleteDhcpOptions", params, nil)
+	}))
+	if err != nil {
+		if isAWSErr(err, "InvalidDhcpOptionID.NotFound") {
+			return nil
+		}
+		if isAWSErr(err, "DependencyViolation") {
+			return fmt.Errorf(
+				"DHCP options %s are still associated with a VPC. Associate the "+
+					"VPC with other DHCP options, or with \"default\", before "+
+					"deleting them: %s", d.Id(), err)
+		}
+
+		return fmt.Errorf("Error deleting DHCP options %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsVpcDhcpOptionsValidate(c *terraform.ResourceConfig) (ws []string, es []error) {
+	for _, o := range ec2DhcpOptionNames {
+		if c.IsSet(o.Attr) {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"At least one of domain_name, domain_name_servers, ntp_servers, "+
+			"netbios_name_servers or netbios_node_type must be set"))
+	return
+}
+
+func validateNetbiosNodeType(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "1", "2", "4", "8":
+	default:
+		es = append(es, fmt.Errorf("%s must be 1, 2, 4 or 8, got %q", k, v))
+	}
+
+	return
+}
+
+// describeDhcpOptions returns the DHCP options with the given ID, or nil
+// if they don't exist.
+func describeDhcpOptions(conn *queryConn, id string) (*ec2DhcpOptions, error) {
+	params := map[string]string{
+		"DhcpOptionsId.1": id,
+	}
+
+	var resp struct {
+		DhcpOptions []ec2DhcpOptions `xml:"dhcpOptionsSet>item"`
+	}
+
+	err := conn.Request("DescribeDhcpOptions", params, &resp)
+	if err != nil {
+		if isAWSErr(err, "InvalidDhcpOptionID.NotFound") {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving DHCP options %s: %s", id, err)
+	}
+
+	if len(resp.DhcpOptions) == 0 {
+		return nil, nil
+	}
+
+	return &resp.DhcpOptions[0], nil
+}
+
+// Flattens the DHCP configurations into the attributes that set them.
+// The options that aren't set are returned empty, so that they are
+// cleared from the state.
+func flattenDhcpConfigurations(configs []ec2DhcpConfiguration) map[string]interface{} {
+	values := make(map[string][]string)
+	for _, c := range configs {
+		values[c.Key] = c.Values
+	}
+
+	result := make(map[string]interface{})
+	for _, o := range ec2DhcpOptionNames {
+		switch o.Attr {
+		case "domain_name", "netbios_node_type":
+			v := ""
+			if len(values[o.Key]) > 0 {
+				v = values[o.Key][0]
+			}
+			result[o.Attr] = v
+		default:
+			v := values[o.Key]
+			if v == nil {
+				v = []string{}
+			}
+			result[o.Attr] = v
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsVpcDhcpOptionsAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsVpcDhcpOptionsAssociationCreate,
+		Read:   resourceAwsVpcDhcpOptionsAssociationRead,
+		Update: resourceAwsVpcDhcpOptionsAssociationCreate,
+		Delete: resourceAwsVpcDhcpOptionsAssociationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			// A VPC is always associated with exactly one set of DHCP
+			// options, so changing them is just associating others
+			"dhcp_options_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+// AssociateDhcpOptions replaces whatever options the VPC had, so it serves
+// as both create and update.
+func resourceAwsVpcDhcpOptionsAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	vpcId := d.Get("vpc_id").(string)
+	optionsId := d.Get("dhcp_options_id").(string)
+
+	log.Printf("[DEBUG] Associating VPC %s with DHCP options %s", vpcId, optionsId)
+	if err := associateDhcpOptions(conn, vpcId, optionsId); err != nil {
+		return fmt.Errorf(
+			"Error associating VPC %s with DHCP options %s: %s", vpcId, optionsId, err)
+	}
+
+	// The VPC has a single association, so it identifies it
+	d.SetId(vpcId)
+
+	return resourceAwsVpcDhcpOptionsAssociationRead(d, meta)
+}
+
+func resourceAwsVpcDhcpOptionsAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	params := map[string]string{
+		"VpcId.1": d.Id(),
+	}
+
+	var resp struct {
+		Vpcs []struct {
+			DhcpOptionsId string `xml:"dhcpOptionsId"`
+		} `xml:"vpcSet>item"`
+	}
+
+	err := conn.Request("DescribeVpcs", params, &resp)
+	if err != nil && !isAWSErr(err, "InvalidVpcID.NotFound") {
+		return fmt.Errorf("Error retrieving VPC %s: %s", d.Id(), err)
+	}
+	if err != nil || len(resp.Vpcs) == 0 {
+		log.Printf("[WARN] VPC %s not found, removing DHCP options association", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// Options associated outside of Terraform show up as a diff, which
+	// associates the configured ones again
+	d.Set("vpc_id", d.Id())
+	d.Set("dhcp_options_id", resp.Vpcs[0].DhcpOptionsId)
+
+	return nil
+}
+
+// Removing the association puts the VPC back on the default options,
+// which leaves the ones it had free to be deleted.
+func resourceAwsVpcDhcpOptionsAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2query
+
+	log.Printf("[DEBUG] Associating VPC %s with the default DHCP options", d.Id())
+	if err := associateDhcpOptions(conn, d.Id(), "default"); err != nil {
+		if isAWSErr(err, "InvalidVpcID.NotFound") {
+			return nil
+		}
+
+		return fmt.Errorf(
+			"Error associating VPC %s with the default DHCP options: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// associateDhcpOptions associates the VPC with the DHCP options, or with
+// none at all if optionsId is "default".
+func associateDhcpOptions(conn *queryConn, vpcId, optionsId string) error {
+	params := map[string]string{
+		"VpcId":         vpcId,
+		"DhcpOptionsId": optionsId,
+	}
+
+	return conn.Request("AssociateDhcpOptions", params, nil)
+}
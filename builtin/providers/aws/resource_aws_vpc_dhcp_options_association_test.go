@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSVpcDhcpOptionsAssociation_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSVpcDhcpOptionsDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSVpcDhcpOptionsConfig,
+				Check: testAccCheckAWSVpcDhcpOptionsAssociated(
+					"aws_vpc.foo", "aws_vpc_dhcp_options.foo"),
+			},
+
+			// Removing the association puts the VPC back on the default
+			// options, so the ones it had can be deleted
+			resource.TestStep{
+				Config: testAccAWSVpcDhcpOptionsAssociationConfigRemoved,
+				Check: testAccCheckAWSVpcDhcpOptionsAssociated(
+					"aws_vpc.foo", ""),
+			},
+		},
+	})
+}
+
+// testAccCheckAWSVpcDhcpOptionsAssociated checks that the VPC is
+// associated with the DHCP options, or with the default ones if options
+// is empty.
+func testAccCheckAWSVpcDhcpOptionsAssociated(vpc, options string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[vpc]
+		if !ok {
+			return fmt.Errorf("Not found: %s", vpc)
+		}
+
+		expected := "default"
+		if options != "" {
+			ors, ok := s.RootModule().Resources[options]
+			if !ok {
+				return fmt.Errorf("Not found: %s", options)
+			}
+			expected = ors.Primary.ID
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ec2query
+		params := map[string]string{
+			"VpcId.1": rs.Primary.ID,
+		}
+
+		var resp struct {
+			Vpcs []struct {
+				DhcpOptionsId string `xml:"dhcpOptionsId"`
+			} `xml:"vpcSet>item"`
+		}
+		if err := conn.Request("DescribeVpcs", params, &resp); err != nil {
+			return err
+		}
+		if len(resp.Vpcs) != 1 {
+			return fmt.Errorf("VPC not found: %s", rs.Primary.ID)
+		}
+
+		if resp.Vpcs[0].DhcpOptionsId != expected {
+			return fmt.Errorf(
+				"bad DHCP options: %s, expected %s", resp.Vpcs[0].DhcpOptionsId, expected)
+		}
+
+		return nil
+	}
+}
+
+const testAccAWSVpcDhcpOptionsAssociationConfigRemoved = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+`
@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAccAWSVpcDhcpOptions_basic(t *testing.T) {
+	var options ec2DhcpOptions
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSVpcDhcpOptionsDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSVpcDhcpOptionsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSVpcDhcpOptionsExists("aws_vpc_dhcp_options.foo", &options),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "domain_name", "service.consul"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "domain_name_servers.0", "127.0.0.1"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "domain_name_servers.1", "10.0.0.2"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "ntp_servers.0", "127.0.0.1"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "netbios_node_type", "2"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc_dhcp_options.foo", "tags.Name", "foo-name"),
+					testAccCheckAWSVpcDhcpOptionsAssociated(
+						"aws_vpc.foo", "aws_vpc_dhcp_options.foo"),
+				),
+			},
+		},
+	})
+}
+
+func TestDescribeDhcpOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("DhcpOptionsId.1") != "dopt-1234" {
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidDhcpOptionID.NotFound</Code>`+
+				`<Message>not found</Message></Error></Errors></Response>`)
+			return
+		}
+
+		fmt.Fprint(w, `<DescribeDhcpOptionsResponse>
+  <dhcpOptionsSet>
+    <item>
+      <dhcpOptionsId>dopt-1234</dhcpOptionsId>
+      <dhcpConfigurationSet>
+        <item>
+          <key>domain-name</key>
+          <valueSet><item><value>example.com</value></item></valueSet>
+        </item>
+        <item>
+          <key>domain-name-servers</key>
+          <valueSet>
+            <item><value>10.2.5.1</value></item>
+            <item><value>10.2.5.2</value></item>
+          </valueSet>
+        </item>
+      </dhcpConfigurationSet>
+      <tagSet>
+        <item><key>Name</key><value>foo</value></item>
+      </tagSet>
+    </item>
+  </dhcpOptionsSet>
+</DescribeDhcpOptionsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &queryConn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		version:    "2014-10-01",
+		httpClient: http.DefaultClient,
+	}
+
+	options, err := describeDhcpOptions(conn, "dopt-1234")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if options == nil || tagsToMap(options.Tags)["Name"] != "foo" {
+		t.Fatalf("bad: %#v", options)
+	}
+
+	expected := map[string]interface{}{
+		"domain_name":          "example.com",
+		"domain_name_servers":  []string{"10.2.5.1", "10.2.5.2"},
+		"ntp_servers":          []string{},
+		"netbios_name_servers": []string{},
+		"netbios_node_type":    "",
+	}
+	if attrs := flattenDhcpConfigurations(options.Configurations); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("bad: %#v", attrs)
+	}
+
+	// Options that are gone are nothing rather than an error
+	options, err = describeDhcpOptions(conn, "dopt-5678")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if options != nil {
+		t.Fatalf("bad: %#v", options)
+	}
+}
+
+func TestValidateNetbiosNodeType(t *testing.T) {
+	for _, v := range []string{"1", "2", "4", "8"} {
+		if _, es := validateNetbiosNodeType(v, "netbios_node_type"); len(es) > 0 {
+			t.Fatalf("%s: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "3", "b-node"} {
+		if _, es := validateNetbiosNodeType(v, "netbios_node_type"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
+
+func testAccCheckAWSVpcDhcpOptionsDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ec2query
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_vpc_dhcp_options" {
+			continue
+		}
+
+		options, err := describeDhcpOptions(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if options != nil {
+			return fmt.Errorf("DHCP options still exist: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSVpcDhcpOptionsExists(n string, options *ec2DhcpOptions) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No DHCP options ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ec2query
+		found, err := describeDhcpOptions(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return fmt.Errorf("DHCP options not found")
+		}
+
+		*options = *found
+		return nil
+	}
+}
+
+const testAccAWSVpcDhcpOptionsConfig = `
+resource "aws_vpc" "foo" {
+	cidr_block = "10.1.0.0/16"
+}
+
+resource "aws_vpc_dhcp_options" "foo" {
+	domain_name = "service.consul"
+	domain_name_servers = ["127.0.0.1", "10.0.0.2"]
+	ntp_servers = ["127.0.0.1"]
+	netbios_name_servers = ["127.0.0.1"]
+	netbios_node_type = "2"
+
+	tags {
+		Name = "foo-name"
+	}
+}
+
+resource "aws_vpc_dhcp_options_association" "foo" {
+	vpc_id = "${aws_vpc.foo.id}"
+	dhcp_options_id = "${aws_vpc_dhcp_options.foo.id}"
+}
+`
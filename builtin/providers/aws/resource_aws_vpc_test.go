@@ -66,6 +66,9 @@ func TestAccVpc_tags(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"aws_vpc.foo", "cidr_block", "10.1.0.0/16"),
 					testAccCheckTags(&vpc.Tags, "foo", "bar"),
+					testAccCheckTags(&vpc.Tags, "env", "staging"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc.foo", "tags.#", "2"),
 				),
 			},
 
@@ -75,6 +78,9 @@ func TestAccVpc_tags(t *testing.T) {
 					testAccCheckVpcExists("aws_vpc.foo", &vpc),
 					testAccCheckTags(&vpc.Tags, "foo", ""),
 					testAccCheckTags(&vpc.Tags, "bar", "baz"),
+					testAccCheckTags(&vpc.Tags, "env", "production"),
+					resource.TestCheckResourceAttr(
+						"aws_vpc.foo", "tags.#", "2"),
 				),
 			},
 		},
@@ -111,7 +117,7 @@ func TestAccVpcUpdate(t *testing.T) {
 }
 
 func testAccCheckVpcDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).ec2conn
+	conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_vpc" {
@@ -162,7 +168,7 @@ func testAccCheckVpcExists(n string, vpc *ec2.VPC) resource.TestCheckFunc {
 			return fmt.Errorf("No VPC ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*AWSClient).ec2conn
+		conn := testAccProvider.Meta().(*AWSClient).ec2conn()
 		resp, err := conn.DescribeVpcs([]string{rs.Primary.ID}, ec2.NewFilter())
 		if err != nil {
 			return err
@@ -196,6 +202,7 @@ resource "aws_vpc" "foo" {
 
 	tags {
 		foo = "bar"
+		env = "staging"
 	}
 }
 `
@@ -206,6 +213,7 @@ resource "aws_vpc" "foo" {
 
 	tags {
 		bar = "baz"
+		env = "production"
 	}
 }
 `
@@ -0,0 +1,192 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+const (
+	route53Endpoint  = "https://route53.amazonaws.com"
+	route53Version   = "2013-04-01"
+	route53Namespace = "https://route53.amazonaws.com/doc/2013-04-01/"
+)
+
+// route53Conn makes requests against the Route53 API for the record sets
+// goamz can't express, such as aliases. Requests are signed with the
+// AWS3-HTTPS scheme, like goamz does for Route53.
+type route53Conn struct {
+	auth       aws.Auth
+	endpoint   string
+	httpClient *http.Client
+
+	// role, if set, signs requests with the credentials of the assumed
+	// role instead of auth
+	role *assumedRole
+}
+
+// route53RecordSet is a resource record set. Alias record sets have an
+// AliasTarget instead of a TTL and records.
+type route53RecordSet struct {
+	Name            string                  `xml:"Name"`
+	Type            string                  `xml:"Type"`
+	TTL             int                     `xml:"TTL,omitempty"`
+	ResourceRecords *route53ResourceRecords `xml:"ResourceRecords,omitempty"`
+	AliasTarget     *route53AliasTarget     `xml:"AliasTarget,omitempty"`
+}
+
+// route53ResourceRecords are the values of a record set. They're a
+// pointer in route53RecordSet as encoding/xml would otherwise always give
+// aliases an empty ResourceRecords element, which Route53 rejects.
+type route53ResourceRecords struct {
+	Values []string `xml:"ResourceRecord>Value"`
+}
+
+// route53AliasTarget is the resource an alias record set points at.
+type route53AliasTarget struct {
+	HostedZoneId         string `xml:"HostedZoneId"`
+	DNSName              string `xml:"DNSName"`
+	EvaluateTargetHealth bool   `xml:"EvaluateTargetHealth"`
+}
+
+// Request sends the body, if any, to the path under the API version. If
+// out is non-nil the XML response is decoded into it. Error responses are
+// returned as a *queryError, which has the same shape.
+func (c *route53Conn) Request(method, path string, query url.Values, body []byte, out interface{}) error {
+	u := fmt.Sprintf("%s/%s/%s", c.endpoint, route53Version, path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	auth, err := signingAuth(c.auth, c.role)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now().UTC().Format(http.TimeFormat)
+	mac := hmac.New(sha256.New, []byte(auth.SecretKey))
+	mac.Write([]byte(date))
+	req.Header.Set("Date", date)
+	req.Header.Set("X-Amzn-Authorization", fmt.Sprintf(
+		"AWS3-HTTPS AWSAccessKeyId=%s,Algorithm=HmacSHA256,Signature=%s",
+		auth.AccessKey, base64.StdEncoding.EncodeToString(mac.Sum(nil))))
+	if auth.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", auth.Token)
+	}
+
+	log.Printf("[DEBUG] Route53 request %s %s", method, path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeQueryError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return xml.NewDecoder(resp.Body).Decode(out)
+}
+
+// changeRoute53RecordSet submits a change of a single record set to the
+// zone, returning the ID of the change to wait on.
+func changeRoute53RecordSet(
+	conn *route53Conn, zone, action, comment string, rec *route53RecordSet) (string, error) {
+	type change struct {
+		Action string            `xml:"Action"`
+		Record *route53RecordSet `xml:"ResourceRecordSet"`
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"ChangeResourceRecordSetsRequest"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Comment string   `xml:"ChangeBatch>Comment,omitempty"`
+		Changes []change `xml:"ChangeBatch>Changes>Change"`
+	}{
+		Xmlns:   route53Namespace,
+		Comment: comment,
+		Changes: []change{change{Action: action, Record: rec}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Id string `xml:"ChangeInfo>Id"`
+	}
+	path := fmt.Sprintf("hostedzone/%s/rrset", cleanRoute53ZoneId(zone))
+	if err := conn.Request("POST", path, nil, body, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Id, nil
+}
+
+// getRoute53RecordSet returns the record set with the name and type, or
+// nil if the zone doesn't have one.
+func getRoute53RecordSet(conn *route53Conn, zone, name, rType string) (*route53RecordSet, error) {
+	query := url.Values{
+		"name":     []string{name},
+		"type":     []string{rType},
+		"maxitems": []string{"1"},
+	}
+
+	// Listing starts at the name and type, so the first record set is
+	// the one asked for if it exists at all
+	var resp struct {
+		Records []route53RecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+	}
+	path := fmt.Sprintf("hostedzone/%s/rrset", cleanRoute53ZoneId(zone))
+	if err := conn.Request("GET", path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, rec := range resp.Records {
+		if route53FQDN(rec.Name) == route53FQDN(name) &&
+			strings.ToUpper(rec.Type) == strings.ToUpper(rType) {
+			rec := rec
+			return &rec, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// cleanRoute53ZoneId strips the "/hostedzone/" prefix the API sometimes
+// gives zone IDs.
+func cleanRoute53ZoneId(zone string) string {
+	return strings.TrimPrefix(zone, "/hostedzone/")
+}
+
+// route53FQDN returns the name as Route53 reports it: lower case and with
+// a trailing dot.
+func route53FQDN(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+
+	return name
+}
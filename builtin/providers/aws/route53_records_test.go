@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestChangeRoute53RecordSet_alias(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2013-04-01/hostedzone/Z123/rrset" {
+			t.Errorf("bad request: %s %s", r.Method, r.URL.Path)
+		}
+		if !strings.HasPrefix(r.Header.Get("X-Amzn-Authorization"), "AWS3-HTTPS AWSAccessKeyId=foo,") {
+			t.Errorf("bad authorization: %s", r.Header.Get("X-Amzn-Authorization"))
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "<TTL>") || strings.Contains(string(body), "<ResourceRecords>") {
+			t.Errorf("alias shouldn't have a TTL or records: %s", body)
+		}
+
+		var req struct {
+			Action string           `xml:"ChangeBatch>Changes>Change>Action"`
+			Record route53RecordSet `xml:"ChangeBatch>Changes>Change>ResourceRecordSet"`
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			t.Errorf("err: %s", err)
+		}
+		if req.Action != "UPSERT" || req.Record.AliasTarget == nil || req.Record.ResourceRecords != nil {
+			t.Errorf("bad change: %#v", req)
+		}
+
+		fmt.Fprint(w, `<ChangeResourceRecordSetsResponse>
+  <ChangeInfo><Id>/change/C123</Id><Status>PENDING</Status></ChangeInfo>
+</ChangeResourceRecordSetsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &route53Conn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		httpClient: http.DefaultClient,
+	}
+
+	rec := &route53RecordSet{
+		Name: "www.example.com",
+		Type: "A",
+		AliasTarget: &route53AliasTarget{
+			HostedZoneId: "Z456",
+			DNSName:      "foo-123.us-east-1.elb.amazonaws.com",
+		},
+	}
+	changeId, err := changeRoute53RecordSet(conn, "/hostedzone/Z123", "UPSERT", "", rec)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if changeId != "/change/C123" {
+		t.Fatalf("bad: %s", changeId)
+	}
+}
+
+func TestGetRoute53RecordSet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "www.example.com" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		// Listing carries on past the record set if it doesn't exist
+		fmt.Fprint(w, `<ListResourceRecordSetsResponse>
+  <ResourceRecordSets>
+    <ResourceRecordSet>
+      <Name>www.example.com.</Name>
+      <Type>A</Type>
+      <AliasTarget>
+        <HostedZoneId>Z456</HostedZoneId>
+        <DNSName>foo-123.us-east-1.elb.amazonaws.com.</DNSName>
+        <EvaluateTargetHealth>true</EvaluateTargetHealth>
+      </AliasTarget>
+    </ResourceRecordSet>
+  </ResourceRecordSets>
+</ListResourceRecordSetsResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := &route53Conn{
+		auth:       aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+		endpoint:   ts.URL,
+		httpClient: http.DefaultClient,
+	}
+
+	rec, err := getRoute53RecordSet(conn, "Z123", "www.example.com", "A")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if rec == nil || rec.AliasTarget == nil || !rec.AliasTarget.EvaluateTargetHealth {
+		t.Fatalf("bad: %#v", rec)
+	}
+
+	alias := flattenRoute53AliasTarget(rec.AliasTarget)
+	if alias[0]["name"] != "foo-123.us-east-1.elb.amazonaws.com" || alias[0]["zone_id"] != "Z456" {
+		t.Fatalf("bad: %#v", alias)
+	}
+
+	rec, err = getRoute53RecordSet(conn, "Z123", "www.example.com", "AAAA")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if rec != nil {
+		t.Fatalf("expected no record set: %#v", rec)
+	}
+}
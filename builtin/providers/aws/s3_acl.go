@@ -0,0 +1,260 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// s3CannedACLs are the canned ACLs that can be given as an "acl".
+var s3CannedACLs = []string{
+	"private",
+	"public-read",
+	"public-read-write",
+	"authenticated-read",
+	"bucket-owner-read",
+	"bucket-owner-full-control",
+	"log-delivery-write",
+}
+
+// s3CustomACL is what grantsToCannedACL returns for grants that no canned
+// ACL produces.
+const s3CustomACL = "custom"
+
+const (
+	s3AllUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	s3AuthenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	s3LogDeliveryURI        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// s3AccessControlPolicy is the response of GET Object acl.
+type s3AccessControlPolicy struct {
+	XMLName xml.Name  `xml:"AccessControlPolicy"`
+	OwnerID string    `xml:"Owner>ID"`
+	Grants  []s3Grant `xml:"AccessControlList>Grant"`
+}
+
+type s3Grant struct {
+	GranteeID  string `xml:"Grantee>ID"`
+	GranteeURI string `xml:"Grantee>URI"`
+	Permission string `xml:"Permission"`
+}
+
+// getS3ObjectACL fetches the access control policy of an object.
+func getS3ObjectACL(conn *s3Conn, bucket, key string) (*s3AccessControlPolicy, error) {
+	var p s3AccessControlPolicy
+	err := s3ObjectSubresourceRequest(conn, "GET", bucket, key, "acl", nil, nil, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// putS3ObjectACL replaces the grants of an object with a canned ACL.
+func putS3ObjectACL(conn *s3Conn, bucket, key, acl string) error {
+	headers := map[string][]string{
+		"X-Amz-Acl": []string{acl},
+	}
+
+	return s3ObjectSubresourceRequest(conn, "PUT", bucket, key, "acl", headers, nil, nil)
+}
+
+// getS3BucketACL fetches the access control policy of a bucket.
+func getS3BucketACL(conn *s3Conn, bucket, owner string) (*s3AccessControlPolicy, error) {
+	var p s3AccessControlPolicy
+	if err := s3SubresourceRequest(conn, "GET", bucket, owner, "acl", nil, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// putS3BucketACL replaces the grants of a bucket with those of the policy.
+func putS3BucketACL(conn *s3Conn, bucket, owner string, p *s3AccessControlPolicy) error {
+	// Grantees are written with their type, which S3 requires but
+	// doesn't need to be read back
+	type grantee struct {
+		XMLNSXsi string `xml:"xmlns:xsi,attr"`
+		Type     string `xml:"xsi:type,attr"`
+		ID       string `xml:"ID,omitempty"`
+		URI      string `xml:"URI,omitempty"`
+	}
+	type grant struct {
+		Grantee    grantee `xml:"Grantee"`
+		Permission string  `xml:"Permission"`
+	}
+
+	grants := make([]grant, 0, len(p.Grants))
+	for _, g := range p.Grants {
+		e := grantee{XMLNSXsi: "http://www.w3.org/2001/XMLSchema-instance"}
+		if g.GranteeURI != "" {
+			e.Type = "Group"
+			e.URI = g.GranteeURI
+		} else {
+			e.Type = "CanonicalUser"
+			e.ID = g.GranteeID
+		}
+
+		grants = append(grants, grant{Grantee: e, Permission: g.Permission})
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"AccessControlPolicy"`
+		OwnerID string   `xml:"Owner>ID"`
+		Grants  []grant  `xml:"AccessControlList>Grant"`
+	}{OwnerID: p.OwnerID, Grants: grants})
+	if err != nil {
+		return err
+	}
+
+	return s3SubresourceRequest(conn, "PUT", bucket, owner, "acl", body, nil)
+}
+
+// s3LogDeliveryGrants are the grants the log delivery group needs to write
+// access logs to a bucket, the same ones the log-delivery-write ACL gives.
+var s3LogDeliveryGrants = []s3Grant{
+	{GranteeURI: s3LogDeliveryURI, Permission: "WRITE"},
+	{GranteeURI: s3LogDeliveryURI, Permission: "READ_ACP"},
+}
+
+// hasS3LogDeliveryGrants returns true if the policy gives the log delivery
+// group every grant it needs.
+func hasS3LogDeliveryGrants(p *s3AccessControlPolicy) bool {
+	for _, lg := range s3LogDeliveryGrants {
+		found := false
+		for _, g := range p.Grants {
+			if g == lg {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setS3LogDeliveryGrants adds the log delivery grants to the policy, or
+// removes them if enabled is false, keeping the other grants. It returns
+// false if the policy was already as asked.
+func setS3LogDeliveryGrants(p *s3AccessControlPolicy, enabled bool) bool {
+	var grants []s3Grant
+	for _, g := range p.Grants {
+		if g.GranteeURI != s3LogDeliveryURI {
+			grants = append(grants, g)
+		}
+	}
+	if enabled {
+		grants = append(grants, s3LogDeliveryGrants...)
+	}
+
+	if s3GrantsString(grants) == s3GrantsString(p.Grants) {
+		return false
+	}
+
+	p.Grants = grants
+	return true
+}
+
+// updateS3BucketLogDelivery grants the log delivery group what it needs
+// to write access logs to the bucket, or revokes it, keeping the bucket's
+// other grants.
+func updateS3BucketLogDelivery(conn *s3Conn, bucket, owner string, enabled bool) error {
+	p, err := getS3BucketACL(conn, bucket, owner)
+	if err != nil {
+		return err
+	}
+
+	if !setS3LogDeliveryGrants(p, enabled) {
+		return nil
+	}
+
+	return putS3BucketACL(conn, bucket, owner, p)
+}
+
+// cannedACLToGrants returns the grants a canned ACL gives besides the
+// owner's own full control. ok is false for the ACLs whose grants depend on
+// who owns the bucket, which can't be known from the ACL alone.
+func cannedACLToGrants(acl string) (grants []s3Grant, ok bool) {
+	switch acl {
+	case "private":
+		return nil, true
+	case "public-read":
+		return []s3Grant{
+			{GranteeURI: s3AllUsersURI, Permission: "READ"},
+		}, true
+	case "public-read-write":
+		return []s3Grant{
+			{GranteeURI: s3AllUsersURI, Permission: "READ"},
+			{GranteeURI: s3AllUsersURI, Permission: "WRITE"},
+		}, true
+	case "authenticated-read":
+		return []s3Grant{
+			{GranteeURI: s3AuthenticatedUsersURI, Permission: "READ"},
+		}, true
+	case "log-delivery-write":
+		return []s3Grant{
+			{GranteeURI: s3LogDeliveryURI, Permission: "WRITE"},
+			{GranteeURI: s3LogDeliveryURI, Permission: "READ_ACP"},
+		}, true
+	}
+
+	return nil, false
+}
+
+// grantsToCannedACL returns the canned ACL that produces the grants of
+// the policy, or s3CustomACL if none does. Grants the owner has on its
+// own resource are implied by every canned ACL, so they are ignored.
+func grantsToCannedACL(p *s3AccessControlPolicy) string {
+	var grants []s3Grant
+	for _, g := range p.Grants {
+		if g.GranteeID != "" && g.GranteeID == p.OwnerID && g.Permission == "FULL_CONTROL" {
+			continue
+		}
+
+		grants = append(grants, g)
+	}
+	actual := s3GrantsString(grants)
+
+	for _, acl := range s3CannedACLs {
+		canned, ok := cannedACLToGrants(acl)
+		if ok && s3GrantsString(canned) == actual {
+			return acl
+		}
+	}
+
+	return s3CustomACL
+}
+
+// s3GrantsString returns a string identifying a set of grants, whatever
+// their order.
+func s3GrantsString(grants []s3Grant) string {
+	parts := make([]string, 0, len(grants))
+	for _, g := range grants {
+		grantee := g.GranteeID
+		if g.GranteeURI != "" {
+			grantee = g.GranteeURI
+		}
+
+		parts = append(parts, grantee+":"+g.Permission)
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, ",")
+}
+
+func validateS3CannedACL(v interface{}, k string) (ws []string, es []error) {
+	for _, acl := range s3CannedACLs {
+		if v.(string) == acl {
+			return
+		}
+	}
+
+	es = append(es, fmt.Errorf(
+		"%s must be one of %s, got %q", k, strings.Join(s3CannedACLs, ", "), v))
+	return
+}
@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func TestGrantsToCannedACL(t *testing.T) {
+	owner := `<Owner><ID>owner</ID><DisplayName>me</DisplayName></Owner>`
+	ownerGrant := `<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>`
+	group := func(uri, perm string) string {
+		return `<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>` +
+			uri + `</URI></Grantee><Permission>` + perm + `</Permission></Grant>`
+	}
+
+	cases := []struct {
+		Grants string
+		ACL    string
+	}{
+		{ownerGrant, "private"},
+		{ownerGrant + group(s3AllUsersURI, "READ"), "public-read"},
+		{ownerGrant + group(s3AllUsersURI, "WRITE") + group(s3AllUsersURI, "READ"), "public-read-write"},
+		{ownerGrant + group(s3AuthenticatedUsersURI, "READ"), "authenticated-read"},
+		{ownerGrant + group(s3LogDeliveryURI, "READ_ACP") + group(s3LogDeliveryURI, "WRITE"), "log-delivery-write"},
+		{"", "private"},
+		{ownerGrant + group(s3AllUsersURI, "READ_ACP"), s3CustomACL},
+		{ownerGrant + group(s3AllUsersURI, "READ") + group(s3AuthenticatedUsersURI, "READ"), s3CustomACL},
+		{ownerGrant + group(s3LogDeliveryURI, "WRITE"), s3CustomACL},
+	}
+
+	for i, tc := range cases {
+		var p s3AccessControlPolicy
+		body := `<AccessControlPolicy>` + owner +
+			`<AccessControlList>` + tc.Grants + `</AccessControlList></AccessControlPolicy>`
+		if err := xml.Unmarshal([]byte(body), &p); err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		if acl := grantsToCannedACL(&p); acl != tc.ACL {
+			t.Fatalf("%d: got %q, expected %q", i, acl, tc.ACL)
+		}
+	}
+}
+
+func TestCannedACLToGrants(t *testing.T) {
+	cases := []struct {
+		ACL    string
+		Grants []s3Grant
+		Ok     bool
+	}{
+		{"private", nil, true},
+		{"public-read", []s3Grant{
+			{GranteeURI: s3AllUsersURI, Permission: "READ"},
+		}, true},
+		{"public-read-write", []s3Grant{
+			{GranteeURI: s3AllUsersURI, Permission: "READ"},
+			{GranteeURI: s3AllUsersURI, Permission: "WRITE"},
+		}, true},
+		{"authenticated-read", []s3Grant{
+			{GranteeURI: s3AuthenticatedUsersURI, Permission: "READ"},
+		}, true},
+		{"log-delivery-write", []s3Grant{
+			{GranteeURI: s3LogDeliveryURI, Permission: "WRITE"},
+			{GranteeURI: s3LogDeliveryURI, Permission: "READ_ACP"},
+		}, true},
+		{"bucket-owner-read", nil, false},
+		{"bucket-owner-full-control", nil, false},
+		{"public", nil, false},
+	}
+
+	for _, tc := range cases {
+		grants, ok := cannedACLToGrants(tc.ACL)
+		if ok != tc.Ok || !reflect.DeepEqual(grants, tc.Grants) {
+			t.Fatalf("%s: bad: %#v, %t", tc.ACL, grants, ok)
+		}
+
+		if !ok {
+			continue
+		}
+
+		// Every canned ACL must map back to itself
+		p := &s3AccessControlPolicy{
+			OwnerID: "owner",
+			Grants: append([]s3Grant{
+				{GranteeID: "owner", Permission: "FULL_CONTROL"},
+			}, grants...),
+		}
+		if acl := grantsToCannedACL(p); acl != tc.ACL {
+			t.Fatalf("%s: round trip got %q", tc.ACL, acl)
+		}
+	}
+}
+
+func TestValidateS3CannedACL(t *testing.T) {
+	for _, v := range s3CannedACLs {
+		if _, es := validateS3CannedACL(v, "acl"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	if _, es := validateS3CannedACL("public", "acl"); len(es) == 0 {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestUpdateS3BucketLogDelivery(t *testing.T) {
+	current := `<AccessControlPolicy><Owner><ID>owner</ID></Owner><AccessControlList>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>` + s3AllUsersURI + `</URI></Grantee><Permission>READ</Permission></Grant>` +
+		`</AccessControlList></AccessControlPolicy>`
+
+	var puts []s3AccessControlPolicy
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["acl"]; !ok {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		if r.Method == "PUT" {
+			var p s3AccessControlPolicy
+			if err := xml.NewDecoder(r.Body).Decode(&p); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			puts = append(puts, p)
+			return
+		}
+
+		fmt.Fprint(w, current)
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	if err := updateS3BucketLogDelivery(conn, "bucket", "", true); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(puts) != 1 {
+		t.Fatalf("expected a single put, got %d", len(puts))
+	}
+
+	// The grants of the ACL are kept, and the log delivery ones added
+	p := puts[0]
+	if p.OwnerID != "owner" {
+		t.Fatalf("bad owner: %s", p.OwnerID)
+	}
+	expected := s3GrantsString([]s3Grant{
+		{GranteeID: "owner", Permission: "FULL_CONTROL"},
+		{GranteeURI: s3AllUsersURI, Permission: "READ"},
+		{GranteeURI: s3LogDeliveryURI, Permission: "WRITE"},
+		{GranteeURI: s3LogDeliveryURI, Permission: "READ_ACP"},
+	})
+	if actual := s3GrantsString(p.Grants); actual != expected {
+		t.Fatalf("bad grants: %s", actual)
+	}
+	if !hasS3LogDeliveryGrants(&p) {
+		t.Fatal("should have the log delivery grants")
+	}
+
+	// Already without the grants, there's nothing to put
+	puts = nil
+	if err := updateS3BucketLogDelivery(conn, "bucket", "", false); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(puts) != 0 {
+		t.Fatalf("expected no puts, got %d", len(puts))
+	}
+}
+
+func TestSetS3LogDeliveryGrants(t *testing.T) {
+	p := &s3AccessControlPolicy{
+		OwnerID: "owner",
+		Grants: []s3Grant{
+			{GranteeID: "owner", Permission: "FULL_CONTROL"},
+			{GranteeURI: s3LogDeliveryURI, Permission: "WRITE"},
+		},
+	}
+
+	// Only part of the grants isn't enough
+	if hasS3LogDeliveryGrants(p) {
+		t.Fatal("shouldn't have the log delivery grants")
+	}
+
+	if !setS3LogDeliveryGrants(p, true) {
+		t.Fatal("should change")
+	}
+	if !hasS3LogDeliveryGrants(p) || len(p.Grants) != 3 {
+		t.Fatalf("bad: %#v", p.Grants)
+	}
+	if setS3LogDeliveryGrants(p, true) {
+		t.Fatal("shouldn't change")
+	}
+
+	if !setS3LogDeliveryGrants(p, false) {
+		t.Fatal("should change")
+	}
+	if acl := grantsToCannedACL(p); acl != "private" {
+		t.Fatalf("bad: %s", acl)
+	}
+}
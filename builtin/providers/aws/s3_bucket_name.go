@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	s3BucketNameIPPattern    = regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+$`)
+	s3BucketNameCharsPattern = regexp.MustCompile(`^[a-z0-9.-]*$`)
+	s3BucketNameLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+// validateS3BucketName is the ValidateFunc of bucket names.
+func validateS3BucketName(v interface{}, k string) (ws []string, es []error) {
+	for _, err := range s3BucketNameErrors(v.(string), false) {
+		es = append(es, fmt.Errorf("%s: %s", k, err))
+	}
+
+	return
+}
+
+// s3BucketNameErrors checks a bucket name against the S3 naming rules, so
+// that a bad name is reported when planning rather than by the API. The
+// names of buckets used as websites or with transfer acceleration also
+// can't contain dots, since their TLS certificates don't cover them; pass
+// noDots to check for that too.
+func s3BucketNameErrors(name string, noDots bool) []error {
+	var es []error
+
+	if len(name) < 3 || len(name) > 63 {
+		es = append(es, fmt.Errorf(
+			"bucket name %q must be between 3 and 63 characters long, is %d",
+			name, len(name)))
+	}
+
+	if strings.ToLower(name) != name {
+		es = append(es, fmt.Errorf(
+			"bucket name %q can't contain uppercase letters", name))
+	}
+
+	if strings.Contains(name, "_") {
+		es = append(es, fmt.Errorf(
+			"bucket name %q can't contain underscores", name))
+	}
+
+	if !s3BucketNameCharsPattern.MatchString(strings.ToLower(name)) {
+		es = append(es, fmt.Errorf(
+			"bucket name %q can only contain lowercase letters, numbers, "+
+				"dots and hyphens", name))
+	}
+
+	if s3BucketNameIPPattern.MatchString(name) {
+		es = append(es, fmt.Errorf(
+			"bucket name %q can't be formatted as an IP address", name))
+	} else {
+		// Each dot separated label must be a valid DNS label
+		for _, label := range strings.Split(strings.ToLower(name), ".") {
+			if !s3BucketNameLabelPattern.MatchString(label) {
+				es = append(es, fmt.Errorf(
+					"bucket name %q must be a series of labels separated by "+
+						"single dots, each starting and ending with a letter "+
+						"or number, not %q", name, label))
+				break
+			}
+		}
+	}
+
+	if noDots && strings.Contains(name, ".") {
+		es = append(es, fmt.Errorf(
+			"bucket name %q can't contain dots when the bucket is used as a "+
+				"website or with transfer acceleration", name))
+	}
+
+	return es
+}
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestS3BucketNameErrors(t *testing.T) {
+	cases := []struct {
+		Name   string
+		NoDots bool
+		Err    string
+	}{
+		{"foo", false, ""},
+		{"tf-test-bucket-1234", false, ""},
+		{"www.example.com", false, ""},
+		{"a.b-c.d", false, ""},
+		{strings.Repeat("a", 63), false, ""},
+
+		{"ab", false, "between 3 and 63"},
+		{strings.Repeat("a", 64), false, "between 3 and 63"},
+		{"FooBar", false, "uppercase"},
+		{"foo_bar", false, "underscores"},
+		{"foo bar", false, "only contain"},
+		{"foo!bar", false, "only contain"},
+		{"192.168.5.4", false, "IP address"},
+		{"-foo", false, "labels"},
+		{"foo-", false, "labels"},
+		{"foo..bar", false, "labels"},
+		{".foo", false, "labels"},
+		{"foo.-bar", false, "labels"},
+
+		{"foo-bar", true, ""},
+		{"www.example.com", true, "dots"},
+	}
+
+	for _, tc := range cases {
+		es := s3BucketNameErrors(tc.Name, tc.NoDots)
+		if tc.Err == "" {
+			if len(es) > 0 {
+				t.Fatalf("%q: unexpected errors: %v", tc.Name, es)
+			}
+			continue
+		}
+
+		found := false
+		for _, err := range es {
+			if strings.Contains(err.Error(), tc.Err) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%q: expected an error containing %q, got %v", tc.Name, tc.Err, es)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// s3DeleteObjectsMax is the most objects a single DeleteObjects request
+// can delete.
+const s3DeleteObjectsMax = 1000
+
+// s3ObjectIdentifier is an object, or a version of one, as listed by S3
+// and as given to DeleteObjects.
+type s3ObjectIdentifier struct {
+	Key       string `xml:"Key"`
+	VersionId string `xml:"VersionId,omitempty"`
+}
+
+// s3ListMarker is where the next page of a listing starts.
+type s3ListMarker struct {
+	Key       string
+	VersionId string
+}
+
+// emptyS3Bucket deletes every object in the bucket, so that it can be
+// deleted itself. If the bucket is versioned every version of every
+// object is deleted, along with the delete markers. Objects are deleted
+// in batches of up to s3DeleteObjectsMax, concurrency batches at a time,
+// while the rest are still being listed. It gives up once the deadline
+// has passed.
+func emptyS3Bucket(
+	conn *s3Conn,
+	bucket, owner string,
+	versioned bool,
+	concurrency int,
+	deadline time.Time) error {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var errs []string
+	deleted := 0
+	batches := make(chan []s3ObjectIdentifier)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				err := deleteS3Objects(conn, bucket, owner, batch)
+
+				lock.Lock()
+				if err != nil {
+					errs = append(errs, err.Error())
+				} else {
+					deleted += len(batch)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	failed := func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(errs) > 0
+	}
+
+	var listErr error
+	var pending []s3ObjectIdentifier
+	var marker s3ListMarker
+	for !failed() {
+		if time.Now().After(deadline) {
+			listErr = fmt.Errorf("timeout while emptying the bucket")
+			break
+		}
+
+		objects, next, err := listS3ObjectsPage(conn, bucket, owner, versioned, marker)
+		if err != nil {
+			listErr = err
+			break
+		}
+
+		pending = append(pending, objects...)
+		for len(pending) >= s3DeleteObjectsMax {
+			batches <- pending[:s3DeleteObjectsMax]
+			pending = pending[s3DeleteObjectsMax:]
+		}
+
+		if next == nil {
+			if len(pending) > 0 {
+				batches <- pending
+			}
+			break
+		}
+		marker = *next
+	}
+	close(batches)
+	wg.Wait()
+
+	log.Printf("[DEBUG] S3 bucket %s: deleted %d objects", bucket, deleted)
+	if listErr != nil {
+		errs = append(errs, listErr.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"Error emptying S3 bucket %s, %d objects were deleted:\n\n%s",
+			bucket, deleted, strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// listS3ObjectsPage lists a page of the objects in a bucket, starting at
+// the marker, or of their versions and delete markers if versioned is
+// set. It returns where the next page starts, or nil if this was the last.
+func listS3ObjectsPage(
+	conn *s3Conn,
+	bucket, owner string,
+	versioned bool,
+	marker s3ListMarker) ([]s3ObjectIdentifier, *s3ListMarker, error) {
+	headers := s3ExpectedBucketOwnerHeaders(owner)
+
+	if !versioned {
+		query := map[string]string{}
+		if marker.Key != "" {
+			query["marker"] = marker.Key
+		}
+
+		var resp struct {
+			Contents    []s3ObjectIdentifier `xml:"Contents"`
+			IsTruncated bool                 `xml:"IsTruncated"`
+		}
+		if err := s3ObjectRequest(conn, "GET", bucket, "", query, headers, nil, &resp); err != nil {
+			return nil, nil, fmt.Errorf("Error listing objects: %s", err)
+		}
+
+		// Without a delimiter S3 doesn't give the next marker, which is
+		// the last key listed
+		if !resp.IsTruncated || len(resp.Contents) == 0 {
+			return resp.Contents, nil, nil
+		}
+		last := resp.Contents[len(resp.Contents)-1]
+		return resp.Contents, &s3ListMarker{Key: last.Key}, nil
+	}
+
+	query := map[string]string{"versions": ""}
+	if marker.Key != "" {
+		query["key-marker"] = marker.Key
+		query["version-id-marker"] = marker.VersionId
+	}
+
+	var resp struct {
+		Versions            []s3ObjectIdentifier `xml:"Version"`
+		DeleteMarkers       []s3ObjectIdentifier `xml:"DeleteMarker"`
+		IsTruncated         bool                 `xml:"IsTruncated"`
+		NextKeyMarker       string               `xml:"NextKeyMarker"`
+		NextVersionIdMarker string               `xml:"NextVersionIdMarker"`
+	}
+	if err := s3ObjectRequest(conn, "GET", bucket, "", query, headers, nil, &resp); err != nil {
+		return nil, nil, fmt.Errorf("Error listing object versions: %s", err)
+	}
+
+	objects := append(resp.Versions, resp.DeleteMarkers...)
+	if !resp.IsTruncated {
+		return objects, nil, nil
+	}
+	return objects, &s3ListMarker{
+		Key:       resp.NextKeyMarker,
+		VersionId: resp.NextVersionIdMarker,
+	}, nil
+}
+
+// deleteS3Objects deletes the objects with a single DeleteObjects request,
+// retrying while S3 asks us to slow down. Objects S3 failed to delete are
+// returned as an error.
+func deleteS3Objects(conn *s3Conn, bucket, owner string, objects []s3ObjectIdentifier) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name             `xml:"Delete"`
+		Quiet   bool                 `xml:"Quiet"`
+		Objects []s3ObjectIdentifier `xml:"Object"`
+	}{Quiet: true, Objects: objects})
+	if err != nil {
+		return err
+	}
+
+	// Only the objects that couldn't be deleted are listed
+	var resp struct {
+		Errors []struct {
+			Key       string `xml:"Key"`
+			VersionId string `xml:"VersionId"`
+			Code      string `xml:"Code"`
+			Message   string `xml:"Message"`
+		} `xml:"Error"`
+	}
+
+	log.Printf("[DEBUG] S3 bucket %s: deleting %d objects", bucket, len(objects))
+	query := map[string]string{"delete": ""}
+	err = resource.Retry(2*time.Minute, retryOnAwsCodes([]string{"SlowDown"}, func() error {
+		return s3ObjectRequest(
+			conn, "POST", bucket, "", query, s3ExpectedBucketOwnerHeaders(owner), body, &resp)
+	}))
+	if err != nil {
+		return fmt.Errorf("Error deleting objects: %s", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		e := resp.Errors[0]
+		return fmt.Errorf(
+			"Error deleting %d objects, including %s (version %q): %s: %s",
+			len(resp.Errors), e.Key, e.VersionId, e.Code, e.Message)
+	}
+
+	return nil
+}
+
+func validateS3DeleteConcurrency(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < 1 {
+		es = append(es, fmt.Errorf("%s must be at least 1, got %d", k, v))
+	}
+
+	return
+}
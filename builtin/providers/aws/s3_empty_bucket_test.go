@@ -0,0 +1,243 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
+)
+
+// testS3ListServer is an S3 that lists the objects it holds in pages of
+// pageSize, and deletes them with DeleteObjects.
+type testS3ListServer struct {
+	t        *testing.T
+	pageSize int
+
+	sync.Mutex
+	objects  map[s3ObjectIdentifier]bool
+	batches  []int
+	inFlight int
+	maxFlown int
+}
+
+func (s *testS3ListServer) sorted() []s3ObjectIdentifier {
+	result := make([]s3ObjectIdentifier, 0, len(s.objects))
+	for o := range s.objects {
+		result = append(result, o)
+	}
+	sort.Sort(s3ObjectIdentifiers(result))
+	return result
+}
+
+func (s *testS3ListServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.Method == "POST" {
+		s.delete(w, r)
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	// Objects after the marker, in the order S3 lists them
+	var page []s3ObjectIdentifier
+	_, versions := q["versions"]
+	marker := s3ObjectIdentifier{Key: q.Get("marker")}
+	if versions {
+		marker = s3ObjectIdentifier{Key: q.Get("key-marker"), VersionId: q.Get("version-id-marker")}
+	}
+	for _, o := range s.sorted() {
+		if marker.Key == "" || s3ObjectIdentifiers([]s3ObjectIdentifier{marker, o}).Less(0, 1) {
+			page = append(page, o)
+		}
+	}
+	truncated := len(page) > s.pageSize
+	if truncated {
+		page = page[:s.pageSize]
+	}
+
+	if !versions {
+		fmt.Fprintf(w, "<ListBucketResult><IsTruncated>%t</IsTruncated>", truncated)
+		for _, o := range page {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", o.Key)
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+		return
+	}
+
+	fmt.Fprintf(w, "<ListVersionsResult><IsTruncated>%t</IsTruncated>", truncated)
+	if truncated {
+		last := page[len(page)-1]
+		fmt.Fprintf(w, "<NextKeyMarker>%s</NextKeyMarker>", last.Key)
+		fmt.Fprintf(w, "<NextVersionIdMarker>%s</NextVersionIdMarker>", last.VersionId)
+	}
+	for i, o := range page {
+		// Every other version is a delete marker
+		element := "Version"
+		if i%2 == 1 {
+			element = "DeleteMarker"
+		}
+		fmt.Fprintf(w, "<%[1]s><Key>%s</Key><VersionId>%s</VersionId></%[1]s>",
+			element, o.Key, o.VersionId)
+	}
+	fmt.Fprint(w, "</ListVersionsResult>")
+}
+
+func (s *testS3ListServer) delete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["delete"]; !ok {
+		s.t.Errorf("bad delete query: %s", r.URL.RawQuery)
+	}
+
+	var req struct {
+		Quiet   bool                 `xml:"Quiet"`
+		Objects []s3ObjectIdentifier `xml:"Object"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("err: %s", err)
+	}
+
+	s.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxFlown {
+		s.maxFlown = s.inFlight
+	}
+	s.Unlock()
+
+	// Give other batches the chance to overlap with this one
+	time.Sleep(10 * time.Millisecond)
+
+	s.Lock()
+	defer s.Unlock()
+	s.inFlight--
+	s.batches = append(s.batches, len(req.Objects))
+	for _, o := range req.Objects {
+		delete(s.objects, o)
+	}
+	fmt.Fprint(w, "<DeleteResult></DeleteResult>")
+}
+
+type s3ObjectIdentifiers []s3ObjectIdentifier
+
+func (s s3ObjectIdentifiers) Len() int      { return len(s) }
+func (s s3ObjectIdentifiers) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s s3ObjectIdentifiers) Less(i, j int) bool {
+	if s[i].Key != s[j].Key {
+		return s[i].Key < s[j].Key
+	}
+	return s[i].VersionId < s[j].VersionId
+}
+
+func TestEmptyS3Bucket(t *testing.T) {
+	server := &testS3ListServer{
+		t:        t,
+		pageSize: 100,
+		objects:  make(map[s3ObjectIdentifier]bool),
+	}
+	for i := 0; i < 250; i++ {
+		server.objects[s3ObjectIdentifier{Key: fmt.Sprintf("key-%04d", i)}] = true
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	err := emptyS3Bucket(conn, "bucket", "", false, 4, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(server.objects) != 0 {
+		t.Fatalf("%d objects left", len(server.objects))
+	}
+
+	// The pages are put together into a single batch
+	if len(server.batches) != 1 || server.batches[0] != 250 {
+		t.Fatalf("bad batches: %v", server.batches)
+	}
+}
+
+func TestEmptyS3Bucket_versioned(t *testing.T) {
+	server := &testS3ListServer{
+		t:        t,
+		pageSize: 1000,
+		objects:  make(map[s3ObjectIdentifier]bool),
+	}
+	for i := 0; i < 1200; i++ {
+		for _, v := range []string{"a", "b"} {
+			server.objects[s3ObjectIdentifier{
+				Key:       fmt.Sprintf("key-%04d", i),
+				VersionId: v,
+			}] = true
+		}
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	err := emptyS3Bucket(conn, "bucket", "", true, 2, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(server.objects) != 0 {
+		t.Fatalf("%d versions left", len(server.objects))
+	}
+
+	total := 0
+	for _, n := range server.batches {
+		if n > s3DeleteObjectsMax {
+			t.Fatalf("batch too big: %v", server.batches)
+		}
+		total += n
+	}
+	if len(server.batches) != 3 || total != 2400 {
+		t.Fatalf("bad batches: %v", server.batches)
+	}
+	if server.maxFlown > 2 {
+		t.Fatalf("%d deletes at once, expected at most 2", server.maxFlown)
+	}
+}
+
+func TestEmptyS3Bucket_deleteErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			fmt.Fprint(w, `<DeleteResult><Error><Key>locked</Key><VersionId>a</VersionId>`+
+				`<Code>AccessDenied</Code><Message>Access Denied</Message></Error></DeleteResult>`)
+			return
+		}
+
+		fmt.Fprint(w, `<ListVersionsResult><IsTruncated>false</IsTruncated>`+
+			`<Version><Key>locked</Key><VersionId>a</VersionId></Version></ListVersionsResult>`)
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	err := emptyS3Bucket(conn, "bucket", "", true, 1, time.Now().Add(time.Minute))
+	if err == nil {
+		t.Fatal("should error")
+	}
+}
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/mitchellh/goamz/s3"
+)
+
+type s3LifecycleConfiguration struct {
+	XMLName xml.Name          `xml:"LifecycleConfiguration"`
+	Rules   []s3LifecycleRule `xml:"Rule"`
+}
+
+type s3LifecycleRule struct {
+	ID                             string                           `xml:"ID,omitempty"`
+	Prefix                         string                           `xml:"Prefix"`
+	Status                         string                           `xml:"Status"`
+	Expiration                     *s3LifecycleExpiration           `xml:"Expiration,omitempty"`
+	AbortIncompleteMultipartUpload *s3LifecycleAbortMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+type s3LifecycleExpiration struct {
+	Days int `xml:"Days"`
+}
+
+type s3LifecycleAbortMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+// getS3BucketLifecycle fetches the lifecycle rules of a bucket. A bucket
+// without a lifecycle configuration has no rules.
+func getS3BucketLifecycle(conn *s3Conn, bucket, owner string) ([]s3LifecycleRule, error) {
+	var c s3LifecycleConfiguration
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "lifecycle", nil, &c)
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); ok && s3err.Code == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return c.Rules, nil
+}
+
+// putS3BucketLifecycle replaces the lifecycle rules of a bucket. S3
+// doesn't accept an empty configuration, so no rules deletes it instead.
+func putS3BucketLifecycle(conn *s3Conn, bucket, owner string, rules []s3LifecycleRule) error {
+	if len(rules) == 0 {
+		return s3SubresourceRequest(conn, "DELETE", bucket, owner, "lifecycle", nil, nil)
+	}
+
+	body, err := xml.Marshal(&s3LifecycleConfiguration{Rules: rules})
+	if err != nil {
+		return fmt.Errorf("Error encoding bucket lifecycle: %s", err)
+	}
+
+	return s3SubresourceRequest(conn, "PUT", bucket, owner, "lifecycle", body, nil)
+}
+
+// Takes the result of flatmap.Expand for an array of lifecycle rules and
+// returns the rules to send to S3
+func expandS3LifecycleRules(configured []interface{}) []s3LifecycleRule {
+	rules := make([]s3LifecycleRule, 0, len(configured))
+	for _, raw := range configured {
+		data := raw.(map[string]interface{})
+
+		rule := s3LifecycleRule{
+			ID:     data["id"].(string),
+			Prefix: data["prefix"].(string),
+			Status: "Disabled",
+		}
+		if data["enabled"].(bool) {
+			rule.Status = "Enabled"
+		}
+		if v := data["expiration_days"].(int); v > 0 {
+			rule.Expiration = &s3LifecycleExpiration{Days: v}
+		}
+		if v := data["abort_incomplete_multipart_upload_days"].(int); v > 0 {
+			rule.AbortIncompleteMultipartUpload = &s3LifecycleAbortMultipartUpload{
+				DaysAfterInitiation: v,
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// Flattens lifecycle rules returned by S3 into a []map[string]interface{}
+func flattenS3LifecycleRules(list []s3LifecycleRule) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, rule := range list {
+		r := map[string]interface{}{
+			"id":                                     rule.ID,
+			"prefix":                                 rule.Prefix,
+			"enabled":                                rule.Status == "Enabled",
+			"expiration_days":                        0,
+			"abort_incomplete_multipart_upload_days": 0,
+		}
+		if rule.Expiration != nil {
+			r["expiration_days"] = rule.Expiration.Days
+		}
+		if rule.AbortIncompleteMultipartUpload != nil {
+			r["abort_incomplete_multipart_upload_days"] =
+				rule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+func validateS3LifecycleDays(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < 1 {
+		es = append(es, fmt.Errorf("%s must be a positive number of days", k))
+	}
+
+	return
+}
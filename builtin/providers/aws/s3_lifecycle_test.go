@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandS3LifecycleRules(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"id":                                     "logs",
+			"prefix":                                 "logs/",
+			"enabled":                                true,
+			"expiration_days":                        90,
+			"abort_incomplete_multipart_upload_days": 7,
+		},
+		map[string]interface{}{
+			"id":                                     "",
+			"prefix":                                 "tmp/",
+			"enabled":                                false,
+			"expiration_days":                        0,
+			"abort_incomplete_multipart_upload_days": 1,
+		},
+	}
+
+	expected := []s3LifecycleRule{
+		s3LifecycleRule{
+			ID:         "logs",
+			Prefix:     "logs/",
+			Status:     "Enabled",
+			Expiration: &s3LifecycleExpiration{Days: 90},
+			AbortIncompleteMultipartUpload: &s3LifecycleAbortMultipartUpload{
+				DaysAfterInitiation: 7,
+			},
+		},
+		s3LifecycleRule{
+			Prefix: "tmp/",
+			Status: "Disabled",
+			AbortIncompleteMultipartUpload: &s3LifecycleAbortMultipartUpload{
+				DaysAfterInitiation: 1,
+			},
+		},
+	}
+
+	actual := expandS3LifecycleRules(expanded)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual,
+			expected)
+	}
+
+	// Rules without an expiration must not send an empty one
+	body, err := xml.Marshal(&s3LifecycleConfiguration{Rules: actual[1:]})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(string(body), "<Expiration>") {
+		t.Fatalf("bad: %s", body)
+	}
+	if !strings.Contains(string(body), "<AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload>") {
+		t.Fatalf("bad: %s", body)
+	}
+}
+
+func TestFlattenS3LifecycleRules(t *testing.T) {
+	body := `<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Rule>
+    <ID>logs</ID>
+    <Prefix>logs/</Prefix>
+    <Status>Enabled</Status>
+    <Expiration>
+      <Days>90</Days>
+    </Expiration>
+    <AbortIncompleteMultipartUpload>
+      <DaysAfterInitiation>7</DaysAfterInitiation>
+    </AbortIncompleteMultipartUpload>
+  </Rule>
+  <Rule>
+    <ID>uploads</ID>
+    <Prefix>uploads/</Prefix>
+    <Status>Disabled</Status>
+    <AbortIncompleteMultipartUpload>
+      <DaysAfterInitiation>3</DaysAfterInitiation>
+    </AbortIncompleteMultipartUpload>
+  </Rule>
+</LifecycleConfiguration>`
+
+	var c s3LifecycleConfiguration
+	if err := xml.Unmarshal([]byte(body), &c); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []map[string]interface{}{
+		map[string]interface{}{
+			"id":                                     "logs",
+			"prefix":                                 "logs/",
+			"enabled":                                true,
+			"expiration_days":                        90,
+			"abort_incomplete_multipart_upload_days": 7,
+		},
+		map[string]interface{}{
+			"id":                                     "uploads",
+			"prefix":                                 "uploads/",
+			"enabled":                                false,
+			"expiration_days":                        0,
+			"abort_incomplete_multipart_upload_days": 3,
+		},
+	}
+
+	actual := flattenS3LifecycleRules(c.Rules)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual,
+			expected)
+	}
+}
+
+func TestValidateS3LifecycleDays(t *testing.T) {
+	for _, v := range []int{0, -1} {
+		if _, es := validateS3LifecycleDays(v, "expiration_days"); len(es) == 0 {
+			t.Fatalf("%d: expected an error", v)
+		}
+	}
+
+	if _, es := validateS3LifecycleDays(7, "expiration_days"); len(es) > 0 {
+		t.Fatalf("bad: %#v", es)
+	}
+}
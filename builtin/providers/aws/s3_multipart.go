@@ -0,0 +1,219 @@
+package aws
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/goamz/s3"
+)
+
+const (
+	// s3MultipartThreshold is the size above which object sources are
+	// uploaded in parts rather than with a single put.
+	s3MultipartThreshold = 100 * 1024 * 1024
+
+	// s3MinPartSize is the smallest part S3 accepts, except for the last.
+	s3MinPartSize = 5 * 1024 * 1024
+
+	// s3MaxParts is the most parts an upload can have.
+	s3MaxParts = 10000
+)
+
+// s3CompletedPart is a part of a multipart upload, as given to
+// CompleteMultipartUpload.
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// putS3ObjectMultipart uploads size bytes of r as the object, in parts of
+// partSize bytes uploaded concurrency at a time. The headers are the ones
+// that would be given to a single put, such as the content type, ACL and
+// metadata. It returns the ETag S3 gives the object, which for a multipart
+// upload is derived from the MD5 of every part rather than of the whole
+// content.
+//
+// If any part fails the upload is aborted, so that its parts don't linger
+// in the bucket.
+func putS3ObjectMultipart(
+	conn *s3Conn,
+	bucket, key string,
+	r io.ReaderAt,
+	size, partSize int64,
+	concurrency int,
+	headers map[string][]string) (string, error) {
+	// The part size is raised if needed to stay within the number of
+	// parts S3 allows
+	if min := (size + s3MaxParts - 1) / s3MaxParts; partSize < min {
+		partSize = min
+	}
+	count := int((size + partSize - 1) / partSize)
+
+	var initResp struct {
+		UploadId string `xml:"UploadId"`
+	}
+	err := s3ObjectRequest(
+		conn, "POST", bucket, key, map[string]string{"uploads": ""}, headers, nil, &initResp)
+	if err != nil {
+		return "", fmt.Errorf("Error starting multipart upload: %s", err)
+	}
+	uploadId := initResp.UploadId
+	log.Printf(
+		"[DEBUG] S3 multipart upload %s of %s in bucket %s: %d parts of %d bytes",
+		uploadId, key, bucket, count, partSize)
+
+	parts := make([]s3CompletedPart, count)
+	sums := make([][]byte, count)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var errs []string
+	numbers := make(chan int)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				offset := int64(n) * partSize
+				length := partSize
+				if offset+length > size {
+					length = size - offset
+				}
+
+				etag, sum, err := putS3ObjectPart(
+					conn, bucket, key, uploadId, n+1, io.NewSectionReader(r, offset, length))
+				if err != nil {
+					lock.Lock()
+					errs = append(errs, fmt.Sprintf("part %d: %s", n+1, err))
+					lock.Unlock()
+					continue
+				}
+
+				parts[n] = s3CompletedPart{PartNumber: n + 1, ETag: etag}
+				sums[n] = sum
+			}
+		}()
+	}
+	for n := 0; n < count; n++ {
+		numbers <- n
+	}
+	close(numbers)
+	wg.Wait()
+
+	if len(errs) == 0 {
+		err = completeS3MultipartUpload(conn, bucket, key, uploadId, parts)
+	} else {
+		err = fmt.Errorf("Error uploading parts:\n\n%s", strings.Join(errs, "\n"))
+	}
+	if err != nil {
+		log.Printf("[DEBUG] S3 abort multipart upload %s", uploadId)
+		query := map[string]string{"uploadId": uploadId}
+		if aerr := s3ObjectRequest(conn, "DELETE", bucket, key, query, nil, nil, nil); aerr != nil {
+			log.Printf("[WARN] Error aborting S3 multipart upload %s: %s", uploadId, aerr)
+		}
+
+		return "", err
+	}
+
+	return s3MultipartETag(sums), nil
+}
+
+// putS3ObjectPart uploads a single part, returning its ETag and the MD5
+// of its content.
+func putS3ObjectPart(
+	conn *s3Conn,
+	bucket, key, uploadId string,
+	number int,
+	r io.Reader) (string, []byte, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := md5.Sum(body)
+
+	query := map[string]string{
+		"partNumber": strconv.Itoa(number),
+		"uploadId":   uploadId,
+	}
+	headers := map[string][]string{
+		"Content-Type": []string{"application/octet-stream"},
+	}
+
+	var respHeaders http.Header
+	err = s3ObjectRequest(conn, "PUT", bucket, key, query, headers, body, &respHeaders)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return respHeaders.Get("ETag"), sum[:], nil
+}
+
+// completeS3MultipartUpload assembles the uploaded parts into the object.
+func completeS3MultipartUpload(
+	conn *s3Conn, bucket, key, uploadId string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	// S3 can fail the request after it has already answered 200, in which
+	// case the body is an error instead of the result
+	var resp struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	query := map[string]string{"uploadId": uploadId}
+	if err := s3ObjectRequest(conn, "POST", bucket, key, query, nil, body, &resp); err != nil {
+		return fmt.Errorf("Error completing multipart upload: %s", err)
+	}
+	if resp.Code != "" {
+		return fmt.Errorf("Error completing multipart upload: %s", &s3.Error{
+			StatusCode: 200,
+			Code:       resp.Code,
+			Message:    resp.Message,
+		})
+	}
+
+	return nil
+}
+
+// s3MultipartETag returns the ETag S3 gives an object uploaded in parts
+// with the given MD5 sums: the MD5 of the sums, followed by the number of
+// parts.
+func s3MultipartETag(sums [][]byte) string {
+	h := md5.New()
+	for _, sum := range sums {
+		h.Write(sum)
+	}
+
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(h.Sum(nil)), len(sums))
+}
+
+func validateS3MultipartPartSize(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < s3MinPartSize {
+		es = append(es, fmt.Errorf(
+			"%s must be at least %d bytes, got %d", k, s3MinPartSize, v))
+	}
+
+	return
+}
+
+func validateS3MultipartConcurrency(v interface{}, k string) (ws []string, es []error) {
+	if v.(int) < 1 {
+		es = append(es, fmt.Errorf("%s must be at least 1, got %d", k, v))
+	}
+
+	return
+}
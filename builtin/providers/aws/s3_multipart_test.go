@@ -0,0 +1,163 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
+)
+
+// testS3MultipartServer is an S3 that only knows multipart uploads, and
+// fails the parts numbered in failParts.
+type testS3MultipartServer struct {
+	t         *testing.T
+	failParts map[int]bool
+
+	sync.Mutex
+	parts   map[int][]byte
+	headers http.Header
+	object  []byte
+	aborted bool
+}
+
+func (s *testS3MultipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == "POST" && q.Get("uploadId") == "":
+		s.headers = r.Header
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>abc</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == "PUT":
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		if q.Get("uploadId") != "abc" || n < 1 {
+			s.t.Errorf("bad part query: %s", r.URL.RawQuery)
+		}
+		if s.failParts[n] {
+			w.WriteHeader(500)
+			fmt.Fprint(w, `<Error><Code>InternalError</Code><Message>oops</Message></Error>`)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		s.parts[n] = body
+		sum := md5.Sum(body)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case r.Method == "POST":
+		var complete struct {
+			Parts []s3CompletedPart `xml:"Part"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+			s.t.Errorf("err: %s", err)
+		}
+		for i, p := range complete.Parts {
+			if p.PartNumber != i+1 {
+				s.t.Errorf("bad part order: %#v", complete.Parts)
+			}
+			s.object = append(s.object, s.parts[p.PartNumber]...)
+		}
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><ETag>"foo"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == "DELETE":
+		s.aborted = true
+		w.WriteHeader(204)
+	}
+}
+
+func TestPutS3ObjectMultipart(t *testing.T) {
+	server := &testS3MultipartServer{t: t, parts: make(map[int][]byte)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	content := bytes.Repeat([]byte("0123456789"), (2*s3MinPartSize+1024)/10)
+	headers := map[string][]string{
+		"Content-Type": []string{"text/plain"},
+		"X-Amz-Acl":    []string{"public-read"},
+	}
+
+	etag, err := putS3ObjectMultipart(
+		conn, "bucket", "key", bytes.NewReader(content),
+		int64(len(content)), s3MinPartSize, 2, headers)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(server.parts) != 3 {
+		t.Fatalf("bad parts: %d", len(server.parts))
+	}
+	if !bytes.Equal(server.object, content) {
+		t.Fatal("uploaded object doesn't match the content")
+	}
+	if server.headers.Get("Content-Type") != "text/plain" || server.headers.Get("X-Amz-Acl") != "public-read" {
+		t.Fatalf("bad headers: %#v", server.headers)
+	}
+
+	var sums []byte
+	for n := 1; n <= 3; n++ {
+		sum := md5.Sum(server.parts[n])
+		sums = append(sums, sum[:]...)
+	}
+	expected := md5.Sum(sums)
+	if etag != hex.EncodeToString(expected[:])+"-3" {
+		t.Fatalf("bad etag: %s", etag)
+	}
+}
+
+func TestPutS3ObjectMultipart_failedPart(t *testing.T) {
+	server := &testS3MultipartServer{
+		t:         t,
+		parts:     make(map[int][]byte),
+		failParts: map[int]bool{2: true},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	content := make([]byte, 2*s3MinPartSize+1)
+	_, err := putS3ObjectMultipart(
+		conn, "bucket", "key", bytes.NewReader(content),
+		int64(len(content)), s3MinPartSize, 4, nil)
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	if !server.aborted {
+		t.Fatal("upload wasn't aborted")
+	}
+	if server.object != nil {
+		t.Fatal("upload was completed")
+	}
+}
+
+func TestS3MultipartETag(t *testing.T) {
+	// The ETag of a two part upload of "foo" and "bar"
+	foo := md5.Sum([]byte("foo"))
+	bar := md5.Sum([]byte("bar"))
+	etag := s3MultipartETag([][]byte{foo[:], bar[:]})
+	if etag != "0105fcbc9eea8193de8e1834677b6c6b-2" {
+		t.Fatalf("bad: %s", etag)
+	}
+}
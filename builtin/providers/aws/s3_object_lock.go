@@ -0,0 +1,190 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/s3"
+)
+
+type s3ObjectLockConfiguration struct {
+	XMLName           xml.Name          `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string            `xml:"ObjectLockEnabled"`
+	Rule              *s3ObjectLockRule `xml:"Rule,omitempty"`
+}
+
+type s3ObjectLockRule struct {
+	DefaultRetention s3ObjectLockRetention `xml:"DefaultRetention"`
+}
+
+type s3ObjectLockRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+// s3CreateBucket creates a bucket with the canned ACL. Object lock can
+// only be enabled while creating the bucket, with a header goamz doesn't
+// send, so a bucket with it is created by a request built here instead.
+// S3 turns on versioning for it too.
+func s3CreateBucket(conn *s3Conn, bucket, acl string, objectLock bool) error {
+	if !objectLock {
+		return conn.Bucket(bucket).PutBucket(s3.ACL(acl))
+	}
+
+	// Like goamz, only regions that need a location constraint get one
+	var body []byte
+	if conn.Region.S3LocationConstraint {
+		body = []byte(fmt.Sprintf(
+			"<CreateBucketConfiguration><LocationConstraint>%s"+
+				"</LocationConstraint></CreateBucketConfiguration>",
+			conn.Region.Name))
+	}
+
+	headers := map[string][]string{
+		"X-Amz-Acl":                        []string{acl},
+		"X-Amz-Bucket-Object-Lock-Enabled": []string{"true"},
+	}
+	return s3ObjectRequest(conn, "PUT", bucket, "", nil, headers, body, nil)
+}
+
+// getS3BucketObjectLock fetches the object lock configuration of a bucket.
+// It's nil for a bucket created without object lock.
+func getS3BucketObjectLock(
+	conn *s3Conn, bucket, owner string) (*s3ObjectLockConfiguration, error) {
+	var c s3ObjectLockConfiguration
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "object-lock", nil, &c)
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); ok && s3err.Code == "ObjectLockConfigurationNotFoundError" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// putS3BucketObjectLock replaces the default retention of a bucket with
+// object lock enabled. A nil rule removes it, so new objects aren't locked
+// unless they ask to be.
+func putS3BucketObjectLock(conn *s3Conn, bucket, owner string, rule *s3ObjectLockRule) error {
+	body, err := xml.Marshal(&s3ObjectLockConfiguration{
+		ObjectLockEnabled: "Enabled",
+		Rule:              rule,
+	})
+	if err != nil {
+		return fmt.Errorf("Error encoding bucket object lock: %s", err)
+	}
+
+	return s3SubresourceRequest(conn, "PUT", bucket, owner, "object-lock", body, nil)
+}
+
+// Takes the result of flatmap.Expand for the "rule" of an object lock
+// configuration and returns the default retention to send to S3, or nil
+// if there isn't one.
+func expandS3ObjectLockRule(configured []interface{}) *s3ObjectLockRule {
+	if len(configured) == 0 {
+		return nil
+	}
+
+	data := configured[0].(map[string]interface{})
+	return &s3ObjectLockRule{
+		DefaultRetention: s3ObjectLockRetention{
+			Mode:  data["mode"].(string),
+			Days:  data["days"].(int),
+			Years: data["years"].(int),
+		},
+	}
+}
+
+// Flattens an object lock configuration returned by S3 into the
+// "object_lock_configuration" block. A bucket without object lock has none.
+func flattenS3ObjectLock(c *s3ObjectLockConfiguration) []map[string]interface{} {
+	if c == nil || c.ObjectLockEnabled != "Enabled" {
+		return []map[string]interface{}{}
+	}
+
+	rules := []map[string]interface{}{}
+	if c.Rule != nil {
+		rules = append(rules, map[string]interface{}{
+			"mode":  c.Rule.DefaultRetention.Mode,
+			"days":  c.Rule.DefaultRetention.Days,
+			"years": c.Rule.DefaultRetention.Years,
+		})
+	}
+
+	return []map[string]interface{}{
+		map[string]interface{}{
+			"object_lock_enabled": true,
+			"rule":                rules,
+		},
+	}
+}
+
+func validateS3ObjectLockMode(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "GOVERNANCE", "COMPLIANCE":
+	default:
+		es = append(es, fmt.Errorf(
+			"%s must be GOVERNANCE or COMPLIANCE, got %q", k, v))
+	}
+
+	return
+}
+
+// validateS3ObjectLockConfig checks the object lock configuration in a
+// bucket's raw configuration: a default retention needs object lock
+// enabled, and a period of either days or years. S3 would otherwise only
+// reject it once the bucket has been created.
+func validateS3ObjectLockConfig(c *terraform.ResourceConfig) (es []error) {
+	const prefix = "object_lock_configuration.0."
+	if _, ok := c.Get(prefix + "rule.0"); !ok {
+		return
+	}
+	if c.IsComputed(prefix+"object_lock_enabled") ||
+		c.IsComputed(prefix+"rule.0.days") || c.IsComputed(prefix+"rule.0.years") {
+		return
+	}
+
+	var enabled bool
+	raw, _ := c.Get(prefix + "object_lock_enabled")
+	switch v := raw.(type) {
+	case bool:
+		enabled = v
+	case string:
+		enabled, _ = strconv.ParseBool(v)
+	}
+	if !enabled {
+		es = append(es, fmt.Errorf(
+			"object_lock_configuration: rule requires object_lock_enabled to be true"))
+	}
+
+	periods := 0
+	for _, k := range []string{"days", "years"} {
+		var n int
+		raw, _ := c.Get(prefix + "rule.0." + k)
+		switch v := raw.(type) {
+		case int:
+			n = v
+		case string:
+			n, _ = strconv.Atoi(v)
+		}
+
+		if n < 0 {
+			es = append(es, fmt.Errorf(
+				"object_lock_configuration: rule %s must be positive", k))
+		}
+		if n != 0 {
+			periods++
+		}
+	}
+	if periods != 1 {
+		es = append(es, fmt.Errorf(
+			"object_lock_configuration: rule needs exactly one of days or years"))
+	}
+
+	return
+}
@@ -0,0 +1,206 @@
+package aws
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestFlattenS3ObjectLock(t *testing.T) {
+	cases := []struct {
+		Body     string
+		Expected []map[string]interface{}
+	}{
+		// Object lock without a default retention
+		{
+			Body: `<ObjectLockConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <ObjectLockEnabled>Enabled</ObjectLockEnabled>
+</ObjectLockConfiguration>`,
+			Expected: []map[string]interface{}{
+				map[string]interface{}{
+					"object_lock_enabled": true,
+					"rule":                []map[string]interface{}{},
+				},
+			},
+		},
+
+		// Default retention in years
+		{
+			Body: `<ObjectLockConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <ObjectLockEnabled>Enabled</ObjectLockEnabled>
+  <Rule>
+    <DefaultRetention>
+      <Mode>COMPLIANCE</Mode>
+      <Years>7</Years>
+    </DefaultRetention>
+  </Rule>
+</ObjectLockConfiguration>`,
+			Expected: []map[string]interface{}{
+				map[string]interface{}{
+					"object_lock_enabled": true,
+					"rule": []map[string]interface{}{
+						map[string]interface{}{
+							"mode":  "COMPLIANCE",
+							"days":  0,
+							"years": 7,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		var c s3ObjectLockConfiguration
+		if err := xml.Unmarshal([]byte(tc.Body), &c); err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		actual := flattenS3ObjectLock(&c)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+
+	// A bucket created without object lock has no configuration
+	if actual := flattenS3ObjectLock(nil); len(actual) != 0 {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestExpandS3ObjectLockRule(t *testing.T) {
+	if rule := expandS3ObjectLockRule(nil); rule != nil {
+		t.Fatalf("bad: %#v", rule)
+	}
+
+	rule := expandS3ObjectLockRule([]interface{}{
+		map[string]interface{}{
+			"mode":  "GOVERNANCE",
+			"days":  30,
+			"years": 0,
+		},
+	})
+	body, err := xml.Marshal(&s3ObjectLockConfiguration{
+		ObjectLockEnabled: "Enabled",
+		Rule:              rule,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>` +
+		`<Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>30</Days>` +
+		`</DefaultRetention></Rule></ObjectLockConfiguration>`
+	if string(body) != expected {
+		t.Fatalf("bad: %s", body)
+	}
+}
+
+func TestValidateS3ObjectLockConfig(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{},
+			Err:    false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"object_lock_configuration": []map[string]interface{}{
+					map[string]interface{}{
+						"object_lock_enabled": true,
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"object_lock_configuration": []map[string]interface{}{
+					map[string]interface{}{
+						"object_lock_enabled": true,
+						"rule": []map[string]interface{}{
+							map[string]interface{}{
+								"mode": "COMPLIANCE",
+								"days": 30,
+							},
+						},
+					},
+				},
+			},
+			Err: false,
+		},
+
+		// A rule needs object lock enabled
+		{
+			Config: map[string]interface{}{
+				"object_lock_configuration": []map[string]interface{}{
+					map[string]interface{}{
+						"object_lock_enabled": false,
+						"rule": []map[string]interface{}{
+							map[string]interface{}{
+								"mode":  "COMPLIANCE",
+								"years": 1,
+							},
+						},
+					},
+				},
+			},
+			Err: true,
+		},
+
+		// Both days and years
+		{
+			Config: map[string]interface{}{
+				"object_lock_configuration": []map[string]interface{}{
+					map[string]interface{}{
+						"object_lock_enabled": true,
+						"rule": []map[string]interface{}{
+							map[string]interface{}{
+								"mode":  "GOVERNANCE",
+								"days":  30,
+								"years": 1,
+							},
+						},
+					},
+				},
+			},
+			Err: true,
+		},
+
+		// Neither days nor years
+		{
+			Config: map[string]interface{}{
+				"object_lock_configuration": []map[string]interface{}{
+					map[string]interface{}{
+						"object_lock_enabled": true,
+						"rule": []map[string]interface{}{
+							map[string]interface{}{
+								"mode": "GOVERNANCE",
+							},
+						},
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		es := validateS3ObjectLockConfig(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
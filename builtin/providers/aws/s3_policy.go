@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/mitchellh/goamz/s3"
+)
+
+// getS3BucketPolicy fetches the policy document of a bucket. A bucket
+// without a policy has an empty one.
+func getS3BucketPolicy(conn *s3Conn, bucket, owner string) (string, error) {
+	var policy []byte
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "policy", nil, &policy)
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); ok && s3err.Code == "NoSuchBucketPolicy" {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return string(policy), nil
+}
+
+// putS3BucketPolicy replaces the policy document of a bucket. An empty
+// policy deletes it instead.
+func putS3BucketPolicy(conn *s3Conn, bucket, owner, policy string) error {
+	if policy == "" {
+		return s3SubresourceRequest(conn, "DELETE", bucket, owner, "policy", nil, nil)
+	}
+
+	return s3SubresourceRequest(conn, "PUT", bucket, owner, "policy", []byte(policy), nil)
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"encoding/xml"
+)
+
+// getS3BucketRegion returns the region a bucket is in, normalized with
+// normalizeS3Region.
+func getS3BucketRegion(conn *s3Conn, bucket, owner string) (string, error) {
+	var location struct {
+		XMLName    xml.Name `xml:"LocationConstraint"`
+		Constraint string   `xml:",chardata"`
+	}
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "location", nil, &location)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeS3Region(location.Constraint), nil
+}
+
+// normalizeS3Region returns the name of the region for a bucket location
+// constraint. Buckets in us-east-1 have none, and the oldest buckets have
+// the legacy names "US" and "EU" of their regions.
+func normalizeS3Region(constraint string) string {
+	switch constraint {
+	case "", "US":
+		return "us-east-1"
+	case "EU":
+		return "eu-west-1"
+	}
+
+	return constraint
+}
+
+// s3RegionStateFunc is a StateFunc that stores a bucket region by its
+// name, so that any of its aliases can be configured without a diff.
+func s3RegionStateFunc(v interface{}) string {
+	return normalizeS3Region(v.(string))
+}
@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"testing"
+)
+
+func TestNormalizeS3Region(t *testing.T) {
+	cases := map[string]string{
+		"":               "us-east-1",
+		"US":             "us-east-1",
+		"us-east-1":      "us-east-1",
+		"EU":             "eu-west-1",
+		"eu-west-1":      "eu-west-1",
+		"us-west-2":      "us-west-2",
+		"ap-southeast-1": "ap-southeast-1",
+	}
+
+	for constraint, expected := range cases {
+		if actual := normalizeS3Region(constraint); actual != expected {
+			t.Fatalf("%q: expected %s, got %s", constraint, expected, actual)
+		}
+
+		// Every alias of a region is stored the same
+		if actual := s3RegionStateFunc(constraint); actual != expected {
+			t.Fatalf("%q: bad state: %s", constraint, actual)
+		}
+	}
+}
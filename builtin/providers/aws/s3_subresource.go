@@ -0,0 +1,212 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/goamz/s3"
+)
+
+// s3Conn is the goamz S3 connection together with the HTTP client that
+// the requests goamz doesn't have are sent with, so that they're retried
+// and identified like all the others.
+type s3Conn struct {
+	*s3.S3
+	httpClient *http.Client
+
+	// role, if set, signs the requests made here with the credentials of
+	// the assumed role instead of those of the goamz connection
+	role *assumedRole
+}
+
+// s3SubresourceRequest makes a signed request against a bucket
+// subresource such as "versioning" or "lifecycle". goamz doesn't expose
+// most of these, so the request is built and signed here using the
+// connection's credentials. If owner isn't empty, S3 refuses the request
+// unless the bucket belongs to that account.
+//
+// If body is non-nil it is sent as the request body. If out is non-nil the
+// XML response is decoded into it, unless out is a *[]byte, which receives
+// the raw response body instead. Any error response from S3 is returned
+// as an *s3.Error.
+func s3SubresourceRequest(
+	conn *s3Conn,
+	method, bucket, owner, subresource string,
+	body []byte,
+	out interface{}) error {
+	return s3ObjectSubresourceRequest(
+		conn, method, bucket, "", subresource, s3ExpectedBucketOwnerHeaders(owner), body, out)
+}
+
+// s3ExpectedBucketOwnerHeaders returns the headers that make S3 refuse a
+// request on a bucket owned by any account but owner, with a 403. An
+// empty owner adds none.
+func s3ExpectedBucketOwnerHeaders(owner string) map[string][]string {
+	if owner == "" {
+		return nil
+	}
+
+	return map[string][]string{
+		"X-Amz-Expected-Bucket-Owner": []string{owner},
+	}
+}
+
+// s3ExpectedBucketOwnerError explains the 403 S3 gives a request made
+// with an expected bucket owner that doesn't own the bucket. It's the
+// same error as for missing permissions, so both are given as causes.
+func s3ExpectedBucketOwnerError(err error, bucket, owner string) error {
+	s3err, ok := err.(*s3.Error)
+	if !ok || owner == "" || s3err.StatusCode != 403 {
+		return err
+	}
+
+	return fmt.Errorf(
+		"Access to S3 bucket %s denied. Either it isn't owned by the "+
+			"expected_bucket_owner %s or the credentials lack permission: %s",
+		bucket, owner, err)
+}
+
+// s3ObjectSubresourceRequest is s3SubresourceRequest for a subresource of
+// the object with the given key, such as its "acl". An empty key means
+// the bucket itself. Any extra headers, such as "X-Amz-Acl", are sent with
+// the request and included in its signature.
+func s3ObjectSubresourceRequest(
+	conn *s3Conn,
+	method, bucket, key, subresource string,
+	extraHeaders map[string][]string,
+	body []byte,
+	out interface{}) error {
+	query := map[string]string{subresource: ""}
+	return s3ObjectRequest(conn, method, bucket, key, query, extraHeaders, body, out)
+}
+
+// s3ObjectRequest is s3ObjectSubresourceRequest for requests that need
+// more than a single subresource, such as the parts of a multipart upload
+// with their "partNumber" and "uploadId". Parameters with an empty value
+// are sent without one, like subresources are. A Content-Type among the
+// extra headers replaces the one guessed from the body, and if out is an
+// *http.Header it receives the response headers instead of the body.
+func s3ObjectRequest(
+	conn *s3Conn,
+	method, bucket, key string,
+	query map[string]string,
+	extraHeaders map[string][]string,
+	body []byte,
+	out interface{}) error {
+	endpoint := conn.Region.S3Endpoint + "/" + bucket
+	if conn.Region.S3BucketEndpoint != "" {
+		endpoint = strings.Replace(
+			conn.Region.S3BucketEndpoint, "${bucket}", bucket, -1)
+	}
+
+	names := make([]string, 0, len(query))
+	for k := range query {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var rawQuery []string
+	params := make(map[string][]string, len(query))
+	for _, k := range names {
+		params[k] = []string{query[k]}
+		if query[k] == "" {
+			rawQuery = append(rawQuery, k)
+		} else {
+			rawQuery = append(rawQuery, k+"="+url.QueryEscape(query[k]))
+		}
+	}
+
+	path := (&url.URL{Path: "/" + key}).String()
+	u, err := url.Parse(endpoint + path + "?" + strings.Join(rawQuery, "&"))
+	if err != nil {
+		return err
+	}
+
+	headers := map[string][]string{
+		"Host": []string{u.Host},
+		"Date": []string{time.Now().In(time.UTC).Format(time.RFC1123)},
+	}
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
+
+	auth, err := signingAuth(conn.Auth, conn.role)
+	if err != nil {
+		return err
+	}
+	if auth.Token != "" {
+		headers["X-Amz-Security-Token"] = []string{auth.Token}
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		sum := md5.Sum(body)
+		headers["Content-Md5"] = []string{base64.StdEncoding.EncodeToString(sum[:])}
+		if _, ok := extraHeaders["Content-Type"]; !ok {
+			headers["Content-Type"] = []string{"application/xml"}
+			if len(body) > 0 && body[0] == '{' {
+				// Bucket policies are the one JSON subresource
+				headers["Content-Type"] = []string{"application/json"}
+			}
+		}
+		reqBody = bytes.NewReader(body)
+	}
+
+	s3.Sign(auth, method, "/"+bucket+"/"+key, params, headers)
+
+	req, err := http.NewRequest(method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = int64(len(body))
+	}
+
+	log.Printf("[DEBUG] S3 %s %s/%s?%s", method, bucket, key, u.RawQuery)
+	resp, err := conn.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s3err := &s3.Error{StatusCode: resp.StatusCode}
+		xml.NewDecoder(resp.Body).Decode(s3err)
+		if s3err.Message == "" {
+			s3err.Message = resp.Status
+		}
+		return s3err
+	}
+
+	if h, ok := out.(*http.Header); ok {
+		*h = resp.Header
+		return nil
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw, err = ioutil.ReadAll(resp.Body)
+		return err
+	}
+
+	if out != nil {
+		if err := xml.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("Error decoding S3 response: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func TestGetS3BucketVersioning_expectedBucketOwner(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		owner := r.Header.Get("X-Amz-Expected-Bucket-Owner")
+		if owner != "" && owner != "123456789012" {
+			w.WriteHeader(403)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+			return
+		}
+
+		fmt.Fprint(w, `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, owner := range []string{"", "123456789012"} {
+		v, err := getS3BucketVersioning(conn, "bucket", owner)
+		if err != nil {
+			t.Fatalf("%q: err: %s", owner, err)
+		}
+		if v.Status != "Enabled" {
+			t.Fatalf("%q: bad: %#v", owner, v)
+		}
+	}
+
+	_, err := getS3BucketVersioning(conn, "bucket", "210987654321")
+	if err == nil {
+		t.Fatal("should error")
+	}
+	err = s3ExpectedBucketOwnerError(err, "bucket", "210987654321")
+	if !strings.Contains(err.Error(), "expected_bucket_owner 210987654321") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Without an expected owner a 403 is just a 403
+	err = s3ExpectedBucketOwnerError(&s3.Error{StatusCode: 403}, "bucket", "")
+	if strings.Contains(err.Error(), "expected_bucket_owner") {
+		t.Fatalf("bad: %s", err)
+	}
+}
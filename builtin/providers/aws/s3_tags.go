@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// s3Tagging is the tag set of an object, as read and written through its
+// "tagging" subresource.
+type s3Tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Tags    []s3Tag  `xml:"TagSet>Tag"`
+}
+
+type s3Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// getS3ObjectTags fetches the tags of an object.
+func getS3ObjectTags(conn *s3Conn, bucket, key string) (map[string]string, error) {
+	var t s3Tagging
+	err := s3ObjectSubresourceRequest(conn, "GET", bucket, key, "tagging", nil, nil, &t)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(t.Tags))
+	for _, tag := range t.Tags {
+		result[tag.Key] = tag.Value
+	}
+
+	return result, nil
+}
+
+// putS3ObjectTags replaces the tags of an object. S3 takes the whole tag
+// set at once, so an empty map removes every tag.
+func putS3ObjectTags(conn *s3Conn, bucket, key string, tags map[string]interface{}) error {
+	if len(tags) == 0 {
+		return s3ObjectSubresourceRequest(conn, "DELETE", bucket, key, "tagging", nil, nil, nil)
+	}
+
+	// Sorted, so the same tags always make the same request
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var t s3Tagging
+	for _, k := range keys {
+		t.Tags = append(t.Tags, s3Tag{Key: k, Value: tags[k].(string)})
+	}
+
+	body, err := xml.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	return s3ObjectSubresourceRequest(conn, "PUT", bucket, key, "tagging", nil, body, nil)
+}
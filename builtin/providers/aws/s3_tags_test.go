@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/goamz/aws"
+	"github.com/mitchellh/goamz/s3"
+)
+
+func TestS3ObjectTags(t *testing.T) {
+	var tagging string
+	var deleted bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; !ok || r.URL.Path != "/bucket/key" {
+			t.Errorf("bad request: %s %s", r.Method, r.URL)
+		}
+
+		switch r.Method {
+		case "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			tagging = string(body)
+		case "DELETE":
+			deleted = true
+			w.WriteHeader(204)
+		case "GET":
+			fmt.Fprint(w, `<Tagging><TagSet>`+
+				`<Tag><Key>CostCenter</Key><Value>1234</Value></Tag>`+
+				`<Tag><Key>Name</Key><Value>foo</Value></Tag>`+
+				`</TagSet></Tagging>`)
+		}
+	}))
+	defer ts.Close()
+
+	conn := &s3Conn{
+		S3: s3.New(
+			aws.Auth{AccessKey: "foo", SecretKey: "bar"},
+			aws.Region{S3Endpoint: ts.URL}),
+		httpClient: http.DefaultClient,
+	}
+
+	err := putS3ObjectTags(conn, "bucket", "key", map[string]interface{}{
+		"Name":       "foo",
+		"CostCenter": "1234",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := `<Tagging><TagSet>` +
+		`<Tag><Key>CostCenter</Key><Value>1234</Value></Tag>` +
+		`<Tag><Key>Name</Key><Value>foo</Value></Tag>` +
+		`</TagSet></Tagging>`
+	if tagging != expected {
+		t.Fatalf("bad: %s", tagging)
+	}
+
+	tags, err := getS3ObjectTags(conn, "bucket", "key")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(tags, map[string]string{"Name": "foo", "CostCenter": "1234"}) {
+		t.Fatalf("bad: %#v", tags)
+	}
+
+	// No tags at all is a delete
+	if err := putS3ObjectTags(conn, "bucket", "key", map[string]interface{}{}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !deleted {
+		t.Fatal("tags should be deleted")
+	}
+}
@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"encoding/xml"
+)
+
+// s3VersioningConfiguration is the response of GET Bucket versioning.
+// Both fields are empty if versioning was never configured on the bucket.
+type s3VersioningConfiguration struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration"`
+	Status    string   `xml:"Status"`
+	MFADelete string   `xml:"MfaDelete"`
+}
+
+// getS3BucketVersioning fetches the versioning configuration of a bucket.
+func getS3BucketVersioning(
+	conn *s3Conn, bucket, owner string) (*s3VersioningConfiguration, error) {
+	var v s3VersioningConfiguration
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "versioning", nil, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// Flattens a versioning configuration into the "versioning" block. S3
+// reports MFA Delete as "Disabled" once it has been turned off and omits
+// it entirely if it was never set, so both read back as false.
+func flattenS3Versioning(v *s3VersioningConfiguration) []map[string]interface{} {
+	return []map[string]interface{}{
+		map[string]interface{}{
+			"enabled":    v.Status == "Enabled",
+			"mfa_delete": v.MFADelete == "Enabled",
+		},
+	}
+}
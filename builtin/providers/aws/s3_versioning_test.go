@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestFlattenS3Versioning(t *testing.T) {
+	cases := []struct {
+		Body     string
+		Expected []map[string]interface{}
+	}{
+		// Versioning never configured
+		{
+			Body: `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`,
+			Expected: []map[string]interface{}{
+				map[string]interface{}{
+					"enabled":    false,
+					"mfa_delete": false,
+				},
+			},
+		},
+
+		// MFA Delete explicitly disabled
+		{
+			Body: `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Status>Enabled</Status>
+  <MfaDelete>Disabled</MfaDelete>
+</VersioningConfiguration>`,
+			Expected: []map[string]interface{}{
+				map[string]interface{}{
+					"enabled":    true,
+					"mfa_delete": false,
+				},
+			},
+		},
+
+		// MFA Delete enabled
+		{
+			Body: `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Status>Suspended</Status>
+  <MfaDelete>Enabled</MfaDelete>
+</VersioningConfiguration>`,
+			Expected: []map[string]interface{}{
+				map[string]interface{}{
+					"enabled":    false,
+					"mfa_delete": true,
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		var v s3VersioningConfiguration
+		if err := xml.Unmarshal([]byte(tc.Body), &v); err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		actual := flattenS3Versioning(&v)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
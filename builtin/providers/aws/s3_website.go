@@ -0,0 +1,161 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/goamz/s3"
+)
+
+// s3WebsiteConfiguration either serves the bucket as a website, with an
+// index document, or redirects every request to another host.
+type s3WebsiteConfiguration struct {
+	XMLName               xml.Name                `xml:"WebsiteConfiguration"`
+	RedirectAllRequestsTo *s3WebsiteRedirect      `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *s3WebsiteIndexDocument `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *s3WebsiteErrorDocument `xml:"ErrorDocument,omitempty"`
+}
+
+type s3WebsiteRedirect struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+type s3WebsiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+type s3WebsiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// getS3BucketWebsite fetches the website configuration of a bucket. It's
+// nil for a bucket that isn't a website.
+func getS3BucketWebsite(conn *s3Conn, bucket, owner string) (*s3WebsiteConfiguration, error) {
+	var c s3WebsiteConfiguration
+	err := s3SubresourceRequest(conn, "GET", bucket, owner, "website", nil, &c)
+	if err != nil {
+		if s3err, ok := err.(*s3.Error); ok && s3err.Code == "NoSuchWebsiteConfiguration" {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// putS3BucketWebsite replaces the website configuration of a bucket, so
+// it also switches between serving and redirecting. A nil configuration
+// deletes it.
+func putS3BucketWebsite(conn *s3Conn, bucket, owner string, c *s3WebsiteConfiguration) error {
+	if c == nil {
+		return s3SubresourceRequest(conn, "DELETE", bucket, owner, "website", nil, nil)
+	}
+
+	body, err := xml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("Error encoding bucket website: %s", err)
+	}
+
+	return s3SubresourceRequest(conn, "PUT", bucket, owner, "website", body, nil)
+}
+
+// Takes the result of flatmap.Expand for the "website" block and returns
+// the configuration to send to S3, or nil if there isn't one.
+func expandS3Website(configured []interface{}) *s3WebsiteConfiguration {
+	if len(configured) == 0 {
+		return nil
+	}
+
+	data := configured[0].(map[string]interface{})
+	c := new(s3WebsiteConfiguration)
+	if redirect := data["redirect_all_requests_to"].([]interface{}); len(redirect) > 0 {
+		r := redirect[0].(map[string]interface{})
+		c.RedirectAllRequestsTo = &s3WebsiteRedirect{
+			HostName: r["host_name"].(string),
+			Protocol: r["protocol"].(string),
+		}
+
+		return c
+	}
+
+	c.IndexDocument = &s3WebsiteIndexDocument{Suffix: data["index_document"].(string)}
+	if v := data["error_document"].(string); v != "" {
+		c.ErrorDocument = &s3WebsiteErrorDocument{Key: v}
+	}
+
+	return c
+}
+
+// Flattens a website configuration returned by S3 into the "website"
+// block. A bucket that isn't a website has none.
+func flattenS3Website(c *s3WebsiteConfiguration) []map[string]interface{} {
+	if c == nil {
+		return []map[string]interface{}{}
+	}
+
+	result := map[string]interface{}{
+		"index_document":           "",
+		"error_document":           "",
+		"redirect_all_requests_to": []map[string]interface{}{},
+	}
+	if c.RedirectAllRequestsTo != nil {
+		result["redirect_all_requests_to"] = []map[string]interface{}{
+			map[string]interface{}{
+				"host_name": c.RedirectAllRequestsTo.HostName,
+				"protocol":  c.RedirectAllRequestsTo.Protocol,
+			},
+		}
+	}
+	if c.IndexDocument != nil {
+		result["index_document"] = c.IndexDocument.Suffix
+	}
+	if c.ErrorDocument != nil {
+		result["error_document"] = c.ErrorDocument.Key
+	}
+
+	return []map[string]interface{}{result}
+}
+
+func validateS3WebsiteRedirectProtocol(v interface{}, k string) (ws []string, es []error) {
+	switch v.(string) {
+	case "http", "https":
+	default:
+		es = append(es, fmt.Errorf("%s must be http or https, got %q", k, v))
+	}
+
+	return
+}
+
+// validateS3WebsiteConfig checks that a website either serves an index
+// document or redirects all requests, which S3 would otherwise only
+// reject once the bucket has been created.
+func validateS3WebsiteConfig(c *terraform.ResourceConfig) (es []error) {
+	const prefix = "website.0."
+	if _, ok := c.Get("website.0"); !ok {
+		return
+	}
+	if c.IsComputed(prefix+"index_document") || c.IsComputed(prefix+"error_document") {
+		return
+	}
+
+	_, index := c.Get(prefix + "index_document")
+	_, errorDocument := c.Get(prefix + "error_document")
+	_, redirect := c.Get(prefix + "redirect_all_requests_to.0")
+
+	switch {
+	case index && redirect:
+		es = append(es, fmt.Errorf(
+			"website: only one of index_document or redirect_all_requests_to can be set"))
+	case !index && !redirect:
+		es = append(es, fmt.Errorf(
+			"website: one of index_document or redirect_all_requests_to must be set"))
+	case errorDocument && redirect:
+		es = append(es, fmt.Errorf(
+			"website: error_document can't be set with redirect_all_requests_to"))
+	}
+
+	return
+}
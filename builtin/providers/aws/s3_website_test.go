@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestExpandS3Website(t *testing.T) {
+	cases := []struct {
+		Configured []interface{}
+		Expected   string
+	}{
+		{
+			Configured: []interface{}{},
+			Expected:   "",
+		},
+
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"index_document":           "index.html",
+					"error_document":           "error.html",
+					"redirect_all_requests_to": []interface{}{},
+				},
+			},
+			Expected: "<WebsiteConfiguration>" +
+				"<IndexDocument><Suffix>index.html</Suffix></IndexDocument>" +
+				"<ErrorDocument><Key>error.html</Key></ErrorDocument>" +
+				"</WebsiteConfiguration>",
+		},
+
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"index_document": "",
+					"error_document": "",
+					"redirect_all_requests_to": []interface{}{
+						map[string]interface{}{
+							"host_name": "www.example.com",
+							"protocol":  "https",
+						},
+					},
+				},
+			},
+			Expected: "<WebsiteConfiguration><RedirectAllRequestsTo>" +
+				"<HostName>www.example.com</HostName><Protocol>https</Protocol>" +
+				"</RedirectAllRequestsTo></WebsiteConfiguration>",
+		},
+	}
+
+	for i, tc := range cases {
+		c := expandS3Website(tc.Configured)
+		if c == nil {
+			if tc.Expected != "" {
+				t.Fatalf("%d: expected a configuration", i)
+			}
+			continue
+		}
+
+		body, err := xml.Marshal(c)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if string(body) != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, body)
+		}
+	}
+}
+
+func TestFlattenS3Website(t *testing.T) {
+	if actual := flattenS3Website(nil); len(actual) != 0 {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	body := `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <RedirectAllRequestsTo>
+    <HostName>www.example.com</HostName>
+  </RedirectAllRequestsTo>
+</WebsiteConfiguration>`
+
+	var c s3WebsiteConfiguration
+	if err := xml.Unmarshal([]byte(body), &c); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []map[string]interface{}{
+		map[string]interface{}{
+			"index_document": "",
+			"error_document": "",
+			"redirect_all_requests_to": []map[string]interface{}{
+				map[string]interface{}{
+					"host_name": "www.example.com",
+					"protocol":  "",
+				},
+			},
+		},
+	}
+	if actual := flattenS3Website(&c); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestValidateS3WebsiteConfig(t *testing.T) {
+	cases := []struct {
+		Config map[string]interface{}
+		Err    bool
+	}{
+		{
+			Config: map[string]interface{}{},
+			Err:    false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"website": []map[string]interface{}{
+					map[string]interface{}{
+						"index_document": "index.html",
+						"error_document": "error.html",
+					},
+				},
+			},
+			Err: false,
+		},
+
+		{
+			Config: map[string]interface{}{
+				"website": []map[string]interface{}{
+					map[string]interface{}{
+						"redirect_all_requests_to": []map[string]interface{}{
+							map[string]interface{}{
+								"host_name": "www.example.com",
+							},
+						},
+					},
+				},
+			},
+			Err: false,
+		},
+
+		// Neither mode
+		{
+			Config: map[string]interface{}{
+				"website": []map[string]interface{}{
+					map[string]interface{}{
+						"error_document": "error.html",
+					},
+				},
+			},
+			Err: true,
+		},
+
+		// Both modes
+		{
+			Config: map[string]interface{}{
+				"website": []map[string]interface{}{
+					map[string]interface{}{
+						"index_document": "index.html",
+						"redirect_all_requests_to": []map[string]interface{}{
+							map[string]interface{}{
+								"host_name": "www.example.com",
+							},
+						},
+					},
+				},
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		es := validateS3WebsiteConfig(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad: %#v", i, es)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package aws
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/mitchellh/goamz/ec2"
 	"github.com/mitchellh/goamz/elb"
@@ -10,7 +14,8 @@ import (
 )
 
 // Takes the result of flatmap.Expand for an array of listeners and
-// returns ELB API compatible objects
+// returns ELB API compatible objects. Protocols are uppercased, the
+// canonical form the API returns.
 func expandListeners(configured []interface{}) ([]elb.Listener, error) {
 	listeners := make([]elb.Listener, 0, len(configured))
 
@@ -21,21 +26,165 @@ func expandListeners(configured []interface{}) ([]elb.Listener, error) {
 
 		l := elb.Listener{
 			InstancePort:     int64(data["instance_port"].(int)),
-			InstanceProtocol: data["instance_protocol"].(string),
+			InstanceProtocol: strings.ToUpper(data["instance_protocol"].(string)),
 			LoadBalancerPort: int64(data["lb_port"].(int)),
-			Protocol:         data["lb_protocol"].(string),
+			Protocol:         strings.ToUpper(data["lb_protocol"].(string)),
+		}
+
+		// Without an instance port or protocol, instances are sent the
+		// traffic the way it came to the load balancer
+		if l.InstancePort == 0 {
+			l.InstancePort = l.LoadBalancerPort
+		}
+		if l.InstanceProtocol == "" {
+			l.InstanceProtocol = l.Protocol
 		}
 
 		if v, ok := data["ssl_certificate_id"]; ok {
 			l.SSLCertificateId = v.(string)
 		}
 
+		if err := validateListenerProtocols(l); err != nil {
+			return nil, err
+		}
+		if err := validateListenerCertificate(l); err != nil {
+			return nil, err
+		}
+
 		listeners = append(listeners, l)
 	}
 
+	if err := validateListenerPorts(listeners); err != nil {
+		return nil, err
+	}
+
 	return listeners, nil
 }
 
+// validateListenerPorts checks that no two listeners share an lb_port.
+// They are different elements of the listener set, but ELB can only have
+// one listener on a port, so one would silently replace the other.
+func validateListenerPorts(listeners []elb.Listener) error {
+	byPort := make(map[int64]elb.Listener, len(listeners))
+	for _, l := range listeners {
+		if other, ok := byPort[l.LoadBalancerPort]; ok {
+			return fmt.Errorf(
+				"listeners can't share lb_port %d: %s and %s",
+				l.LoadBalancerPort, listenerString(other), listenerString(l))
+		}
+
+		byPort[l.LoadBalancerPort] = l
+	}
+
+	return nil
+}
+
+// listenerString describes a listener for error messages.
+func listenerString(l elb.Listener) string {
+	s := fmt.Sprintf("%s:%d => %s:%d",
+		l.Protocol, l.LoadBalancerPort, l.InstanceProtocol, l.InstancePort)
+	if l.SSLCertificateId != "" {
+		s += fmt.Sprintf(" (%s)", l.SSLCertificateId)
+	}
+
+	return s
+}
+
+// validateListenerProtocols checks that a listener's front and back end
+// protocols are compatible. ELB only passes HTTP(S) to HTTP(S) instances
+// and TCP/SSL to TCP/SSL instances.
+func validateListenerProtocols(l elb.Listener) error {
+	layer := func(p string) string {
+		switch strings.ToLower(p) {
+		case "http", "https":
+			return "http"
+		case "tcp", "ssl":
+			return "tcp"
+		}
+		return ""
+	}
+
+	lb := layer(l.Protocol)
+	if lb == "" {
+		return fmt.Errorf(
+			"listener on lb_port %d: unknown lb_protocol %q, must be one of "+
+				"HTTP, HTTPS, TCP or SSL", l.LoadBalancerPort, l.Protocol)
+	}
+
+	instance := layer(l.InstanceProtocol)
+	if instance == "" {
+		return fmt.Errorf(
+			"listener on lb_port %d: unknown instance_protocol %q, must be one of "+
+				"HTTP, HTTPS, TCP or SSL", l.LoadBalancerPort, l.InstanceProtocol)
+	}
+
+	if lb != instance {
+		return fmt.Errorf(
+			"listener on lb_port %d: lb_protocol %q can't be used with "+
+				"instance_protocol %q. HTTP and HTTPS listeners need an HTTP or "+
+				"HTTPS instance_protocol, TCP and SSL listeners a TCP or SSL one",
+			l.LoadBalancerPort, l.Protocol, l.InstanceProtocol)
+	}
+
+	return nil
+}
+
+// validateListenerCertificate checks that a listener has a certificate
+// if, and only if, it terminates SSL. ELB rejects HTTPS and SSL listeners
+// without one, and silently drops the certificate of HTTP and TCP ones,
+// which would then show up as a diff on every plan.
+func validateListenerCertificate(l elb.Listener) error {
+	switch strings.ToLower(l.Protocol) {
+	case "https", "ssl":
+		if l.SSLCertificateId == "" {
+			return fmt.Errorf(
+				"listener on lb_port %d: lb_protocol %q requires an "+
+					"ssl_certificate_id", l.LoadBalancerPort, l.Protocol)
+		}
+	case "http", "tcp":
+		if l.SSLCertificateId != "" {
+			return fmt.Errorf(
+				"listener on lb_port %d: lb_protocol %q can't have an "+
+					"ssl_certificate_id, only HTTPS and SSL listeners can",
+				l.LoadBalancerPort, l.Protocol)
+		}
+	}
+
+	return nil
+}
+
+// Takes the result of flatmap.Expand for an SSL negotiation policy and
+// returns the ELB API compatible policy attributes. Attributes are sorted
+// by name so the same configuration always produces the same request.
+func expandSslPolicyAttributes(m map[string]interface{}) []elb.PolicyAttribute {
+	var attrs []elb.PolicyAttribute
+
+	if v, ok := m["reference_security_policy"]; ok && v.(string) != "" {
+		attrs = append(attrs, elb.PolicyAttribute{
+			AttributeName:  "Reference-Security-Policy",
+			AttributeValue: v.(string),
+		})
+	}
+
+	if v, ok := m["attributes"]; ok {
+		raw := v.(map[string]interface{})
+		names := make([]string, 0, len(raw))
+		for k := range raw {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		for _, k := range names {
+			attrs = append(attrs, elb.PolicyAttribute{
+				AttributeName:  k,
+				AttributeValue: raw[k].(string),
+			})
+		}
+	}
+
+	return attrs
+}
+
 // Takes the result of flatmap.Expand for an array of ingress/egress
 // security group rules and returns EC2 API compatible objects
 func expandIPPerms(id string, configured []interface{}) []ec2.IPPerm {
@@ -172,16 +321,17 @@ func flattenInstances(list []elb.Instance) []string {
 	return result
 }
 
-// Flattens an array of Listeners into a []map[string]interface{}
+// Flattens an array of Listeners into a []map[string]interface{}, with
+// the protocols in uppercase
 func flattenListeners(list []elb.Listener) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, len(list))
 	for _, i := range list {
 		result = append(result, map[string]interface{}{
 			"instance_port":      i.InstancePort,
-			"instance_protocol":  strings.ToLower(i.InstanceProtocol),
+			"instance_protocol":  strings.ToUpper(i.InstanceProtocol),
 			"ssl_certificate_id": i.SSLCertificateId,
 			"lb_port":            i.LoadBalancerPort,
-			"lb_protocol":        strings.ToLower(i.Protocol),
+			"lb_protocol":        strings.ToUpper(i.Protocol),
 		})
 	}
 	return result
@@ -199,6 +349,21 @@ func flattenParameters(list []rds.Parameter) []map[string]interface{} {
 	return result
 }
 
+// Flattens a []string returned by the API into a *schema.Set hashed with
+// hashcode.String, the same as the string sets in our schemas. Duplicates
+// are dropped and the order the API returned them in doesn't matter.
+func flattenStringSet(list []string) *schema.Set {
+	set := &schema.Set{
+		F: func(v interface{}) int {
+			return hashcode.String(v.(string))
+		},
+	}
+	for _, v := range list {
+		set.Add(v)
+	}
+	return set
+}
+
 // Takes the result of flatmap.Expand for an array of strings
 // and returns a []string
 func expandStringList(configured []interface{}) []string {
@@ -208,3 +373,94 @@ func expandStringList(configured []interface{}) []string {
 	}
 	return vs
 }
+
+// Takes an IAM instance profile given either by name or by full ARN, such
+// as "arn:aws:iam::123456789012:instance-profile/path/name", and returns
+// just the name. Instance profile names are unique within an account, so
+// the name alone is enough to identify it.
+func normalizeIamInstanceProfile(v string) string {
+	if !strings.HasPrefix(v, "arn:") {
+		return v
+	}
+
+	if i := strings.Index(v, ":instance-profile/"); i >= 0 {
+		v = v[i+len(":instance-profile/"):]
+	}
+	if i := strings.LastIndex(v, "/"); i >= 0 {
+		v = v[i+1:]
+	}
+
+	return v
+}
+
+// Takes a JSON document, such as an IAM or bucket policy, and returns it
+// with insignificant whitespace removed and object keys sorted. Documents
+// that mean the same thing normalize to the same string, so the state
+// doesn't show a diff for formatting changes alone.
+func normalizeJsonString(v interface{}) (string, error) {
+	s := v.(string)
+	if s == "" {
+		return "", nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return s, err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return s, err
+	}
+
+	return string(b), nil
+}
+
+// jsonStateFunc is a StateFunc that stores JSON in its normalized form.
+// Invalid JSON is stored as given and rejected by validateJsonString.
+func jsonStateFunc(v interface{}) string {
+	s, _ := normalizeJsonString(v)
+	return s
+}
+
+// upperCaseStateFunc is a StateFunc for values that are stored in
+// uppercase, the way the API returns them, so configuring them in another
+// case is no diff.
+func upperCaseStateFunc(v interface{}) string {
+	return strings.ToUpper(v.(string))
+}
+
+func validateJsonString(v interface{}, k string) (ws []string, es []error) {
+	if _, err := normalizeJsonString(v); err != nil {
+		es = append(es, fmt.Errorf("%s contains invalid JSON: %s", k, err))
+	}
+
+	return
+}
+
+func validateAwsAccountId(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	valid := len(value) == 12
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			valid = false
+		}
+	}
+
+	if !valid {
+		es = append(es, fmt.Errorf(
+			"%s must be a 12 digit AWS account ID, got %q", k, value))
+	}
+
+	return
+}
+
+// boolValue returns the value of a boolean the API may leave out of its
+// response, or def if it did.
+func boolValue(v *bool, def bool) bool {
+	if v == nil {
+		return def
+	}
+
+	return *v
+}
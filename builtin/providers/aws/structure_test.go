@@ -2,6 +2,7 @@ package aws
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/flatmap"
@@ -193,8 +194,8 @@ func Test_expandListeners(t *testing.T) {
 	expected := elb.Listener{
 		InstancePort:     8000,
 		LoadBalancerPort: 80,
-		InstanceProtocol: "http",
-		Protocol:         "http",
+		InstanceProtocol: "HTTP",
+		Protocol:         "HTTP",
 	}
 
 	if !reflect.DeepEqual(listeners[0], expected) {
@@ -206,6 +207,189 @@ func Test_expandListeners(t *testing.T) {
 
 }
 
+func Test_expandListeners_defaults(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"instance_port":      0,
+			"lb_port":            443,
+			"instance_protocol":  "",
+			"lb_protocol":        "ssl",
+			"ssl_certificate_id": "arn:aws:iam::123456789012:server-certificate/foo",
+		},
+	}
+	listeners, err := expandListeners(expanded)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := elb.Listener{
+		InstancePort:     443,
+		LoadBalancerPort: 443,
+		InstanceProtocol: "SSL",
+		Protocol:         "SSL",
+		SSLCertificateId: "arn:aws:iam::123456789012:server-certificate/foo",
+	}
+
+	if !reflect.DeepEqual(listeners[0], expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			listeners[0],
+			expected)
+	}
+}
+
+func Test_flattenListeners(t *testing.T) {
+	// Protocols are stored in uppercase, whatever case they come in
+	cases := []struct {
+		Input  elb.Listener
+		Output map[string]interface{}
+	}{
+		{
+			Input: elb.Listener{
+				InstancePort:     8000,
+				InstanceProtocol: "HTTP",
+				LoadBalancerPort: 80,
+				Protocol:         "HTTP",
+			},
+			Output: map[string]interface{}{
+				"instance_port":      int64(8000),
+				"instance_protocol":  "HTTP",
+				"ssl_certificate_id": "",
+				"lb_port":            int64(80),
+				"lb_protocol":        "HTTP",
+			},
+		},
+		{
+			Input: elb.Listener{
+				InstancePort:     8443,
+				InstanceProtocol: "tcp",
+				LoadBalancerPort: 443,
+				Protocol:         "ssl",
+				SSLCertificateId: "arn:aws:iam::123456789012:server-certificate/foo",
+			},
+			Output: map[string]interface{}{
+				"instance_port":      int64(8443),
+				"instance_protocol":  "TCP",
+				"ssl_certificate_id": "arn:aws:iam::123456789012:server-certificate/foo",
+				"lb_port":            int64(443),
+				"lb_protocol":        "SSL",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		output := flattenListeners([]elb.Listener{tc.Input})
+		if !reflect.DeepEqual(output, []map[string]interface{}{tc.Output}) {
+			t.Fatalf("Input:\n\n%#v\n\nOutput:\n\n%#v", tc.Input, output)
+		}
+	}
+}
+
+func Test_validateListenerProtocols(t *testing.T) {
+	cases := []struct {
+		LB, Instance string
+		Valid        bool
+	}{
+		{"http", "http", true},
+		{"http", "https", true},
+		{"https", "http", true},
+		{"https", "https", true},
+		{"tcp", "tcp", true},
+		{"tcp", "ssl", true},
+		{"ssl", "tcp", true},
+		{"ssl", "ssl", true},
+		{"HTTPS", "HTTP", true},
+		{"http", "tcp", false},
+		{"http", "ssl", false},
+		{"https", "tcp", false},
+		{"https", "ssl", false},
+		{"tcp", "http", false},
+		{"tcp", "https", false},
+		{"ssl", "http", false},
+		{"ssl", "https", false},
+		{"udp", "udp", false},
+		{"http", "", false},
+	}
+
+	for _, tc := range cases {
+		err := validateListenerProtocols(elb.Listener{
+			LoadBalancerPort: 443,
+			Protocol:         tc.LB,
+			InstanceProtocol: tc.Instance,
+		})
+		if tc.Valid && err != nil {
+			t.Fatalf("%s -> %s: unexpected error: %s", tc.LB, tc.Instance, err)
+		}
+		if !tc.Valid && err == nil {
+			t.Fatalf("%s -> %s: expected an error", tc.LB, tc.Instance)
+		}
+	}
+}
+
+func Test_expandListeners_duplicatePort(t *testing.T) {
+	expanded := []interface{}{
+		map[string]interface{}{
+			"instance_port":     8000,
+			"lb_port":           80,
+			"instance_protocol": "http",
+			"lb_protocol":       "http",
+		},
+		map[string]interface{}{
+			"instance_port":     8080,
+			"lb_port":           80,
+			"instance_protocol": "tcp",
+			"lb_protocol":       "tcp",
+		},
+	}
+
+	_, err := expandListeners(expanded)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// Both listeners are named, whichever order the set gave them in
+	for _, l := range []string{"HTTP:80 => HTTP:8000", "TCP:80 => TCP:8080"} {
+		if !strings.Contains(err.Error(), l) {
+			t.Fatalf("error doesn't mention %s: %s", l, err)
+		}
+	}
+}
+
+func Test_expandSslPolicyAttributes(t *testing.T) {
+	expanded := map[string]interface{}{
+		"lb_port":                   443,
+		"name":                      "tf-tls-policy",
+		"reference_security_policy": "ELBSecurityPolicy-2015-02",
+		"attributes": map[string]interface{}{
+			"Protocol-TLSv1.2": "true",
+			"Protocol-SSLv3":   "false",
+		},
+	}
+	attrs := expandSslPolicyAttributes(expanded)
+
+	expected := []elb.PolicyAttribute{
+		elb.PolicyAttribute{
+			AttributeName:  "Reference-Security-Policy",
+			AttributeValue: "ELBSecurityPolicy-2015-02",
+		},
+		elb.PolicyAttribute{
+			AttributeName:  "Protocol-SSLv3",
+			AttributeValue: "false",
+		},
+		elb.PolicyAttribute{
+			AttributeName:  "Protocol-TLSv1.2",
+			AttributeValue: "true",
+		},
+	}
+
+	if !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			attrs,
+			expected)
+	}
+}
+
 func Test_flattenHealthCheck(t *testing.T) {
 	cases := []struct {
 		Input  elb.HealthCheck
@@ -256,6 +440,32 @@ func Test_expandStringList(t *testing.T) {
 
 }
 
+func Test_flattenStringSet(t *testing.T) {
+	configured := []string{"sg-11111", "sg-22222", "sg-33333"}
+	expected := flattenStringSet(configured)
+
+	// The API may return the groups in any order, and sometimes repeats
+	// a group, neither of which should produce a diff.
+	reversed := []string{"sg-33333", "sg-22222", "sg-11111", "sg-22222"}
+	actual := flattenStringSet(reversed)
+
+	if actual.Len() != 3 {
+		t.Fatalf("bad: %#v", actual.List())
+	}
+	if !actual.Equal(expected) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual.List(),
+			expected.List())
+	}
+	if !reflect.DeepEqual(actual.List(), expected.List()) {
+		t.Fatalf(
+			"Got:\n\n%#v\n\nExpected:\n\n%#v\n",
+			actual.List(),
+			expected.List())
+	}
+}
+
 func Test_expandParameters(t *testing.T) {
 	expanded := []interface{}{
 		map[string]interface{}{
@@ -311,3 +521,126 @@ func Test_flattenParameters(t *testing.T) {
 		}
 	}
 }
+
+func Test_normalizeIamInstanceProfile(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Output string
+	}{
+		{
+			Input:  "web-profile",
+			Output: "web-profile",
+		},
+		{
+			Input:  "arn:aws:iam::123456789012:instance-profile/web-profile",
+			Output: "web-profile",
+		},
+		{
+			Input:  "arn:aws:iam::123456789012:instance-profile/app/web/web-profile",
+			Output: "web-profile",
+		},
+		{
+			Input:  "",
+			Output: "",
+		},
+	}
+
+	for _, tc := range cases {
+		output := normalizeIamInstanceProfile(tc.Input)
+		if output != tc.Output {
+			t.Fatalf("%s: got %q, expected %q", tc.Input, output, tc.Output)
+		}
+	}
+}
+
+func Test_normalizeJsonString(t *testing.T) {
+	expected := `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow",` +
+		`"Principal":"*","Resource":"arn:aws:s3:::tf-test-bucket/*"}],` +
+		`"Version":"2012-10-17"}`
+
+	cases := []string{
+		expected,
+
+		// Indented
+		`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::tf-test-bucket/*"
+    }
+  ]
+}`,
+
+		// Keys in a different order
+		`{"Statement":[{"Resource":"arn:aws:s3:::tf-test-bucket/*",` +
+			`"Principal":"*","Effect":"Allow","Action":"s3:GetObject"}],` +
+			`"Version":"2012-10-17"}`,
+	}
+
+	for i, input := range cases {
+		output, err := normalizeJsonString(input)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if output != expected {
+			t.Fatalf("%d: got:\n\n%s\n\nexpected:\n\n%s", i, output, expected)
+		}
+	}
+
+	if output, err := normalizeJsonString(""); err != nil || output != "" {
+		t.Fatalf("empty: got %q, err %v", output, err)
+	}
+
+	if _, err := normalizeJsonString(`{"Version":`); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func Test_validateListenerCertificate(t *testing.T) {
+	cases := []struct {
+		Protocol    string
+		Certificate string
+		Valid       bool
+	}{
+		{"http", "", true},
+		{"tcp", "", true},
+		{"https", "arn:aws:iam::123456789012:server-certificate/foo", true},
+		{"ssl", "arn:aws:iam::123456789012:server-certificate/foo", true},
+		{"HTTPS", "arn:aws:iam::123456789012:server-certificate/foo", true},
+		{"http", "arn:aws:iam::123456789012:server-certificate/foo", false},
+		{"tcp", "arn:aws:iam::123456789012:server-certificate/foo", false},
+		{"https", "", false},
+		{"ssl", "", false},
+	}
+
+	for _, tc := range cases {
+		err := validateListenerCertificate(elb.Listener{
+			LoadBalancerPort: 443,
+			Protocol:         tc.Protocol,
+			SSLCertificateId: tc.Certificate,
+		})
+		if tc.Valid && err != nil {
+			t.Fatalf("%s (%q): unexpected error: %s", tc.Protocol, tc.Certificate, err)
+		}
+		if !tc.Valid && err == nil {
+			t.Fatalf("%s (%q): expected an error", tc.Protocol, tc.Certificate)
+		}
+	}
+}
+
+func TestValidateAwsAccountId(t *testing.T) {
+	for _, v := range []string{"123456789012", "000000000000"} {
+		if _, es := validateAwsAccountId(v, "expected_bucket_owner"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"", "12345678901", "1234567890123", "12345678901a"} {
+		if _, es := validateAwsAccountId(v, "expected_bucket_owner"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
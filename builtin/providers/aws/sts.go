@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+const (
+	stsEndpoint = "https://sts.amazonaws.com"
+	stsVersion  = "2011-06-15"
+
+	// assumeRoleDuration is how long the credentials of an assumed role
+	// are asked to last. An hour is the most AWS allows in every case.
+	assumeRoleDuration = time.Hour
+
+	// assumeRoleRenewal is how long before they expire the credentials of
+	// an assumed role are renewed. A goamz connection keeps the
+	// credentials it was made with, so this is also how long a resource
+	// can keep using one, such as while waiting for a state change.
+	assumeRoleRenewal = 30 * time.Minute
+)
+
+// stsCredentials are the temporary credentials returned by AssumeRole.
+type stsCredentials struct {
+	AccessKeyId     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+// Auth returns the credentials as an aws.Auth for the connections.
+func (c *stsCredentials) Auth() aws.Auth {
+	return aws.Auth{
+		AccessKey: c.AccessKeyId,
+		SecretKey: c.SecretAccessKey,
+		Token:     c.SessionToken,
+	}
+}
+
+// assumeRole obtains temporary credentials for the role, using the
+// credentials the connection is signed with.
+func assumeRole(conn *queryConn, roleARN, sessionName, externalID string) (*stsCredentials, error) {
+	params := map[string]string{
+		"RoleArn":         roleARN,
+		"RoleSessionName": sessionName,
+		"DurationSeconds": strconv.Itoa(int(assumeRoleDuration / time.Second)),
+	}
+	if externalID != "" {
+		params["ExternalId"] = externalID
+	}
+
+	var resp struct {
+		Credentials stsCredentials `xml:"AssumeRoleResult>Credentials"`
+	}
+
+	log.Printf("[INFO] Assuming role %s as session %s", roleARN, sessionName)
+	if err := conn.Request("AssumeRole", params, &resp); err != nil {
+		return nil, fmt.Errorf("Error assuming role %s: %s", roleARN, err)
+	}
+
+	return &resp.Credentials, nil
+}
+
+// assumedRole holds the temporary credentials of an assumed role, and
+// assumes the role again when they are about to expire. It is shared by
+// all the connections and is safe for concurrent use.
+type assumedRole struct {
+	conn        *queryConn
+	roleARN     string
+	sessionName string
+	externalID  string
+
+	sync.Mutex
+	creds *stsCredentials
+}
+
+// Credentials returns the credentials of the role, assuming it again first
+// if they expire within assumeRoleRenewal. Renewed credentials are a new
+// *stsCredentials, so callers can tell them from the ones they have.
+func (r *assumedRole) Credentials() (*stsCredentials, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.creds != nil && time.Now().Add(assumeRoleRenewal).Before(r.creds.Expiration) {
+		return r.creds, nil
+	}
+
+	creds, err := assumeRole(r.conn, r.roleARN, r.sessionName, r.externalID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Assumed role %s until %s", r.roleARN, creds.Expiration)
+
+	r.creds = creds
+	return creds, nil
+}
+
+// signingAuth returns the credentials to sign a request with: those of
+// the assumed role if there is one, which may have been renewed since the
+// connection was made, and auth otherwise.
+func signingAuth(auth aws.Auth, role *assumedRole) (aws.Auth, error) {
+	if role == nil {
+		return auth, nil
+	}
+
+	creds, err := role.Credentials()
+	if err != nil {
+		return aws.Auth{}, err
+	}
+
+	return creds.Auth(), nil
+}
+
+// newSTSConn returns a connection to STS signed with the given
+// credentials. An empty endpoint uses the global STS endpoint.
+func newSTSConn(auth aws.Auth, endpoint string, httpClient *http.Client) *queryConn {
+	if endpoint == "" {
+		endpoint = stsEndpoint
+	}
+
+	return &queryConn{
+		auth:       auth,
+		endpoint:   endpoint,
+		version:    stsVersion,
+		httpClient: httpClient,
+	}
+}
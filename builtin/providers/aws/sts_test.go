@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/goamz/aws"
+)
+
+func TestAssumeRole(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("Action") != "AssumeRole" ||
+			q.Get("RoleArn") != "arn:aws:iam::123456789012:role/terraform" ||
+			q.Get("RoleSessionName") != "terraform" ||
+			q.Get("ExternalId") != "foo" ||
+			q.Get("DurationSeconds") != "3600" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <SessionToken>token</SessionToken>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <Expiration>2015-01-20T23:28:33Z</Expiration>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`)
+	}))
+	defer ts.Close()
+
+	conn := newSTSConn(aws.Auth{AccessKey: "foo", SecretKey: "bar"}, ts.URL, http.DefaultClient)
+	creds, err := assumeRole(conn, "arn:aws:iam::123456789012:role/terraform", "terraform", "foo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := aws.Auth{AccessKey: "ASIAEXAMPLE", SecretKey: "secret", Token: "token"}
+	if creds.Auth() != expected {
+		t.Fatalf("bad: %#v", creds.Auth())
+	}
+
+	if !creds.Expiration.Equal(time.Date(2015, 1, 20, 23, 28, 33, 0, time.UTC)) {
+		t.Fatalf("bad expiration: %s", creds.Expiration)
+	}
+}
+
+// testSTSServer returns an STS that hands out new credentials, with
+// the access key "ASIA<n>" for the n-th AssumeRole, valid for an hour.
+func testSTSServer(t *testing.T, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") != "AssumeRole" {
+			t.Errorf("bad query: %s", r.URL.RawQuery)
+		}
+
+		n := atomic.AddInt32(calls, 1)
+		fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <SessionToken>token%d</SessionToken>
+      <SecretAccessKey>secret%d</SecretAccessKey>
+      <Expiration>%s</Expiration>
+      <AccessKeyId>ASIA%d</AccessKeyId>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, n, n, time.Now().Add(time.Hour).UTC().Format(time.RFC3339), n)
+	}))
+}
+
+// testExpireAssumedRole makes the credentials of the role expire within
+// the renewal window, as if the run had gone on for most of an hour.
+func testExpireAssumedRole(r *assumedRole) {
+	r.Lock()
+	defer r.Unlock()
+	r.creds.Expiration = time.Now().Add(time.Minute)
+}
+
+func TestAssumedRoleCredentials(t *testing.T) {
+	var calls int32
+	ts := testSTSServer(t, &calls)
+	defer ts.Close()
+
+	role := &assumedRole{
+		conn:        newSTSConn(aws.Auth{AccessKey: "foo", SecretKey: "bar"}, ts.URL, http.DefaultClient),
+		roleARN:     "arn:aws:iam::123456789012:role/terraform",
+		sessionName: "terraform",
+	}
+
+	first, err := role.Credentials()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if first.AccessKeyId != "ASIA1" {
+		t.Fatalf("bad: %#v", first)
+	}
+
+	// Credentials that are still good are kept
+	creds, err := role.Credentials()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if creds != first || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("credentials were renewed too early: %#v", creds)
+	}
+
+	testExpireAssumedRole(role)
+
+	creds, err = role.Credentials()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if creds == first || creds.AccessKeyId != "ASIA2" || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("credentials weren't renewed: %#v", creds)
+	}
+}
@@ -2,7 +2,9 @@ package aws
 
 import (
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/mitchellh/goamz/ec2"
 )
@@ -17,6 +19,10 @@ func tagsSchema() *schema.Schema {
 
 // setTags is a helper to set the tags for a resource. It expects the
 // tags field to be named "tags"
+//
+// All of the removed tags are deleted in a single call, and all of the new
+// tags are created in another, so large applies don't issue a request per
+// tag. Both calls are retried while EC2 is throttling us.
 func setTags(conn *ec2.EC2, d *schema.ResourceData) error {
 	if d.HasChange("tags") {
 		oraw, nraw := d.GetChange("tags")
@@ -27,13 +33,21 @@ func setTags(conn *ec2.EC2, d *schema.ResourceData) error {
 		// Set tags
 		if len(remove) > 0 {
 			log.Printf("[DEBUG] Removing tags: %#v", remove)
-			if _, err := conn.DeleteTags([]string{d.Id()}, remove); err != nil {
+			err := retryTagsThrottled(func() error {
+				_, err := conn.DeleteTags([]string{d.Id()}, remove)
+				return err
+			})
+			if err != nil {
 				return err
 			}
 		}
 		if len(create) > 0 {
 			log.Printf("[DEBUG] Creating tags: %#v", create)
-			if _, err := conn.CreateTags([]string{d.Id()}, create); err != nil {
+			err := retryTagsThrottled(func() error {
+				_, err := conn.CreateTags([]string{d.Id()}, create)
+				return err
+			})
+			if err != nil {
 				return err
 			}
 		}
@@ -42,6 +56,13 @@ func setTags(conn *ec2.EC2, d *schema.ResourceData) error {
 	return nil
 }
 
+// retryTagsThrottled retries f for as long as EC2 reports that we are
+// making too many requests.
+func retryTagsThrottled(f resource.RetryFunc) error {
+	return resource.Retry(2*time.Minute,
+		retryOnAwsCodes([]string{"RequestLimitExceeded"}, f))
+}
+
 // diffTags takes our tags locally and the ones remotely and returns
 // the set of tags that must be created, and the set of tags that must
 // be destroyed.
@@ -46,6 +46,33 @@ func TestDiffTags(t *testing.T) {
 				"foo": "bar",
 			},
 		},
+
+		// Multiple adds and removes in one apply
+		{
+			Old: map[string]interface{}{
+				"Name":    "web",
+				"env":     "staging",
+				"owner":   "ops",
+				"service": "api",
+			},
+			New: map[string]interface{}{
+				"Name":    "web",
+				"env":     "production",
+				"team":    "platform",
+				"version": "2",
+			},
+			Create: map[string]string{
+				"Name":    "web",
+				"env":     "production",
+				"team":    "platform",
+				"version": "2",
+			},
+			Remove: map[string]string{
+				"env":     "staging",
+				"owner":   "ops",
+				"service": "api",
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -61,6 +88,36 @@ func TestDiffTags(t *testing.T) {
 	}
 }
 
+func TestRetryTagsThrottled(t *testing.T) {
+	calls := 0
+	err := retryTagsThrottled(func() error {
+		calls++
+		if calls < 3 {
+			return &ec2.Error{Code: "RequestLimitExceeded"}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 3 {
+		t.Fatalf("bad calls: %d", calls)
+	}
+
+	calls = 0
+	err = retryTagsThrottled(func() error {
+		calls++
+		return &ec2.Error{Code: "InvalidID"}
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if calls != 1 {
+		t.Fatalf("bad calls: %d", calls)
+	}
+}
+
 // testAccCheckTags can be used to check the tags on a resource.
 func testAccCheckTags(
 	ts *[]ec2.Tag, key string, value string) resource.TestCheckFunc {
@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// timeoutsSchema returns the schema to use for a "timeouts" block. Each
+// of the given operations (such as "create" or "delete") becomes an
+// optional duration string, for example "10m" or "1h30m".
+func timeoutsSchema(ops ...string) *schema.Schema {
+	s := make(map[string]*schema.Schema)
+	for _, op := range ops {
+		s[op] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateTimeoutDuration,
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: s,
+		},
+	}
+}
+
+// resourceTimeout is a helper to read the timeout for an operation. It
+// expects the timeouts field to be named "timeouts" and returns def if
+// no timeout was configured for the operation.
+func resourceTimeout(
+	d *schema.ResourceData, op string, def time.Duration) (time.Duration, error) {
+	return expandTimeout(d.Get("timeouts").([]interface{}), op, def)
+}
+
+// Takes the result of flatmap.Expand for a "timeouts" block and returns
+// the duration configured for the given operation
+func expandTimeout(
+	configured []interface{}, op string, def time.Duration) (time.Duration, error) {
+	if len(configured) == 0 || configured[0] == nil {
+		return def, nil
+	}
+
+	m := configured[0].(map[string]interface{})
+	raw, ok := m[op]
+	if !ok || raw.(string) == "" {
+		return def, nil
+	}
+
+	timeout, err := time.ParseDuration(raw.(string))
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing %s timeout %q: %s", op, raw, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s timeout must be positive, got %q", op, raw)
+	}
+
+	return timeout, nil
+}
+
+func validateTimeoutDuration(v interface{}, k string) (ws []string, es []error) {
+	timeout, err := time.ParseDuration(v.(string))
+	if err != nil {
+		es = append(es, fmt.Errorf("%s: %s", k, err))
+	} else if timeout <= 0 {
+		es = append(es, fmt.Errorf("%s must be positive, got %q", k, v))
+	}
+
+	return
+}
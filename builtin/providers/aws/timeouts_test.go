@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandTimeout(t *testing.T) {
+	cases := []struct {
+		Configured []interface{}
+		Op         string
+		Expected   time.Duration
+		Err        bool
+	}{
+		// No timeouts block
+		{
+			Configured: nil,
+			Op:         "create",
+			Expected:   2 * time.Minute,
+		},
+
+		// Block without the operation
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"delete": "10m",
+				},
+			},
+			Op:       "create",
+			Expected: 2 * time.Minute,
+		},
+
+		// Configured operation
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"create": "1h30m",
+				},
+			},
+			Op:       "create",
+			Expected: 90 * time.Minute,
+		},
+
+		// Unparseable duration
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"create": "ten minutes",
+				},
+			},
+			Op:  "create",
+			Err: true,
+		},
+
+		// Negative duration
+		{
+			Configured: []interface{}{
+				map[string]interface{}{
+					"create": "-5m",
+				},
+			},
+			Op:  "create",
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := expandTimeout(tc.Configured, tc.Op, 2*time.Minute)
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: bad error: %s", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
+func TestValidateTimeoutDuration(t *testing.T) {
+	for _, v := range []string{"30s", "10m", "1h30m"} {
+		if _, es := validateTimeoutDuration(v, "timeouts.0.create"); len(es) > 0 {
+			t.Fatalf("%q: unexpected errors: %#v", v, es)
+		}
+	}
+
+	for _, v := range []string{"ten minutes", "10", "-5m", "0s"} {
+		if _, es := validateTimeoutDuration(v, "timeouts.0.create"); len(es) == 0 {
+			t.Fatalf("%q: expected an error", v)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// throttleCodes are the error codes AWS uses to report that a request
+// was rejected because of rate limiting.
+var throttleCodes = []string{
+	"Throttling",
+	"ThrottlingException",
+	"RequestLimitExceeded",
+	"RequestThrottled",
+	"SlowDown",
+}
+
+// retryTransport is an http.RoundTripper that retries throttled requests
+// with exponential backoff and jitter. Requests with a body are never
+// retried since the body can't be sent a second time, and network errors
+// are only retried for idempotent methods.
+type retryTransport struct {
+	Transport  http.RoundTripper
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry. It doubles on
+	// every further retry, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// newRetryingHTTPClient returns an http.Client that retries throttled
+// requests up to maxRetries times, and sends userAgent with each of them.
+func newRetryingHTTPClient(maxRetries int, userAgent string) *http.Client {
+	return &http.Client{
+		Transport: &userAgentTransport{
+			Transport: &retryTransport{
+				Transport:  http.DefaultTransport,
+				MaxRetries: maxRetries,
+				BaseDelay:  100 * time.Millisecond,
+				MaxDelay:   20 * time.Second,
+			},
+			UserAgent: userAgent,
+		},
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for try := 0; ; try++ {
+		resp, err := t.Transport.RoundTrip(req)
+		if try >= t.MaxRetries || req.Body != nil || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		delay := t.delay(try)
+		log.Printf(
+			"[DEBUG] Retrying throttled %s %s in %s (retry %d of %d)",
+			req.Method, req.URL.Host, delay, try+1, t.MaxRetries)
+		time.Sleep(delay)
+	}
+}
+
+func (t *retryTransport) shouldRetry(
+	req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Method == "GET" || req.Method == "HEAD"
+	}
+
+	switch resp.StatusCode {
+	case 503:
+		return true
+	case 400:
+		// Throttling is reported as a 400 with the code in the body.
+		// Read the body so we can check it, and put it back so the
+		// caller can still parse the error.
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+
+		for _, code := range throttleCodes {
+			if bytes.Contains(body, []byte("<Code>"+code+"</Code>")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// delay returns a random duration up to the exponential backoff for the
+// given try, so that concurrent requests don't all retry at once.
+func (t *retryTransport) delay(try int) time.Duration {
+	d := t.BaseDelay << uint(try)
+	if d <= 0 || d > t.MaxDelay {
+		d = t.MaxDelay
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// userAgent returns the User-Agent identifying requests made by Terraform,
+// with the configured suffix, if any, appended.
+func userAgent(suffix string) string {
+	ua := fmt.Sprintf("APN/1.0 HashiCorp/1.0 Terraform/%s", terraform.Version)
+	if suffix != "" {
+		ua += " " + suffix
+	}
+
+	return ua
+}
+
+// userAgentTransport is an http.RoundTripper that sets the User-Agent of
+// every request, replacing the one goamz sends.
+type userAgentTransport struct {
+	Transport http.RoundTripper
+	UserAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper mustn't modify the request it's given
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("User-Agent", t.UserAgent)
+
+	return t.Transport.RoundTrip(r)
+}
@@ -0,0 +1,189 @@
+package aws
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := []struct {
+		Status     int
+		Body       string
+		MaxRetries int
+		Method     string
+		Calls      int
+	}{
+		// Throttled, then succeeds
+		{
+			Status:     400,
+			Body:       "<Response><Errors><Error><Code>RequestLimitExceeded</Code></Error></Errors></Response>",
+			MaxRetries: 11,
+			Method:     "GET",
+			Calls:      3,
+		},
+
+		// Service unavailable, then succeeds
+		{
+			Status:     503,
+			Body:       "<Error><Code>SlowDown</Code></Error>",
+			MaxRetries: 11,
+			Method:     "GET",
+			Calls:      3,
+		},
+
+		// Other client errors are returned straight away
+		{
+			Status:     400,
+			Body:       "<Response><Errors><Error><Code>InvalidParameterValue</Code></Error></Errors></Response>",
+			MaxRetries: 11,
+			Method:     "GET",
+			Calls:      1,
+		},
+
+		// Gives up after MaxRetries
+		{
+			Status:     400,
+			Body:       "<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>",
+			MaxRetries: 1,
+			Method:     "GET",
+			Calls:      2,
+		},
+
+		// Requests with a body are never resent
+		{
+			Status:     503,
+			Body:       "<Error><Code>SlowDown</Code></Error>",
+			MaxRetries: 11,
+			Method:     "PUT",
+			Calls:      1,
+		},
+	}
+
+	for i, tc := range cases {
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls < 3 {
+					w.WriteHeader(tc.Status)
+					fmt.Fprint(w, tc.Body)
+					return
+				}
+
+				fmt.Fprint(w, "ok")
+			}))
+
+		client := &http.Client{
+			Transport: &retryTransport{
+				Transport:  http.DefaultTransport,
+				MaxRetries: tc.MaxRetries,
+				BaseDelay:  time.Millisecond,
+				MaxDelay:   10 * time.Millisecond,
+			},
+		}
+
+		var body io.Reader
+		if tc.Method == "PUT" {
+			body = strings.NewReader("data")
+		}
+		req, err := http.NewRequest(tc.Method, ts.URL, body)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		// The body must still be readable by the caller
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		ts.Close()
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if resp.StatusCode != 200 && string(b) != tc.Body {
+			t.Fatalf("%d: bad body: %s", i, b)
+		}
+
+		if calls != tc.Calls {
+			t.Fatalf("%d: expected %d calls, got %d", i, tc.Calls, calls)
+		}
+	}
+}
+
+// headerRecorder is an http.RoundTripper that records the requests it's
+// given instead of sending them.
+type headerRecorder struct {
+	Requests []*http.Request
+}
+
+func (r *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.Requests = append(r.Requests, req)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestUserAgentTransport(t *testing.T) {
+	recorder := new(headerRecorder)
+	client := &http.Client{
+		Transport: &userAgentTransport{
+			Transport: recorder,
+			UserAgent: userAgent("example/1.0"),
+		},
+	}
+
+	req, err := http.NewRequest("GET", "https://ec2.us-east-1.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("User-Agent", "goamz")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if len(recorder.Requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(recorder.Requests))
+	}
+
+	expected := "APN/1.0 HashiCorp/1.0 Terraform/" + terraform.Version + " example/1.0"
+	if v := recorder.Requests[0].Header.Get("User-Agent"); v != expected {
+		t.Fatalf("bad User-Agent: %q", v)
+	}
+
+	// The caller's request is left alone
+	if v := req.Header.Get("User-Agent"); v != "goamz" {
+		t.Fatalf("request was modified: %q", v)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	cases := []struct {
+		Suffix   string
+		Expected string
+	}{
+		{"", "APN/1.0 HashiCorp/1.0 Terraform/" + terraform.Version},
+		{"team-a", "APN/1.0 HashiCorp/1.0 Terraform/" + terraform.Version + " team-a"},
+	}
+
+	for _, tc := range cases {
+		if v := userAgent(tc.Suffix); v != tc.Expected {
+			t.Fatalf("%q: expected %q, got %q", tc.Suffix, tc.Expected, v)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package resource
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// UniqueIdPrefix is the prefix of the IDs returned by UniqueId.
+const UniqueIdPrefix = "terraform-"
+
+// UniqueIdSuffixLength is the length of the random suffix that
+// PrefixedUniqueId adds to its prefix. A resource whose names are limited
+// to N characters can take prefixes of up to N - UniqueIdSuffixLength.
+const UniqueIdSuffixLength = 26
+
+// UniqueId returns a unique ID starting with UniqueIdPrefix.
+func UniqueId() string {
+	return PrefixedUniqueId(UniqueIdPrefix)
+}
+
+// PrefixedUniqueId returns the prefix followed by UniqueIdSuffixLength
+// random characters, for generating the names of resources that need
+// unique ones, such as a replacement created before the resource it
+// replaces is destroyed. The suffix only uses lowercase letters and
+// digits, which every AWS name allows.
+func PrefixedUniqueId(prefix string) string {
+	// 128 bits of randomness, the same as a UUID, encodes to exactly
+	// UniqueIdSuffixLength base32 characters without padding
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("Error reading random bytes: %s", err))
+	}
+
+	suffix := base32.StdEncoding.EncodeToString(b)
+	suffix = strings.ToLower(strings.TrimRight(suffix, "="))
+	return prefix + suffix
+}
@@ -0,0 +1,37 @@
+package resource
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPrefixedUniqueId(t *testing.T) {
+	suffix := regexp.MustCompile("^[a-z2-7]+$")
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 10000; i++ {
+		id := PrefixedUniqueId("foo-")
+		if !strings.HasPrefix(id, "foo-") {
+			t.Fatalf("bad prefix: %s", id)
+		}
+		if len(id) != len("foo-")+UniqueIdSuffixLength {
+			t.Fatalf("bad length: %s", id)
+		}
+		if !suffix.MatchString(id[len("foo-"):]) {
+			t.Fatalf("bad suffix: %s", id)
+		}
+
+		if _, ok := seen[id]; ok {
+			t.Fatalf("duplicate ID: %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func TestUniqueId(t *testing.T) {
+	id := UniqueId()
+	if !strings.HasPrefix(id, UniqueIdPrefix) {
+		t.Fatalf("bad: %s", id)
+	}
+}
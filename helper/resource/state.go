@@ -37,6 +37,7 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 	log.Printf("[DEBUG] Waiting for state to become: %s", conf.Target)
 
 	notfoundTick := 0
+	start := time.Now()
 
 	var result interface{}
 	var resulterr error
@@ -65,10 +66,20 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 			var currentState string
 			result, currentState, err = conf.Refresh()
 			if err != nil {
+				log.Printf(
+					"[DEBUG] Refresh %d failed after %s: %s",
+					tries+1, time.Since(start), err)
 				resulterr = err
 				return
 			}
 
+			// Record every poll, so a wait that never seems to finish shows
+			// what it kept seeing. The log filter drops this unless debug
+			// logging is enabled.
+			log.Printf(
+				"[DEBUG] Refresh %d for target '%s' returned state '%s' after %s (found: %t)",
+				tries+1, conf.Target, currentState, time.Since(start), result != nil)
+
 			// If we're waiting for the absence of a thing, then return
 			if result == nil && conf.Target == "" {
 				return
@@ -52,6 +52,12 @@ type Resource struct {
 	Update UpdateFunc
 	Delete DeleteFunc
 	Exists ExistsFunc
+
+	// ValidateFunc is called after the configuration has been validated
+	// against the schema, and can check things that involve more than one
+	// field. Warnings it returns are shown to the user without failing
+	// the plan. If it isn't set, only the schema is validated.
+	ValidateFunc ResourceValidateFunc
 }
 
 // See Resource documentation.
@@ -69,6 +75,9 @@ type DeleteFunc func(*ResourceData, interface{}) error
 // See Resource documentation.
 type ExistsFunc func(*ResourceData, interface{}) (bool, error)
 
+// See Resource documentation.
+type ResourceValidateFunc func(*terraform.ResourceConfig) ([]string, []error)
+
 // Apply creates, updates, and/or deletes a resource.
 func (r *Resource) Apply(
 	s *terraform.InstanceState,
@@ -134,7 +143,13 @@ func (r *Resource) Diff(
 
 // Validate validates the resource configuration against the schema.
 func (r *Resource) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	return schemaMap(r.Schema).Validate(c)
+	ws, es := schemaMap(r.Schema).Validate(c)
+	if len(es) > 0 || r.ValidateFunc == nil {
+		return ws, es
+	}
+
+	ws2, es2 := r.ValidateFunc(c)
+	return append(ws, ws2...), append(es, es2...)
 }
 
 // Refresh refreshes the state of the resource.
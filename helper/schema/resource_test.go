@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -336,6 +337,68 @@ func TestResourceInternalValidate(t *testing.T) {
 	}
 }
 
+func TestResourceValidate(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+
+			"bar": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+
+		ValidateFunc: func(c *terraform.ResourceConfig) ([]string, []error) {
+			if _, ok := c.Get("foo"); ok {
+				if _, ok := c.Get("bar"); ok {
+					return []string{"foo and bar are both set"}, nil
+				}
+			}
+
+			return nil, nil
+		},
+	}
+
+	cases := []struct {
+		Config   map[string]interface{}
+		Warnings []string
+		Err      bool
+	}{
+		{
+			Config: map[string]interface{}{"foo": "a"},
+		},
+
+		{
+			Config:   map[string]interface{}{"foo": "a", "bar": 1},
+			Warnings: []string{"foo and bar are both set"},
+		},
+
+		// Schema errors skip the resource validation
+		{
+			Config: map[string]interface{}{"foo": "a", "bar": "nope"},
+			Err:    true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		ws, es := r.Validate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: bad errors: %#v", i, es)
+		}
+		if !reflect.DeepEqual(ws, tc.Warnings) {
+			t.Fatalf("%d: bad warnings: %#v", i, ws)
+		}
+	}
+}
+
 func TestResourceRefresh(t *testing.T) {
 	r := &Resource{
 		Schema: map[string]*Schema{
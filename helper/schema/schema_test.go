@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"fmt"
 	"os"
 	"reflect"
 	"testing"
@@ -2199,6 +2200,21 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 			},
 			false,
 		},
+
+		// ValidateFunc on a list
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeString},
+					ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+						return nil, nil
+					},
+				},
+			},
+			true,
+		},
 	}
 
 	for i, tc := range cases {
@@ -2607,6 +2623,88 @@ func TestSchemaMap_Validate(t *testing.T) {
 
 			Err: true,
 		},
+
+		// ValidateFunc gets the decoded value
+		{
+			Schema: map[string]*Schema{
+				"port": &Schema{
+					Type:     TypeInt,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+						if v.(int) > 65535 {
+							return nil, []error{fmt.Errorf("%s: too big", k)}
+						}
+						return nil, nil
+					},
+				},
+			},
+
+			Config: map[string]interface{}{
+				"port": "80",
+			},
+
+			Err: false,
+		},
+
+		// ValidateFunc errors are returned
+		{
+			Schema: map[string]*Schema{
+				"port": &Schema{
+					Type:     TypeInt,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+						if v.(int) > 65535 {
+							return nil, []error{fmt.Errorf("%s: too big", k)}
+						}
+						return nil, nil
+					},
+				},
+			},
+
+			Config: map[string]interface{}{
+				"port": 70000,
+			},
+
+			Err: true,
+		},
+
+		// ValidateFunc warnings are returned
+		{
+			Schema: map[string]*Schema{
+				"name": &Schema{
+					Type:     TypeString,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+						return []string{fmt.Sprintf("%s: deprecated", k)}, nil
+					},
+				},
+			},
+
+			Config: map[string]interface{}{
+				"name": "foo",
+			},
+
+			Warn: true,
+		},
+
+		// ValidateFunc is not called for computed values
+		{
+			Schema: map[string]*Schema{
+				"name": &Schema{
+					Type:     TypeString,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+						return nil, []error{fmt.Errorf("%s: should not be called", k)}
+					},
+				},
+			},
+
+			Config: map[string]interface{}{
+				"name": "${var.foo}",
+			},
+
+			Err: false,
+		},
 	}
 
 	for i, tc := range cases {
@@ -0,0 +1,11 @@
+package terraform
+
+// The main version number that is being run at the moment. It's here
+// rather than in the main package so that providers, which are built as
+// their own binaries, can report it too.
+const Version = "0.3.7"
+
+// A pre-release marker for the version. If this is "" (empty string)
+// then it means that it is a final release. Otherwise, this is a pre-release
+// such as "dev" (in development), "beta", "rc1", etc.
+const VersionPrerelease = "dev"